@@ -0,0 +1,45 @@
+package main
+
+import (
+	"gopher-social-backend-server/cmd/server/api"
+	"gopher-social-backend-server/internal/database"
+	"gopher-social-backend-server/pkg/logger"
+	"os"
+
+	"go.uber.org/zap"
+)
+
+var log = logger.GetLogger()
+
+func main() {
+	postgresDB, err := database.NewPostgresDB()
+	if err != nil {
+		log.Error("failed to connect to the database", zap.Error(err))
+		os.Exit(1)
+	}
+
+	store := api.NewStore(postgresDB)
+	failed := false
+
+	if err := store.PostsStore.RecomputeCounters(); err != nil {
+		log.Error("could not recompute counters", zap.String("model", "Post"), zap.Error(err))
+		failed = true
+	} else {
+		log.Info("counters recomputed successfully", zap.String("model", "Post"))
+	}
+
+	if err := store.CommentsStore.RecomputeCounters(); err != nil {
+		log.Error("could not recompute counters", zap.String("model", "Comment"), zap.Error(err))
+		failed = true
+	} else {
+		log.Info("counters recomputed successfully", zap.String("model", "Comment"))
+	}
+
+	if err := database.CloseDatabase(); err != nil {
+		log.Error("failed to close the database", zap.Error(err))
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -4,10 +4,22 @@ import (
 	"context"
 	"gopher-social-backend-server/cmd/server/api/services/authentication"
 	"gopher-social-backend-server/cmd/server/api/services/comments"
+	"gopher-social-backend-server/cmd/server/api/services/emails"
 	"gopher-social-backend-server/cmd/server/api/services/health"
+	"gopher-social-backend-server/cmd/server/api/services/jobs"
+	"gopher-social-backend-server/cmd/server/api/services/notifications"
 	"gopher-social-backend-server/cmd/server/api/services/posts"
+	"gopher-social-backend-server/cmd/server/api/services/search"
+	"gopher-social-backend-server/cmd/server/api/services/stream"
+	"gopher-social-backend-server/cmd/server/api/services/tags"
+	"gopher-social-backend-server/cmd/server/api/services/users"
+	"gopher-social-backend-server/cmd/server/api/services/webhooks"
 	"gopher-social-backend-server/internal/database"
+	"gopher-social-backend-server/internal/events"
+	"gopher-social-backend-server/internal/hooks"
 	"gopher-social-backend-server/internal/middlewares"
+	"gopher-social-backend-server/internal/pubsub"
+	"gopher-social-backend-server/internal/queue"
 	"gopher-social-backend-server/pkg/logger"
 	"gopher-social-backend-server/pkg/ratelimiter"
 	"net/http"
@@ -23,12 +35,29 @@ import (
 var log = logger.GetLogger()
 
 func (app *Application) mountRoutes(router chi.Router) {
-	health.RegisterHealthRoutes(router, app.Handlers.HealthHandler)
-	authentication.RegisterAuthenticationRoutes(router, app.Handlers.AuthenticationHandler)
+	timeout := middlewares.TimeoutMiddleware(time.Minute)
+
+	router.Group(func(r chi.Router) {
+		r.Use(timeout)
+		health.RegisterHealthRoutes(r, app.Handlers.HealthHandler)
+		authentication.RegisterAuthenticationRoutes(r, app.Handlers.AuthenticationHandler)
+	})
 
 	router.Route("/api/v1", func(r chi.Router) {
-		posts.RegisterPostsRoutes(r, app.Handlers.PostsHandler)
-		comments.RegisterCommentsRoutes(r, app.Handlers.CommentsHandler)
+		r.Group(func(r chi.Router) {
+			r.Use(timeout)
+			posts.RegisterPostsRoutes(r, app.Handlers.PostsHandler)
+			comments.RegisterCommentsRoutes(r, app.Handlers.CommentsHandler)
+			search.RegisterSearchRoutes(r, app.Handlers.SearchHandler)
+			tags.RegisterTagsRoutes(r, app.Handlers.TagsHandler)
+			users.RegisterUsersRoutes(r, app.Handlers.UsersHandler)
+			notifications.RegisterNotificationsRoutes(r, app.Handlers.NotificationsHandler)
+			webhooks.RegisterWebhooksRoutes(r, app.Handlers.WebhooksHandler)
+			jobs.RegisterJobsRoutes(r, app.Handlers.JobsHandler)
+			emails.RegisterEmailsRoutes(r, app.Handlers.EmailsHandler)
+		})
+
+		stream.RegisterStreamRoutes(r, app.Handlers.StreamHandler)
 	})
 }
 
@@ -54,28 +83,116 @@ func (app *Application) makeMigrations() {
 		log.Error("could not migrate model", zap.String("model", "User"), zap.Error(err))
 	}
 
+	if err := database.MigrateModel(&users.Follow{}); err != nil {
+		log.Error("could not migrate model", zap.String("model", "Follow"), zap.Error(err))
+	}
+
+	if err := database.MigrateModel(&users.Block{}); err != nil {
+		log.Error("could not migrate model", zap.String("model", "Block"), zap.Error(err))
+	}
+
 	if err := database.MigrateModel(&posts.Post{}); err != nil {
 		log.Error("could not migrate model", zap.String("model", "Post"), zap.Error(err))
 	}
 
-	if err := database.MigrateModel(&posts.PostLike{}); err != nil {
-		log.Error("could not migrate model", zap.String("model", "PostLike"), zap.Error(err))
+	if err := database.MigrateModel(&posts.PostReaction{}); err != nil {
+		log.Error("could not migrate model", zap.String("model", "PostReaction"), zap.Error(err))
 	}
 
-	if err := database.MigrateModel(&posts.PostDislike{}); err != nil {
-		log.Error("could not migrate model", zap.String("model", "PostDislike"), zap.Error(err))
+	if err := database.MigrateModel(&posts.PostBookmark{}); err != nil {
+		log.Error("could not migrate model", zap.String("model", "PostBookmark"), zap.Error(err))
+	}
+
+	if err := database.MigrateModel(&posts.PostRevision{}); err != nil {
+		log.Error("could not migrate model", zap.String("model", "PostRevision"), zap.Error(err))
+	}
+
+	if err := database.MigrateModel(&posts.PostMention{}); err != nil {
+		log.Error("could not migrate model", zap.String("model", "PostMention"), zap.Error(err))
 	}
 
 	if err := database.MigrateModel(&comments.Comment{}); err != nil {
 		log.Error("could not migrate model", zap.String("model", "Comment"), zap.Error(err))
 	}
 
-	if err := database.MigrateModel(&comments.CommentLike{}); err != nil {
-		log.Error("could not migrate model", zap.String("model", "CommentLike"), zap.Error(err))
+	if err := database.MigrateModel(&comments.CommentReaction{}); err != nil {
+		log.Error("could not migrate model", zap.String("model", "CommentReaction"), zap.Error(err))
+	}
+
+	if err := database.MigrateModel(&comments.CommentRevision{}); err != nil {
+		log.Error("could not migrate model", zap.String("model", "CommentRevision"), zap.Error(err))
+	}
+
+	if err := database.MigrateModel(&comments.CommentMention{}); err != nil {
+		log.Error("could not migrate model", zap.String("model", "CommentMention"), zap.Error(err))
+	}
+
+	if err := database.MigrateModel(&notifications.Notification{}); err != nil {
+		log.Error("could not migrate model", zap.String("model", "Notification"), zap.Error(err))
+	}
+
+	if err := database.MigrateModel(&notifications.NotificationActor{}); err != nil {
+		log.Error("could not migrate model", zap.String("model", "NotificationActor"), zap.Error(err))
+	}
+
+	if err := database.MigrateModel(&notifications.NotificationPreference{}); err != nil {
+		log.Error("could not migrate model", zap.String("model", "NotificationPreference"), zap.Error(err))
 	}
 
-	if err := database.MigrateModel(&comments.CommentDislike{}); err != nil {
-		log.Error("could not migrate model", zap.String("model", "CommentDislike"), zap.Error(err))
+	if err := database.MigrateModel(&pubsub.Event{}); err != nil {
+		log.Error("could not migrate model", zap.String("model", "Event"), zap.Error(err))
+	}
+
+	if err := database.MigrateModel(&events.OutboxEvent{}); err != nil {
+		log.Error("could not migrate model", zap.String("model", "OutboxEvent"), zap.Error(err))
+	}
+
+	if err := database.MigrateModel(&queue.Job{}); err != nil {
+		log.Error("could not migrate model", zap.String("model", "Job"), zap.Error(err))
+	}
+
+	if err := database.MigrateModel(&hooks.Webhook{}); err != nil {
+		log.Error("could not migrate model", zap.String("model", "Webhook"), zap.Error(err))
+	}
+
+	if err := database.MigrateModel(&hooks.Delivery{}); err != nil {
+		log.Error("could not migrate model", zap.String("model", "Delivery"), zap.Error(err))
+	}
+
+	if err := database.MigrateModel(&tags.Tag{}); err != nil {
+		log.Error("could not migrate model", zap.String("model", "Tag"), zap.Error(err))
+	}
+
+	if err := database.MigrateModel(&tags.PostTag{}); err != nil {
+		log.Error("could not migrate model", zap.String("model", "PostTag"), zap.Error(err))
+	}
+
+	if err := database.RunMigrations(posts.Migrations...); err != nil {
+		log.Error("could not run migrations", zap.String("service", "posts"), zap.Error(err))
+	}
+
+	if err := database.RunMigrations(comments.Migrations...); err != nil {
+		log.Error("could not run migrations", zap.String("service", "comments"), zap.Error(err))
+	}
+
+	if err := database.RunMigrations(search.Migrations...); err != nil {
+		log.Error("could not run migrations", zap.String("service", "search"), zap.Error(err))
+	}
+
+	if err := search.SyncLanguage(app.PostgresDB); err != nil {
+		log.Error("could not sync search language", zap.String("language", search.SEARCH_LANGUAGE), zap.Error(err))
+	}
+
+	if err := database.RunMigrations(tags.Migrations...); err != nil {
+		log.Error("could not run migrations", zap.String("service", "tags"), zap.Error(err))
+	}
+
+	if err := database.RunMigrations(users.Migrations...); err != nil {
+		log.Error("could not run migrations", zap.String("service", "users"), zap.Error(err))
+	}
+
+	if err := database.RunMigrations(webhooks.Migrations...); err != nil {
+		log.Error("could not run migrations", zap.String("service", "webhooks"), zap.Error(err))
 	}
 }
 
@@ -97,7 +214,6 @@ func (app *Application) configureRouter() *chi.Mux {
 
 	rateLimiter := ratelimiter.NewRateLimiter(time.Second)
 	router.Use(middlewares.RateLimiterMiddleware(rateLimiter))
-	router.Use(middlewares.TimeoutMiddleware(time.Minute))
 
 	app.makeMigrations()
 	app.mountRoutes(router)
@@ -116,6 +232,20 @@ func (app *Application) Run() {
 		IdleTimeout:  2 * time.Minute,
 	}
 
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	jobQueue := app.newJobQueue()
+	queueStopped := make(chan struct{})
+	go func() {
+		jobQueue.Run(jobsCtx)
+		close(queueStopped)
+	}()
+
+	go app.runWebhookJob(jobsCtx)
+	go app.runOutboxJob(jobsCtx, app.newEventBus())
+	go app.Handlers.StreamHandler.Hub.Listen(jobsCtx, database.ConnectionString(), app.Store.StreamStore, STREAM_RECONNECT_DELAY)
+
 	go func() {
 		log.Info("starting server", zap.String("address", app.Config.Address))
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
@@ -128,6 +258,8 @@ func (app *Application) Run() {
 
 	<-quit
 
+	stopJobs()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -136,4 +268,11 @@ func (app *Application) Run() {
 	} else {
 		log.Info("server shutdown gracefully")
 	}
+
+	select {
+	case <-queueStopped:
+		log.Info("job queue stopped")
+	case <-ctx.Done():
+		log.Warn("job queue did not stop in time, running jobs will be retried after their lease expires")
+	}
 }
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"gopher-social-backend-server/cmd/server/api/services/comments"
+	"gopher-social-backend-server/cmd/server/api/services/posts"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestRecomputeRepliesCountKeepsNestedPlaceholders(t *testing.T) {
+	app := newTestApp(t)
+
+	author := createTestUser(t, app, "author")
+	post := &posts.Post{AuthorID: author.ID, Title: "Post", Content: "Body"}
+	if err := app.Store.PostsStore.CreatePost(post, nil, nil); err != nil {
+		t.Fatalf("could not create post: %v", err)
+	}
+
+	reply := func(name string, parent *comments.Comment) *comments.Comment {
+		comment := &comments.Comment{PostID: post.ID, AuthorID: author.ID, Content: name}
+		if parent != nil {
+			comment.ParentID = &parent.ID
+			comment.Depth = parent.Depth + 1
+		}
+		if err := app.Store.CommentsStore.CreateComment(comment, nil); err != nil {
+			t.Fatalf("could not create %s: %v", name, err)
+		}
+		return comment
+	}
+
+	// root -> child (deleted) -> grandchild (deleted) -> leaf
+	//      -> sibling (deleted, no replies)
+	root := reply("root", nil)
+	child := reply("child", root)
+	grandchild := reply("grandchild", child)
+	reply("leaf", grandchild)
+	sibling := reply("sibling", root)
+
+	for _, comment := range []*comments.Comment{grandchild, child, sibling} {
+		if err := app.Store.CommentsStore.DeleteComment(comment.ID, author.ID, ""); err != nil {
+			t.Fatalf("could not delete %s: %v", comment.Content, err)
+		}
+	}
+
+	want := map[uuid.UUID]int64{root.ID: 1, child.ID: 1, grandchild.ID: 1, sibling.ID: 0}
+
+	repliesCounts := func() map[uuid.UUID]int64 {
+		var rows []struct {
+			ID           uuid.UUID
+			RepliesCount int64
+		}
+		if err := app.PostgresDB.Raw("SELECT id, replies_count FROM comments WHERE post_id = ?", post.ID).Scan(&rows).Error; err != nil {
+			t.Fatalf("could not load replies counts: %v", err)
+		}
+
+		counts := make(map[uuid.UUID]int64, len(rows))
+		for _, row := range rows {
+			counts[row.ID] = row.RepliesCount
+		}
+		return counts
+	}
+
+	expectCounts := func(stage string) {
+		t.Helper()
+
+		counts := repliesCounts()
+		for id, count := range want {
+			if counts[id] != count {
+				t.Errorf("%s: replies_count of %s = %d, want %d", stage, id, counts[id], count)
+			}
+		}
+	}
+
+	expectCounts("after deleting")
+
+	if err := app.PostgresDB.Exec("UPDATE comments SET replies_count = 0 WHERE post_id = ?", post.ID).Error; err != nil {
+		t.Fatalf("could not reset replies counts: %v", err)
+	}
+	if err := app.Store.CommentsStore.RecomputeCounters(); err != nil {
+		t.Fatalf("could not recompute counters: %v", err)
+	}
+
+	expectCounts("after recomputing")
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"gopher-social-backend-server/cmd/server/api/services/authentication"
+	"gopher-social-backend-server/internal/database"
+	"gopher-social-backend-server/pkg/mailer"
+	"gopher-social-backend-server/pkg/utils"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestApp migrates a throwaway schema in the database named by
+// TEST_DATABASE_URL and wires an Application against it. Tests that need
+// Postgres are skipped when the variable is not set.
+func newTestApp(t *testing.T) *Application {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	adminDB, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("could not connect to the test database: %v", err)
+	}
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	for _, statement := range []string{
+		`CREATE EXTENSION IF NOT EXISTS "uuid-ossp" WITH SCHEMA public`,
+		`DO $$
+		BEGIN
+			IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'user_role') THEN
+				CREATE TYPE public.user_role AS ENUM ('user', 'staff', 'admin');
+			END IF;
+		END $$`,
+		"CREATE SCHEMA " + schema,
+	} {
+		if err := adminDB.Exec(statement).Error; err != nil {
+			t.Fatalf("could not prepare the test database: %v", err)
+		}
+	}
+
+	postgresDB, err := gorm.Open(postgres.Open(withSearchPath(dsn, schema+",public")), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatalf("could not connect to the test schema: %v", err)
+	}
+
+	sqlDB, err := postgresDB.DB()
+	if err != nil {
+		t.Fatalf("could not get the test connection pool: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(10)
+
+	previousDB := database.PostgresDB
+	database.PostgresDB = postgresDB
+
+	t.Cleanup(func() {
+		database.PostgresDB = previousDB
+
+		sqlDB.Close()
+
+		if err := adminDB.Exec("DROP SCHEMA " + schema + " CASCADE").Error; err != nil {
+			t.Errorf("could not drop the test schema: %v", err)
+		}
+
+		if adminSQL, err := adminDB.DB(); err == nil {
+			adminSQL.Close()
+		}
+	})
+
+	emailTemplates, err := mailer.NewRegistry()
+	if err != nil {
+		t.Fatalf("could not load the email templates: %v", err)
+	}
+
+	store := NewStore(postgresDB)
+	app := &Application{
+		Store:          store,
+		Handlers:       NewHandlers(store, emailTemplates),
+		PostgresDB:     postgresDB,
+		Mailer:         mailer.NewMemoryMailer(),
+		EmailTemplates: emailTemplates,
+	}
+
+	app.makeMigrations()
+
+	return app
+}
+
+func withSearchPath(dsn, searchPath string) string {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err == nil {
+			query := u.Query()
+			query.Set("search_path", searchPath)
+			u.RawQuery = query.Encode()
+			return u.String()
+		}
+	}
+
+	return dsn + " search_path=" + searchPath
+}
+
+// testRouter mounts the API without the global per-IP rate limiter so tests
+// can issue requests back to back.
+func (app *Application) testRouter() http.Handler {
+	router := chi.NewRouter()
+	app.mountRoutes(router)
+	return router
+}
+
+func createTestUser(t *testing.T, app *Application, name string) *authentication.User {
+	t.Helper()
+
+	user := &authentication.User{
+		FirstName:   name,
+		LastName:    "Tester",
+		Email:       fmt.Sprintf("%s-%d@example.com", name, time.Now().UnixNano()),
+		Password:    "not-a-real-hash",
+		IsActivated: true,
+	}
+
+	if err := app.Store.AuthenticationStore.CreateUser(user); err != nil {
+		t.Fatalf("could not create user %s: %v", name, err)
+	}
+
+	return user
+}
+
+// serve sends a request through the router, authenticated as viewer when it
+// is not nil.
+func serve(t *testing.T, router http.Handler, method, path string, viewer *authentication.User, body any) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var payload bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&payload).Encode(body); err != nil {
+			t.Fatalf("could not encode request body: %v", err)
+		}
+	}
+
+	r := httptest.NewRequest(method, path, &payload)
+	r.Header.Set("Content-Type", "application/json")
+	if viewer != nil {
+		token, _ := utils.GenerateAccessToken(viewer.ID.String())
+		r.AddCookie(&http.Cookie{Name: "AuthToken", Value: token})
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+	return w
+}
+
+func decodePage(t *testing.T, w *httptest.ResponseRecorder) []map[string]any {
+	t.Helper()
+
+	var page struct {
+		Data []map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatalf("could not decode page %q: %v", w.Body.String(), err)
+	}
+
+	return page.Data
+}
@@ -0,0 +1,252 @@
+package api
+
+import (
+	"context"
+	"gopher-social-backend-server/cmd/server/api/services/authentication"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/mailer"
+	"gopher-social-backend-server/pkg/utils"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type fakeAuthenticationStore struct {
+	authentication.AuthenticationStore
+	users map[string]*authentication.User
+}
+
+func (s *fakeAuthenticationStore) GetUserByID(id string) (*authentication.User, error) {
+	user, ok := s.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return user, nil
+}
+
+func newMailApp(t *testing.T, users ...*authentication.User) (*Application, *mailer.MemoryMailer) {
+	t.Helper()
+
+	emailTemplates, err := mailer.NewRegistry()
+	if err != nil {
+		t.Fatalf("could not load the email templates: %v", err)
+	}
+
+	store := &fakeAuthenticationStore{users: make(map[string]*authentication.User)}
+	for _, user := range users {
+		store.users[user.ID.String()] = user
+	}
+
+	memoryMailer := mailer.NewMemoryMailer()
+	return &Application{
+		Store:          &Store{AuthenticationStore: store},
+		Mailer:         memoryMailer,
+		EmailTemplates: emailTemplates,
+	}, memoryMailer
+}
+
+// linkToken returns the token at the end of the first link under path in
+// both the HTML and the text part of message.
+func linkToken(t *testing.T, message mailer.Message, path string) string {
+	t.Helper()
+
+	pattern := regexp.MustCompile(regexp.QuoteMeta(mailer.APP_URL+path) + `([A-Za-z0-9_.-]+)`)
+
+	htmlMatch := pattern.FindStringSubmatch(message.HTML)
+	if htmlMatch == nil {
+		t.Fatalf("no %s link in the HTML part:\n%s", path, message.HTML)
+	}
+	textMatch := pattern.FindStringSubmatch(message.Text)
+	if textMatch == nil {
+		t.Fatalf("no %s link in the text part:\n%s", path, message.Text)
+	}
+	if htmlMatch[1] != textMatch[1] {
+		t.Fatalf("the HTML and text parts link different tokens")
+	}
+
+	return htmlMatch[1]
+}
+
+func TestSendEmailRendersEachKind(t *testing.T) {
+	english := &authentication.User{ID: uuid.New(), Email: "ada@example.com", Locale: "en"}
+	spanish := &authentication.User{ID: uuid.New(), Email: "grace@example.com", Locale: "es"}
+	app, memoryMailer := newMailApp(t, english, spanish)
+
+	tests := []struct {
+		name    string
+		user    *authentication.User
+		kind    constants.EmailKind
+		subject string
+		verify  func(t *testing.T, message mailer.Message, userID string)
+	}{
+		{
+			name:    "activation",
+			user:    english,
+			kind:    constants.EmailActivation,
+			subject: "Activate Your Account",
+			verify: func(t *testing.T, message mailer.Message, userID string) {
+				subject, err := utils.VerifyActivationToken(linkToken(t, message, "/auth/activate/"))
+				if err != nil || subject != userID {
+					t.Fatalf("activation link is for %q (%v), want %q", subject, err, userID)
+				}
+			},
+		},
+		{
+			name:    "activation in the user's locale",
+			user:    spanish,
+			kind:    constants.EmailActivation,
+			subject: "Activa tu cuenta",
+			verify: func(t *testing.T, message mailer.Message, userID string) {
+				linkToken(t, message, "/auth/activate/")
+			},
+		},
+		{
+			name:    "account activated",
+			user:    english,
+			kind:    constants.EmailAccountActivated,
+			subject: "Account Activated",
+		},
+		{
+			name:    "password reset",
+			user:    english,
+			kind:    constants.EmailPasswordReset,
+			subject: "Reset Your Password",
+			verify: func(t *testing.T, message mailer.Message, userID string) {
+				subject, err := utils.VerifyPasswordResetToken(linkToken(t, message, "/auth/reset-password/"))
+				if err != nil || subject != userID {
+					t.Fatalf("reset link is for %q (%v), want %q", subject, err, userID)
+				}
+			},
+		},
+		{
+			name:    "password changed",
+			user:    english,
+			kind:    constants.EmailPasswordChanged,
+			subject: "Password Changed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memoryMailer.Reset()
+
+			payload := sendEmailJob{Kind: tt.kind, UserID: tt.user.ID, Email: tt.user.Email}
+			if err := app.sendEmail(context.Background(), payload); err != nil {
+				t.Fatalf("could not send email: %v", err)
+			}
+
+			messages := memoryMailer.Messages()
+			if len(messages) != 1 {
+				t.Fatalf("expected 1 message, got %d", len(messages))
+			}
+
+			message := messages[0]
+			if message.To != tt.user.Email {
+				t.Errorf("to = %q, want %q", message.To, tt.user.Email)
+			}
+			if message.Subject != tt.subject {
+				t.Errorf("subject = %q, want %q", message.Subject, tt.subject)
+			}
+			if message.HTML == "" || message.Text == "" {
+				t.Errorf("expected both an HTML and a text part")
+			}
+			if tt.verify != nil {
+				tt.verify(t, message, tt.user.ID.String())
+			}
+		})
+	}
+}
+
+// deliverEmails dispatches pending outbox events and runs the send_email jobs
+// they queue, returning the messages sent.
+func deliverEmails(t *testing.T, app *Application, memoryMailer *mailer.MemoryMailer) []mailer.Message {
+	t.Helper()
+
+	memoryMailer.Reset()
+	app.dispatchEvents(context.Background(), app.newEventBus())
+
+	jobs, err := app.Store.JobsStore.ClaimJobs(constants.JobSendEmail, time.Now(), time.Hour, 50)
+	if err != nil {
+		t.Fatalf("could not claim email jobs: %v", err)
+	}
+
+	for _, job := range jobs {
+		var payload sendEmailJob
+		if err := job.Decode(&payload); err != nil {
+			t.Fatalf("could not decode email job: %v", err)
+		}
+		if err := app.sendEmail(context.Background(), payload); err != nil {
+			t.Fatalf("could not send %s email: %v", payload.Kind, err)
+		}
+	}
+
+	return memoryMailer.Messages()
+}
+
+func expectOneEmail(t *testing.T, messages []mailer.Message, to, subject string) mailer.Message {
+	t.Helper()
+
+	if len(messages) != 1 {
+		t.Fatalf("expected 1 email, got %d: %+v", len(messages), messages)
+	}
+	if messages[0].To != to {
+		t.Fatalf("to = %q, want %q", messages[0].To, to)
+	}
+	if messages[0].Subject != subject {
+		t.Fatalf("subject = %q, want %q", messages[0].Subject, subject)
+	}
+	return messages[0]
+}
+
+func TestAuthenticationEmailFlows(t *testing.T) {
+	app := newTestApp(t)
+	memoryMailer := app.Mailer.(*mailer.MemoryMailer)
+	router := app.testRouter()
+
+	email := strings.ToLower(uuid.NewString()[:8]) + "@example.com"
+	w := serve(t, router, http.MethodPost, "/auth/register", nil, map[string]string{
+		"first_name":       "Ada",
+		"last_name":        "Lovelace",
+		"email":            email,
+		"password":         "correct horse",
+		"confirm_password": "correct horse",
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("register: expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	activation := expectOneEmail(t, deliverEmails(t, app, memoryMailer), email, "Activate Your Account")
+	activationToken := linkToken(t, activation, "/auth/activate/")
+
+	w = serve(t, router, http.MethodGet, "/auth/activate/"+activationToken, nil, nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("activate: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	expectOneEmail(t, deliverEmails(t, app, memoryMailer), email, "Account Activated")
+
+	w = serve(t, router, http.MethodPost, "/auth/forgot-password", nil, map[string]string{"email": email})
+	if w.Code != http.StatusOK {
+		t.Fatalf("forgot password: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	reset := expectOneEmail(t, deliverEmails(t, app, memoryMailer), email, "Reset Your Password")
+	resetToken := linkToken(t, reset, "/auth/reset-password/")
+
+	w = serve(t, router, http.MethodPost, "/auth/reset-password/"+resetToken, nil, map[string]string{
+		"password":         "battery staple",
+		"confirm_password": "battery staple",
+	})
+	if w.Code != http.StatusOK {
+		t.Fatalf("reset password: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	expectOneEmail(t, deliverEmails(t, app, memoryMailer), email, "Password Changed")
+
+	w = serve(t, router, http.MethodPost, "/auth/login", nil, map[string]string{"email": email, "password": "battery staple"})
+	if w.Code != http.StatusOK {
+		t.Fatalf("login with the new password: expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -3,8 +3,18 @@ package api
 import (
 	"gopher-social-backend-server/cmd/server/api/services/authentication"
 	"gopher-social-backend-server/cmd/server/api/services/comments"
+	"gopher-social-backend-server/cmd/server/api/services/emails"
 	"gopher-social-backend-server/cmd/server/api/services/health"
+	"gopher-social-backend-server/cmd/server/api/services/jobs"
+	"gopher-social-backend-server/cmd/server/api/services/notifications"
 	"gopher-social-backend-server/cmd/server/api/services/posts"
+	"gopher-social-backend-server/cmd/server/api/services/search"
+	"gopher-social-backend-server/cmd/server/api/services/stream"
+	"gopher-social-backend-server/cmd/server/api/services/tags"
+	"gopher-social-backend-server/cmd/server/api/services/users"
+	"gopher-social-backend-server/cmd/server/api/services/webhooks"
+	"gopher-social-backend-server/internal/pubsub"
+	"gopher-social-backend-server/pkg/mailer"
 )
 
 type Handlers struct {
@@ -12,13 +22,29 @@ type Handlers struct {
 	AuthenticationHandler *authentication.AuthenticationHandler
 	PostsHandler          *posts.PostsHandler
 	CommentsHandler       *comments.CommentsHandler
+	SearchHandler         *search.SearchHandler
+	TagsHandler           *tags.TagsHandler
+	UsersHandler          *users.UsersHandler
+	NotificationsHandler  *notifications.NotificationsHandler
+	StreamHandler         *stream.StreamHandler
+	WebhooksHandler       *webhooks.WebhooksHandler
+	JobsHandler           *jobs.JobsHandler
+	EmailsHandler         *emails.EmailsHandler
 }
 
-func NewHandlers(store *Store) *Handlers {
+func NewHandlers(store *Store, emailTemplates *mailer.Registry) *Handlers {
 	return &Handlers{
 		HealthHandler:         &health.HealthHandler{},
 		AuthenticationHandler: &authentication.AuthenticationHandler{AuthenticationStore: store.AuthenticationStore},
-		PostsHandler:          &posts.PostsHandler{PostsStore: store.PostsStore, AuthenticationStore: store.AuthenticationStore},
-		CommentsHandler:       &comments.CommentsHandler{CommentsStore: store.CommentsStore, PostsStore: store.PostsStore, AuthenticationStore: store.AuthenticationStore},
+		PostsHandler:          &posts.PostsHandler{PostsStore: store.PostsStore, AuthenticationStore: store.AuthenticationStore, TagsStore: store.TagsStore, UsersStore: store.UsersStore},
+		CommentsHandler:       &comments.CommentsHandler{CommentsStore: store.CommentsStore, PostsStore: store.PostsStore, AuthenticationStore: store.AuthenticationStore, UsersStore: store.UsersStore},
+		SearchHandler:         &search.SearchHandler{SearchStore: store.SearchStore},
+		TagsHandler:           &tags.TagsHandler{TagsStore: store.TagsStore},
+		UsersHandler:          &users.UsersHandler{UsersStore: store.UsersStore, AuthenticationStore: store.AuthenticationStore},
+		NotificationsHandler:  &notifications.NotificationsHandler{NotificationsStore: store.NotificationsStore},
+		StreamHandler:         &stream.StreamHandler{StreamStore: store.StreamStore, PostsStore: store.PostsStore, AuthenticationStore: store.AuthenticationStore, Hub: pubsub.NewHub()},
+		WebhooksHandler:       &webhooks.WebhooksHandler{WebhooksStore: store.WebhooksStore, AuthenticationStore: store.AuthenticationStore},
+		JobsHandler:           &jobs.JobsHandler{JobsStore: store.JobsStore, AuthenticationStore: store.AuthenticationStore},
+		EmailsHandler:         &emails.EmailsHandler{EmailTemplates: emailTemplates, AuthenticationStore: store.AuthenticationStore},
 	}
 }
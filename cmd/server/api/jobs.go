@@ -0,0 +1,428 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"gopher-social-backend-server/cmd/server/api/services/authentication"
+	"gopher-social-backend-server/internal/events"
+	"gopher-social-backend-server/internal/hooks"
+	"gopher-social-backend-server/internal/queue"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/mailer"
+	"gopher-social-backend-server/pkg/utils"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+var SOFT_DELETE_RETENTION = utils.GetEnvAsDuration("SOFT_DELETE_RETENTION", constants.DefaultSoftDeleteRetention)
+var PURGE_INTERVAL = utils.GetEnvAsDuration("PURGE_INTERVAL", constants.DefaultPurgeInterval)
+var PUBLISH_INTERVAL = utils.GetEnvAsDuration("PUBLISH_INTERVAL", constants.DefaultPublishInterval)
+var STREAM_EVENT_RETENTION = utils.GetEnvAsDuration("STREAM_EVENT_RETENTION", constants.DefaultStreamEventRetention)
+var STREAM_RECONNECT_DELAY = utils.GetEnvAsDuration("STREAM_RECONNECT_DELAY", constants.DefaultStreamReconnectDelay)
+var WEBHOOK_INTERVAL = utils.GetEnvAsDuration("WEBHOOK_INTERVAL", constants.DefaultWebhookInterval)
+var WEBHOOK_TIMEOUT = utils.GetEnvAsDuration("WEBHOOK_TIMEOUT", constants.DefaultWebhookTimeout)
+var WEBHOOK_MAX_ATTEMPTS = utils.GetEnvAsInt("WEBHOOK_MAX_ATTEMPTS", constants.DefaultWebhookMaxAttempts)
+var WEBHOOK_BACKOFF_BASE = utils.GetEnvAsDuration("WEBHOOK_BACKOFF_BASE", constants.DefaultWebhookBackoffBase)
+var WEBHOOK_BACKOFF_MAX = utils.GetEnvAsDuration("WEBHOOK_BACKOFF_MAX", constants.DefaultWebhookBackoffMax)
+var WEBHOOK_DELIVERY_RETENTION = utils.GetEnvAsDuration("WEBHOOK_DELIVERY_RETENTION", constants.DefaultWebhookDeliveryRetention)
+var OUTBOX_INTERVAL = utils.GetEnvAsDuration("OUTBOX_INTERVAL", constants.DefaultOutboxInterval)
+var OUTBOX_LEASE = utils.GetEnvAsDuration("OUTBOX_LEASE", constants.DefaultOutboxLease)
+var OUTBOX_MAX_ATTEMPTS = utils.GetEnvAsInt("OUTBOX_MAX_ATTEMPTS", constants.DefaultOutboxMaxAttempts)
+var OUTBOX_BACKOFF_BASE = utils.GetEnvAsDuration("OUTBOX_BACKOFF_BASE", constants.DefaultOutboxBackoffBase)
+var OUTBOX_BACKOFF_MAX = utils.GetEnvAsDuration("OUTBOX_BACKOFF_MAX", constants.DefaultOutboxBackoffMax)
+var OUTBOX_RETENTION = utils.GetEnvAsDuration("OUTBOX_RETENTION", constants.DefaultOutboxRetention)
+var JOB_POLL_INTERVAL = utils.GetEnvAsDuration("JOB_POLL_INTERVAL", constants.DefaultJobPollInterval)
+var JOB_LEASE = utils.GetEnvAsDuration("JOB_LEASE", constants.DefaultJobLease)
+var JOB_TIMEOUT = utils.GetEnvAsDuration("JOB_TIMEOUT", constants.DefaultJobTimeout)
+var JOB_MAX_ATTEMPTS = utils.GetEnvAsInt("JOB_MAX_ATTEMPTS", constants.DefaultJobMaxAttempts)
+var JOB_CONCURRENCY = utils.GetEnvAsInt("JOB_CONCURRENCY", constants.DefaultJobConcurrency)
+var JOB_BACKOFF_BASE = utils.GetEnvAsDuration("JOB_BACKOFF_BASE", constants.DefaultJobBackoffBase)
+var JOB_BACKOFF_MAX = utils.GetEnvAsDuration("JOB_BACKOFF_MAX", constants.DefaultJobBackoffMax)
+var JOB_RETENTION = utils.GetEnvAsDuration("JOB_RETENTION", constants.DefaultJobRetention)
+var DIGEST_SCHEDULE = utils.GetEnvAsString("DIGEST_SCHEDULE", constants.DefaultDigestSchedule)
+
+const purgeLockID = 72613902
+
+func (app *Application) purgeDeleted(ctx context.Context, job queue.Job) error {
+	return app.PostgresDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", purgeLockID).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			log.Info("purge is already running on another instance")
+			return nil
+		}
+
+		app.purge()
+		return nil
+	})
+}
+
+func (app *Application) purge() {
+	before := time.Now().Add(-SOFT_DELETE_RETENTION)
+
+	purgedPosts, err := app.Store.PostsStore.PurgeDeletedPosts(before)
+	if err != nil {
+		log.Warn("could not purge deleted posts", zap.Error(err))
+	} else if purgedPosts > 0 {
+		log.Info("purged deleted posts", zap.Int64("count", purgedPosts))
+	}
+
+	purgedComments, err := app.Store.CommentsStore.PurgeDeletedComments(before)
+	if err != nil {
+		log.Warn("could not purge deleted comments", zap.Error(err))
+	} else if purgedComments > 0 {
+		log.Info("purged deleted comments", zap.Int64("count", purgedComments))
+	}
+
+	purgedEvents, err := app.Store.StreamStore.PurgeEvents(time.Now().Add(-STREAM_EVENT_RETENTION))
+	if err != nil {
+		log.Warn("could not purge stream events", zap.Error(err))
+	} else if purgedEvents > 0 {
+		log.Info("purged stream events", zap.Int64("count", purgedEvents))
+	}
+
+	purgedDeliveries, err := app.Store.WebhooksStore.PurgeDeliveries(time.Now().Add(-WEBHOOK_DELIVERY_RETENTION))
+	if err != nil {
+		log.Warn("could not purge webhook deliveries", zap.Error(err))
+	} else if purgedDeliveries > 0 {
+		log.Info("purged webhook deliveries", zap.Int64("count", purgedDeliveries))
+	}
+
+	purgedOutboxEvents, err := app.Store.OutboxStore.PurgeEvents(time.Now().Add(-OUTBOX_RETENTION))
+	if err != nil {
+		log.Warn("could not purge outbox events", zap.Error(err))
+	} else if purgedOutboxEvents > 0 {
+		log.Info("purged outbox events", zap.Int64("count", purgedOutboxEvents))
+	}
+
+	purgedJobs, err := app.Store.JobsStore.PurgeJobs(time.Now().Add(-JOB_RETENTION))
+	if err != nil {
+		log.Warn("could not purge jobs", zap.Error(err))
+	} else if purgedJobs > 0 {
+		log.Info("purged jobs", zap.Int64("count", purgedJobs))
+	}
+}
+
+func (app *Application) publishScheduled(ctx context.Context, job queue.Job) error {
+	for ctx.Err() == nil {
+		published, err := app.Store.PostsStore.PublishDuePosts(time.Now(), constants.PublishBatchSize)
+		if err != nil {
+			return fmt.Errorf("could not publish scheduled posts: %w", err)
+		}
+
+		if published > 0 {
+			log.Info("published scheduled posts", zap.Int64("count", published))
+		}
+
+		if published < constants.PublishBatchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+func (app *Application) deliverWebhooks(ctx context.Context, sender *hooks.Sender) {
+	for ctx.Err() == nil {
+		deliveries, err := app.Store.WebhooksStore.ClaimDueDeliveries(time.Now(), 2*WEBHOOK_TIMEOUT, constants.WebhookBatchSize)
+		if err != nil {
+			log.Warn("could not claim webhook deliveries", zap.Error(err))
+			return
+		}
+
+		var wg sync.WaitGroup
+		for i := range deliveries {
+			wg.Add(1)
+			go func(delivery *hooks.Delivery) {
+				defer wg.Done()
+
+				result := sender.Send(ctx, *delivery)
+				if ctx.Err() != nil {
+					return
+				}
+
+				delivery.Record(result, time.Now(), WEBHOOK_MAX_ATTEMPTS, WEBHOOK_BACKOFF_BASE, WEBHOOK_BACKOFF_MAX)
+				if err := app.Store.WebhooksStore.RecordDeliveryAttempt(delivery); err != nil {
+					log.Warn("could not record webhook delivery", zap.String("delivery_id", delivery.ID.String()), zap.Error(err))
+					return
+				}
+
+				if delivery.Status == constants.WebhookDeliveryFailed {
+					log.Warn("webhook delivery failed", zap.String("delivery_id", delivery.ID.String()), zap.Int("attempts", delivery.Attempts), zap.String("error", delivery.Error))
+				}
+			}(&deliveries[i])
+		}
+		wg.Wait()
+
+		if len(deliveries) < constants.WebhookBatchSize {
+			return
+		}
+	}
+}
+
+func (app *Application) runWebhookJob(ctx context.Context) {
+	sender := hooks.NewSender(WEBHOOK_TIMEOUT)
+
+	ticker := time.NewTicker(WEBHOOK_INTERVAL)
+	defer ticker.Stop()
+
+	app.deliverWebhooks(ctx, sender)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			app.deliverWebhooks(ctx, sender)
+		}
+	}
+}
+
+func (app *Application) dispatchEvents(ctx context.Context, bus *events.Bus) {
+	for ctx.Err() == nil {
+		outboxEvents, err := app.Store.OutboxStore.ClaimDueEvents(time.Now(), OUTBOX_LEASE, constants.OutboxBatchSize)
+		if err != nil {
+			log.Warn("could not claim outbox events", zap.Error(err))
+			return
+		}
+
+		for i := range outboxEvents {
+			event := &outboxEvents[i]
+
+			dispatchErr := bus.Dispatch(ctx, event)
+			if ctx.Err() == nil {
+				event.Record(dispatchErr, time.Now(), OUTBOX_MAX_ATTEMPTS, OUTBOX_BACKOFF_BASE, OUTBOX_BACKOFF_MAX)
+			}
+
+			if err := app.Store.OutboxStore.SaveEvent(event); err != nil {
+				log.Warn("could not save outbox event", zap.String("event_id", event.ID.String()), zap.Error(err))
+				continue
+			}
+
+			switch {
+			case event.Status == constants.OutboxFailed:
+				log.Warn("outbox event failed", zap.String("event_id", event.ID.String()), zap.String("type", string(event.Type)), zap.Int("attempts", event.Attempts), zap.String("error", event.LastError))
+			case dispatchErr != nil:
+				log.Warn("could not dispatch outbox event", zap.String("event_id", event.ID.String()), zap.String("type", string(event.Type)), zap.Error(dispatchErr))
+			}
+		}
+
+		if len(outboxEvents) < constants.OutboxBatchSize {
+			return
+		}
+	}
+}
+
+func (app *Application) runOutboxJob(ctx context.Context, bus *events.Bus) {
+	ticker := time.NewTicker(OUTBOX_INTERVAL)
+	defer ticker.Stop()
+
+	app.dispatchEvents(ctx, bus)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			app.dispatchEvents(ctx, bus)
+		}
+	}
+}
+
+func (app *Application) newEmailMessage(payload sendEmailJob, locale string) (mailer.Message, error) {
+	templates := app.EmailTemplates
+
+	switch payload.Kind {
+	case constants.EmailActivation:
+		return templates.NewActivationEmail(locale, payload.Email, utils.GenerateActivationToken(payload.UserID.String()))
+	case constants.EmailOAuthWelcome:
+		return templates.NewOAuthWelcomeEmail(locale, payload.Email, payload.OAuth)
+	case constants.EmailAccountActivated:
+		return templates.NewAccountActivatedEmail(locale, payload.Email)
+	case constants.EmailPasswordReset:
+		return templates.NewPasswordResetEmail(locale, payload.Email, utils.GeneratePasswordResetToken(payload.UserID.String()))
+	case constants.EmailPasswordChanged:
+		return templates.NewPasswordChangedEmail(locale, payload.Email)
+	default:
+		return mailer.Message{}, queue.Permanent(fmt.Errorf("unknown email kind: %s", payload.Kind))
+	}
+}
+
+func (app *Application) sendEmail(ctx context.Context, payload sendEmailJob) error {
+	locale := constants.DefaultLocale
+	user, err := app.Store.AuthenticationStore.GetUserByID(payload.UserID.String())
+	switch {
+	case err == nil:
+		locale = user.Locale
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return err
+	}
+
+	message, err := app.newEmailMessage(payload, locale)
+	if err != nil {
+		return err
+	}
+
+	if err := app.Mailer.Send(ctx, message); err != nil {
+		return err
+	}
+
+	log.Info("email sent", zap.String("kind", string(payload.Kind)), zap.String("to", payload.Email))
+	return nil
+}
+
+func (app *Application) sendDigests(ctx context.Context, job queue.Job) error {
+	now := time.Now()
+	afterID := uuid.Nil
+	enqueued := 0
+
+	for ctx.Err() == nil {
+		recipients, err := app.Store.UsersStore.GetDigestRecipients(now, afterID, constants.DigestRecipientBatch)
+		if err != nil {
+			return fmt.Errorf("could not get digest recipients: %w", err)
+		}
+
+		for _, recipient := range recipients {
+			key := "digest:" + recipient.ID.String() + ":" + now.UTC().Format(time.DateOnly)
+			if err := queue.EnqueueUnique(app.PostgresDB, key, constants.JobSendDigest, sendDigestJob{UserID: recipient.ID}, now); err != nil {
+				return fmt.Errorf("could not enqueue digest: %w", err)
+			}
+			afterID = recipient.ID
+			enqueued++
+		}
+
+		if len(recipients) < constants.DigestRecipientBatch {
+			if enqueued > 0 {
+				log.Info("enqueued digests", zap.Int("count", enqueued))
+			}
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+func digestName(user authentication.User) string {
+	if user.FirstName != "" {
+		return user.FirstName
+	}
+	if user.Handle != nil {
+		return "@" + *user.Handle
+	}
+	return "Someone"
+}
+
+func (app *Application) digestActivity(userID uuid.UUID, notificationType constants.NotificationType, since time.Time) ([]mailer.DigestActivity, error) {
+	notificationsList, err := app.Store.NotificationsStore.GetDigestNotifications(userID, notificationType, since, constants.DigestItemLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	activity := make([]mailer.DigestActivity, 0, len(notificationsList))
+	for _, notification := range notificationsList {
+		activity = append(activity, mailer.DigestActivity{
+			Actor:  digestName(notification.Actor),
+			Others: max(notification.ActorsCount-1, 0),
+		})
+	}
+	return activity, nil
+}
+
+func (app *Application) sendDigest(ctx context.Context, payload sendDigestJob) error {
+	user, err := app.Store.AuthenticationStore.GetUserByID(payload.UserID.String())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	period := utils.DigestPeriod(user.DigestFrequency)
+	if period == 0 || !user.IsActivated || user.DigestSentAt > utils.DigestCutoff(user.DigestFrequency, now) {
+		return nil
+	}
+
+	since := time.Unix(max(user.DigestSentAt, now.Add(-period).Unix()), 0)
+	data := mailer.DigestEmailData{
+		Email:          user.Email,
+		Frequency:      user.DigestFrequency,
+		UnsubscribeURL: mailer.APP_URL + "/api/v1/users/unsubscribe?token=" + utils.GenerateUnsubscribeToken(user.ID.String()),
+	}
+
+	if data.Followers, err = app.digestActivity(user.ID, constants.NotificationFollow, since); err != nil {
+		return err
+	}
+	if data.Replies, err = app.digestActivity(user.ID, constants.NotificationReply, since); err != nil {
+		return err
+	}
+	if data.Mentions, err = app.digestActivity(user.ID, constants.NotificationMention, since); err != nil {
+		return err
+	}
+
+	digestPosts, err := app.Store.PostsStore.GetDigestPosts(user.ID, since, constants.DigestItemLimit)
+	if err != nil {
+		return err
+	}
+	for _, post := range digestPosts {
+		data.Posts = append(data.Posts, mailer.DigestPost{
+			Title:    post.Title,
+			Author:   digestName(post.Author),
+			URL:      mailer.APP_URL + "/api/v1/posts/" + post.ID.String(),
+			Likes:    post.LikesCount,
+			Comments: post.CommentsCount,
+		})
+	}
+
+	if !data.Empty() {
+		message, err := app.EmailTemplates.NewDigestEmail(user.Locale, data)
+		if err != nil {
+			return err
+		}
+
+		if err := app.Mailer.Send(ctx, message); err != nil {
+			return err
+		}
+
+		log.Info("digest sent", zap.String("frequency", string(user.DigestFrequency)), zap.String("to", user.Email))
+	}
+
+	return app.Store.UsersStore.MarkDigestSent(user.ID, now)
+}
+
+func (app *Application) newJobQueue() *queue.Queue {
+	jobQueue := queue.NewQueue(app.Store.JobsStore, queue.Config{
+		PollInterval: JOB_POLL_INTERVAL,
+		Lease:        JOB_LEASE,
+		BackoffBase:  JOB_BACKOFF_BASE,
+		BackoffMax:   JOB_BACKOFF_MAX,
+		Defaults: queue.Options{
+			Concurrency: JOB_CONCURRENCY,
+			MaxAttempts: JOB_MAX_ATTEMPTS,
+			Timeout:     JOB_TIMEOUT,
+		},
+	})
+
+	jobQueue.Register(constants.JobPurgeDeleted, app.purgeDeleted, queue.Options{Concurrency: 1, MaxAttempts: 1})
+	jobQueue.Register(constants.JobPublishScheduled, app.publishScheduled, queue.Options{Concurrency: 1, MaxAttempts: 1})
+	jobQueue.Register(constants.JobSendEmail, queue.Handle(app.sendEmail), queue.Options{})
+	jobQueue.Register(constants.JobSendDigests, app.sendDigests, queue.Options{Concurrency: 1, MaxAttempts: 1})
+	jobQueue.Register(constants.JobSendDigest, queue.Handle(app.sendDigest), queue.Options{})
+
+	jobQueue.Schedule(constants.JobPurgeDeleted, queue.Every(PURGE_INTERVAL))
+	jobQueue.Schedule(constants.JobPublishScheduled, queue.Every(PUBLISH_INTERVAL))
+
+	digestSchedule, err := queue.ParseCron(DIGEST_SCHEDULE)
+	if err != nil {
+		log.Error("invalid digest schedule, digests are disabled", zap.String("schedule", DIGEST_SCHEDULE), zap.Error(err))
+	} else {
+		jobQueue.Schedule(constants.JobSendDigests, digestSchedule)
+	}
+
+	return jobQueue
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"gopher-social-backend-server/cmd/server/api/services/notifications"
+	"gopher-social-backend-server/pkg/constants"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func mentionNotifications(t *testing.T, app *Application, userID uuid.UUID) []notifications.Notification {
+	t.Helper()
+
+	app.dispatchEvents(context.Background(), app.newEventBus())
+
+	all, err := app.Store.NotificationsStore.GetNotifications(userID, false, 50, 0)
+	if err != nil {
+		t.Fatalf("could not load notifications: %v", err)
+	}
+
+	var mentions []notifications.Notification
+	for _, notification := range all {
+		if notification.Type == constants.NotificationMention {
+			mentions = append(mentions, notification)
+		}
+	}
+	return mentions
+}
+
+func TestScheduledPostMentionsAreNotifiedOnPublish(t *testing.T) {
+	app := newTestApp(t)
+	router := app.testRouter()
+
+	author := createTestUser(t, app, "author")
+	mentioned := createTestUser(t, app, "mentioned")
+
+	publishAt := time.Now().Add(time.Hour).Unix()
+	w := serve(t, router, http.MethodPost, "/api/v1/posts", author, map[string]any{
+		"title":      "Scheduled",
+		"content":    "Hello @" + *mentioned.Handle,
+		"status":     constants.PostStatusScheduled,
+		"publish_at": publishAt,
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create post: expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var post struct {
+		ID uuid.UUID `json:"id"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &post); err != nil {
+		t.Fatalf("could not decode post: %v", err)
+	}
+
+	if got := mentionNotifications(t, app, mentioned.ID); len(got) != 0 {
+		t.Fatalf("expected no mention notifications before publishing, got %d", len(got))
+	}
+
+	if err := app.PostgresDB.Exec("UPDATE posts SET publish_at = ? WHERE id = ?", time.Now().Add(-time.Minute).Unix(), post.ID).Error; err != nil {
+		t.Fatalf("could not move publish_at: %v", err)
+	}
+	if published, err := app.Store.PostsStore.PublishDuePosts(time.Now(), 10); err != nil || published != 1 {
+		t.Fatalf("expected 1 published post, got %d (%v)", published, err)
+	}
+
+	got := mentionNotifications(t, app, mentioned.ID)
+	if len(got) != 1 || got[0].PostID == nil || *got[0].PostID != post.ID || got[0].CommentID != nil {
+		t.Fatalf("expected one mention notification for the post, got %+v", got)
+	}
+
+	w = serve(t, router, http.MethodPost, "/api/v1/posts/"+post.ID.String()+"/comments", author, map[string]string{
+		"content": "Also @" + *mentioned.Handle,
+	})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create comment: expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	got = mentionNotifications(t, app, mentioned.ID)
+	if len(got) != 2 {
+		t.Fatalf("expected a second mention notification for the comment, got %+v", got)
+	}
+}
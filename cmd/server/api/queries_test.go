@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"gopher-social-backend-server/cmd/server/api/services/comments"
+	"gopher-social-backend-server/cmd/server/api/services/posts"
+	"gopher-social-backend-server/pkg/constants"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gorm.io/gorm/logger"
+)
+
+// queryCounter counts every statement gorm executes. gorm traces each
+// statement through the logger, preloads included.
+type queryCounter struct {
+	logger.Interface
+	count atomic.Int64
+}
+
+func (c *queryCounter) LogMode(logger.LogLevel) logger.Interface {
+	return c
+}
+
+func (c *queryCounter) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	c.count.Add(1)
+}
+
+func countQueries(t *testing.T, app *Application) *queryCounter {
+	t.Helper()
+
+	counter := &queryCounter{Interface: logger.Discard}
+	previous := app.PostgresDB.Logger
+	app.PostgresDB.Logger = counter
+	t.Cleanup(func() {
+		app.PostgresDB.Logger = previous
+	})
+
+	return counter
+}
+
+// queriesFor returns the number of statements a GET to path runs.
+func queriesFor(t *testing.T, app *Application, counter *queryCounter, path string, wantItems int) int64 {
+	t.Helper()
+
+	viewer := createTestUser(t, app, fmt.Sprintf("viewer%d", wantItems))
+	router := app.testRouter()
+
+	counter.count.Store(0)
+	w := serve(t, router, http.MethodGet, path, viewer, nil)
+	queries := counter.count.Load()
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET %s: expected 200, got %d: %s", path, w.Code, w.Body.String())
+	}
+	if got := len(decodePage(t, w)); got != wantItems {
+		t.Fatalf("GET %s: expected %d items, got %d", path, wantItems, got)
+	}
+
+	return queries
+}
+
+func TestPostsPageQueryCountIsConstant(t *testing.T) {
+	app := newTestApp(t)
+
+	for i := range 12 {
+		author := createTestUser(t, app, fmt.Sprintf("author%d", i))
+		post := &posts.Post{
+			AuthorID: author.ID,
+			Title:    fmt.Sprintf("Post %d #gophers", i),
+			Content:  fmt.Sprintf("Body %d mentioning @%s #golang", i, *author.Handle),
+		}
+		if err := app.Store.PostsStore.CreatePost(post, &[]string{"backend"}, nil); err != nil {
+			t.Fatalf("could not create post: %v", err)
+		}
+		if err := app.Store.PostsStore.ReactToPost(author.ID, post.ID, constants.ReactionLike); err != nil {
+			t.Fatalf("could not react to post: %v", err)
+		}
+	}
+
+	counter := countQueries(t, app)
+
+	small := queriesFor(t, app, counter, "/api/v1/posts?limit=2", 2)
+	large := queriesFor(t, app, counter, "/api/v1/posts?limit=12", 12)
+	if small != large {
+		t.Fatalf("GET /api/v1/posts ran %d queries for 2 posts and %d for 12", small, large)
+	}
+
+	smallByTag := queriesFor(t, app, counter, "/api/v1/tags/backend/posts?limit=2", 2)
+	largeByTag := queriesFor(t, app, counter, "/api/v1/tags/backend/posts?limit=12", 12)
+	if smallByTag != largeByTag {
+		t.Fatalf("GET /api/v1/tags/backend/posts ran %d queries for 2 posts and %d for 12", smallByTag, largeByTag)
+	}
+}
+
+func TestCommentsPageQueryCountIsConstant(t *testing.T) {
+	app := newTestApp(t)
+
+	author := createTestUser(t, app, "author")
+	post := &posts.Post{AuthorID: author.ID, Title: "Post", Content: "Body"}
+	if err := app.Store.PostsStore.CreatePost(post, nil, nil); err != nil {
+		t.Fatalf("could not create post: %v", err)
+	}
+
+	for i := range 12 {
+		commenter := createTestUser(t, app, fmt.Sprintf("commenter%d", i))
+		comment := &comments.Comment{
+			AuthorID: commenter.ID,
+			PostID:   post.ID,
+			Content:  fmt.Sprintf("Comment %d for @%s", i, *author.Handle),
+		}
+		if err := app.Store.CommentsStore.CreateComment(comment, nil); err != nil {
+			t.Fatalf("could not create comment: %v", err)
+		}
+		if err := app.Store.CommentsStore.ReactToComment(author.ID, comment.ID, constants.ReactionLike); err != nil {
+			t.Fatalf("could not react to comment: %v", err)
+		}
+	}
+
+	counter := countQueries(t, app)
+
+	path := "/api/v1/posts/" + post.ID.String() + "/comments"
+	small := queriesFor(t, app, counter, path+"?limit=2", 2)
+	large := queriesFor(t, app, counter, path+"?limit=12", 12)
+	if small != large {
+		t.Fatalf("GET %s ran %d queries for 2 comments and %d for 12", path, small, large)
+	}
+}
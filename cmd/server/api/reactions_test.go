@@ -0,0 +1,142 @@
+package api
+
+import (
+	"fmt"
+	"gopher-social-backend-server/cmd/server/api/services/authentication"
+	"gopher-social-backend-server/cmd/server/api/services/comments"
+	"gopher-social-backend-server/cmd/server/api/services/posts"
+	"gopher-social-backend-server/pkg/constants"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+var testReactionTypes = []constants.ReactionType{constants.ReactionLike, constants.ReactionDislike, "love"}
+
+// hammerReactions sends interleaved PUT and DELETE reaction requests for
+// every user in parallel and fails on any status other than 200, or 404 for
+// removing a reaction that is already gone.
+func hammerReactions(t *testing.T, router http.Handler, users []*authentication.User, path string) {
+	t.Helper()
+
+	const callsPerUser = 30
+
+	type failure struct {
+		method, path string
+		code         int
+		body         string
+	}
+
+	var wg sync.WaitGroup
+	failures := make(chan failure, len(users)*callsPerUser)
+	for _, user := range users {
+		for i := range callsPerUser {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				method := http.MethodPut
+				if i%2 == 1 {
+					method = http.MethodDelete
+				}
+				reactionPath := path + "/reactions/" + string(testReactionTypes[i%len(testReactionTypes)])
+
+				w := serve(t, router, method, reactionPath, user, nil)
+				if w.Code == http.StatusOK || (method == http.MethodDelete && w.Code == http.StatusNotFound && strings.Contains(w.Body.String(), "reaction not found")) {
+					return
+				}
+				failures <- failure{method: method, path: reactionPath, code: w.Code, body: w.Body.String()}
+			}()
+		}
+	}
+	wg.Wait()
+	close(failures)
+
+	for f := range failures {
+		t.Errorf("%s %s: unexpected %d: %s", f.method, f.path, f.code, f.body)
+	}
+}
+
+// expectConsistentReactions checks that table holds at most one reaction per
+// user for targetID and that the stored counters match its rows.
+func expectConsistentReactions(t *testing.T, app *Application, table, column string, targetID uuid.UUID, counts map[constants.ReactionType]int64, likes, dislikes int64) {
+	t.Helper()
+
+	var duplicates int64
+	if err := app.PostgresDB.Raw(fmt.Sprintf(`SELECT COUNT(*) FROM (
+		SELECT user_id FROM %s WHERE %s = ? GROUP BY user_id HAVING COUNT(*) > 1
+	) duplicated`, table, column), targetID).Scan(&duplicates).Error; err != nil {
+		t.Fatalf("could not count duplicate reactions: %v", err)
+	}
+	if duplicates != 0 {
+		t.Fatalf("expected at most one reaction per user, found %d users with duplicates", duplicates)
+	}
+
+	var rows []struct {
+		Type  constants.ReactionType
+		Count int64
+	}
+	if err := app.PostgresDB.Raw(fmt.Sprintf("SELECT type, COUNT(*) AS count FROM %s WHERE %s = ? GROUP BY type", table, column), targetID).Scan(&rows).Error; err != nil {
+		t.Fatalf("could not count reactions: %v", err)
+	}
+
+	want := make(map[constants.ReactionType]int64)
+	for _, row := range rows {
+		want[row.Type] = row.Count
+	}
+
+	for _, reactionType := range testReactionTypes {
+		if got := counts[reactionType]; got != want[reactionType] {
+			t.Errorf("reaction_counts[%s] = %d, want %d", reactionType, got, want[reactionType])
+		}
+	}
+	if likes != want[constants.ReactionLike] {
+		t.Errorf("likes_count = %d, want %d", likes, want[constants.ReactionLike])
+	}
+	if dislikes != want[constants.ReactionDislike] {
+		t.Errorf("dislikes_count = %d, want %d", dislikes, want[constants.ReactionDislike])
+	}
+}
+
+func TestConcurrentReactionsStayConsistent(t *testing.T) {
+	app := newTestApp(t)
+	router := app.testRouter()
+
+	author := createTestUser(t, app, "author")
+	post := &posts.Post{AuthorID: author.ID, Title: "Post", Content: "Body"}
+	if err := app.Store.PostsStore.CreatePost(post, nil, nil); err != nil {
+		t.Fatalf("could not create post: %v", err)
+	}
+	comment := &comments.Comment{PostID: post.ID, AuthorID: author.ID, Content: "Comment"}
+	if err := app.Store.CommentsStore.CreateComment(comment, nil); err != nil {
+		t.Fatalf("could not create comment: %v", err)
+	}
+
+	var reactors []*authentication.User
+	for u := range 3 {
+		reactors = append(reactors, createTestUser(t, app, fmt.Sprintf("reactor%d", u)))
+	}
+
+	t.Run("post", func(t *testing.T) {
+		hammerReactions(t, router, reactors, "/api/v1/posts/"+post.ID.String())
+
+		stored, err := app.Store.PostsStore.GetPostByID(post.ID)
+		if err != nil {
+			t.Fatalf("could not reload post: %v", err)
+		}
+		expectConsistentReactions(t, app, "post_reactions", "post_id", post.ID, stored.ReactionCounts, stored.LikesCount, stored.DislikesCount)
+	})
+
+	t.Run("comment", func(t *testing.T) {
+		hammerReactions(t, router, reactors, "/api/v1/comments/"+comment.ID.String())
+
+		stored, err := app.Store.CommentsStore.GetCommentByID(comment.ID)
+		if err != nil {
+			t.Fatalf("could not reload comment: %v", err)
+		}
+		expectConsistentReactions(t, app, "comment_reactions", "comment_id", comment.ID, stored.ReactionCounts, stored.LikesCount, stored.DislikesCount)
+	})
+}
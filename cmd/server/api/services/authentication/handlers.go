@@ -5,7 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"gopher-social-backend-server/pkg/constants"
-	"gopher-social-backend-server/pkg/mailer"
 	"gopher-social-backend-server/pkg/utils"
 	"net/http"
 	"strings"
@@ -56,9 +55,6 @@ func (h *AuthenticationHandler) RegisterUserHandler(w http.ResponseWriter, r *ht
 		return
 	}
 
-	token := utils.GenerateActivationToken(user.ID.String())
-	mailer.SendActivationEmail(user.Email, token)
-
 	if err := utils.WriteJSON(w, http.StatusCreated, user); err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -89,14 +85,11 @@ func (h *AuthenticationHandler) ActivateUserHandler(w http.ResponseWriter, r *ht
 		return
 	}
 
-	user.IsActivated = true
-	if err := h.AuthenticationStore.UpdateUser(user); err != nil {
+	if err := h.AuthenticationStore.ActivateUser(user); err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	mailer.SendAccountActivatedEmail(user.Email)
-
 	if err := utils.WriteJSON(w, http.StatusOK, user); err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -184,8 +177,10 @@ func (h *AuthenticationHandler) ForgotPasswordHandler(w http.ResponseWriter, r *
 		return
 	}
 
-	token := utils.GeneratePasswordResetToken(user.ID.String())
-	mailer.SendPasswordResetEmail(user.Email, token)
+	if err := h.AuthenticationStore.RequestPasswordReset(user); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
 
 	if err := utils.WriteJSON(w, http.StatusOK, map[string]string{"message": "password reset email sent"}); err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err.Error())
@@ -229,14 +224,11 @@ func (h *AuthenticationHandler) ResetPasswordHandler(w http.ResponseWriter, r *h
 		return
 	}
 
-	user.Password = string(hashedPassword)
-	if err := h.AuthenticationStore.UpdateUser(user); err != nil {
+	if err := h.AuthenticationStore.ChangePassword(user, string(hashedPassword)); err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	mailer.SendPasswordChangedEmail(user.Email)
-
 	if err := utils.WriteJSON(w, http.StatusOK, map[string]string{"message": "password changed"}); err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
@@ -287,7 +279,6 @@ func (h *AuthenticationHandler) GoogleCallbackHandler(w http.ResponseWriter, r *
 	existingUser, _ := h.AuthenticationStore.GetUserByEmail(user.Email)
 	if existingUser == nil {
 		h.AuthenticationStore.CreateUser(&user)
-		mailer.SendOAuthWelcomeEmail(user.Email, user.OAuth)
 	}
 
 	accessToken, expirationTime := utils.GenerateAccessToken(user.ID.String())
@@ -356,7 +347,6 @@ func (h *AuthenticationHandler) GitHubCallbackHandler(w http.ResponseWriter, r *
 	existingUser, _ := h.AuthenticationStore.GetUserByEmail(user.Email)
 	if existingUser == nil {
 		h.AuthenticationStore.CreateUser(&user)
-		mailer.SendOAuthWelcomeEmail(user.Email, user.OAuth)
 	}
 
 	accessToken, expirationTime := utils.GenerateAccessToken(user.ID.String())
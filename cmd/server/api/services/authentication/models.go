@@ -7,14 +7,19 @@ import (
 )
 
 type User struct {
-	ID          uuid.UUID               `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	FirstName   string                  `json:"first_name" gorm:"type:varchar(100);not null"`
-	LastName    string                  `json:"last_name" gorm:"type:varchar(100);not null"`
-	Email       string                  `json:"email" gorm:"type:varchar(100);unique;not null"`
-	Password    string                  `json:"password" gorm:"type:varchar(100);not null"`
-	Role        constants.UserRole      `json:"role" gorm:"type:varchar(20);not null;default:'user'"`
-	IsActivated bool                    `json:"is_activated" gorm:"default:false"`
-	OAuth       constants.OAuthProvider `json:"oauth" gorm:"type:varchar(20);not null;default:'none'"`
-	CreatedAt   int64                   `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt   int64                   `json:"updated_at" gorm:"autoUpdateTime"`
+	ID              uuid.UUID                 `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	FirstName       string                    `json:"first_name" gorm:"type:varchar(100);not null"`
+	LastName        string                    `json:"last_name" gorm:"type:varchar(100);not null"`
+	Email           string                    `json:"email" gorm:"type:varchar(100);unique;not null"`
+	Handle          *string                   `json:"handle" gorm:"type:varchar(30);uniqueIndex"`
+	Password        string                    `json:"password" gorm:"type:varchar(100);not null"`
+	Role            constants.UserRole        `json:"role" gorm:"type:varchar(20);not null;default:'user'"`
+	IsActivated     bool                      `json:"is_activated" gorm:"default:false"`
+	OAuth           constants.OAuthProvider   `json:"oauth" gorm:"type:varchar(20);not null;default:'none'"`
+	MentionPolicy   constants.MentionPolicy   `json:"mention_policy" gorm:"type:varchar(16);not null;default:'everyone'"`
+	Locale          string                    `json:"locale" gorm:"type:varchar(16);not null;default:'en'"`
+	DigestFrequency constants.DigestFrequency `json:"digest_frequency" gorm:"type:varchar(16);not null;default:'weekly'"`
+	DigestSentAt    int64                     `json:"digest_sent_at" gorm:"not null;default:0"`
+	CreatedAt       int64                     `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       int64                     `json:"updated_at" gorm:"autoUpdateTime"`
 }
@@ -1,12 +1,24 @@
 package authentication
 
-import "gorm.io/gorm"
+import (
+	"fmt"
+	"gopher-social-backend-server/internal/events"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/utils"
+	"math/rand"
+
+	"gorm.io/gorm"
+)
 
 type AuthenticationStore interface {
 	CreateUser(user *User) error
 	GetUserByEmail(email string) (*User, error)
 	GetUserByID(id string) (*User, error)
+	GetUserByHandle(handle string) (*User, error)
 	UpdateUser(user *User) error
+	ActivateUser(user *User) error
+	ChangePassword(user *User, hashedPassword string) error
+	RequestPasswordReset(user *User) error
 }
 
 type authenticationStore struct {
@@ -19,8 +31,37 @@ func NewAuthenticationStore(postgresDB *gorm.DB) AuthenticationStore {
 	}
 }
 
+func (s *authenticationStore) availableHandle(base string) (string, error) {
+	handle := base
+	for attempt := 0; attempt < constants.MaxHandleAttempts; attempt++ {
+		var count int64
+		if err := s.postgresDB.Model(&User{}).Where("handle = ?", handle).Count(&count).Error; err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return handle, nil
+		}
+		handle = fmt.Sprintf("%s_%d", base, rand.Intn(10000))
+	}
+	return "", fmt.Errorf("could not find an available handle for %s", base)
+}
+
 func (s *authenticationStore) CreateUser(user *User) error {
-	return s.postgresDB.Create(user).Error
+	if user.Handle == nil {
+		handle, err := s.availableHandle(utils.HandleFromEmail(user.Email))
+		if err != nil {
+			return err
+		}
+		user.Handle = &handle
+	}
+
+	return s.postgresDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+
+		return events.Record(tx, constants.DomainEventUserRegistered, events.UserRegistered{UserID: user.ID, Email: user.Email, OAuth: user.OAuth})
+	})
 }
 
 func (s *authenticationStore) GetUserByEmail(email string) (*User, error) {
@@ -39,6 +80,38 @@ func (s *authenticationStore) GetUserByID(id string) (*User, error) {
 	return &user, nil
 }
 
+func (s *authenticationStore) GetUserByHandle(handle string) (*User, error) {
+	var user User
+	if err := s.postgresDB.Where("handle = ?", handle).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
 func (s *authenticationStore) UpdateUser(user *User) error {
 	return s.postgresDB.Save(user).Error
 }
+
+func (s *authenticationStore) ActivateUser(user *User) error {
+	return s.postgresDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Update("is_activated", true).Error; err != nil {
+			return err
+		}
+
+		return events.Record(tx, constants.DomainEventUserActivated, events.UserActivated{UserID: user.ID, Email: user.Email})
+	})
+}
+
+func (s *authenticationStore) ChangePassword(user *User, hashedPassword string) error {
+	return s.postgresDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(user).Update("password", hashedPassword).Error; err != nil {
+			return err
+		}
+
+		return events.Record(tx, constants.DomainEventPasswordChanged, events.PasswordChanged{UserID: user.ID, Email: user.Email})
+	})
+}
+
+func (s *authenticationStore) RequestPasswordReset(user *User) error {
+	return events.Record(s.postgresDB, constants.DomainEventPasswordResetRequested, events.PasswordResetRequested{UserID: user.ID, Email: user.Email})
+}
@@ -2,101 +2,178 @@ package comments
 
 import (
 	"errors"
+	"fmt"
 	"gopher-social-backend-server/cmd/server/api/services/authentication"
 	"gopher-social-backend-server/cmd/server/api/services/posts"
+	"gopher-social-backend-server/cmd/server/api/services/users"
 	"gopher-social-backend-server/pkg/constants"
 	"gopher-social-backend-server/pkg/utils"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type CommentsHandler struct {
 	CommentsStore       CommentsStore
 	PostsStore          posts.PostsStore
 	AuthenticationStore authentication.AuthenticationStore
+	UsersStore          users.UsersStore
 }
 
+var MAX_COMMENT_DEPTH = utils.GetEnvAsInt("MAX_COMMENT_DEPTH", constants.DefaultMaxCommentDepth)
+
+var COMMENT_EDIT_WINDOW = utils.GetEnvAsDuration("COMMENT_EDIT_WINDOW", constants.DefaultCommentEditWindow)
+var DIFF_RATE_LIMIT = utils.GetEnvAsDuration("DIFF_RATE_LIMIT", constants.DefaultDiffRateLimit)
+
 var validate = validator.New()
 
-func (h *CommentsHandler) fetchCommentDetails(commentID uuid.UUID) (*commentCreateUpdateResponse, error) {
-	comment, err := h.CommentsStore.GetCommentByID(commentID)
+func (h *CommentsHandler) buildCommentResponses(comments []Comment, viewerID uuid.UUID) ([]commentCreateUpdateResponse, error) {
+	commentIDs := make([]uuid.UUID, 0, len(comments))
+	for _, comment := range comments {
+		commentIDs = append(commentIDs, comment.ID)
+	}
+
+	viewerReactions, err := h.CommentsStore.GetUserReactionsForComments(viewerID, commentIDs)
 	if err != nil {
 		return nil, err
 	}
 
-	commentLikes, _ := h.CommentsStore.GetLikesCount(comment.ID)
-	commentDislikes, _ := h.CommentsStore.GetDislikesCount(comment.ID)
-
-	post, err := h.PostsStore.GetPostByID(comment.PostID)
+	commentMentions, err := h.CommentsStore.GetMentionsForComments(commentIDs)
 	if err != nil {
 		return nil, err
 	}
 
-	postLikes, _ := h.PostsStore.GetLikesCount(post.ID)
-	postDislikes, _ := h.PostsStore.GetDislikesCount(post.ID)
+	commentResponses := make([]commentCreateUpdateResponse, 0, len(comments))
+	for _, comment := range comments {
+		author := &commentCreateUpdateResponseAuthor{
+			ID:        comment.Author.ID,
+			FirstName: comment.Author.FirstName,
+			LastName:  comment.Author.LastName,
+			Email:     comment.Author.Email,
+		}
+		content := comment.Content
+		contentFormat := comment.ContentFormat
+		contentHTML := utils.RenderedContent(comment.ContentFormat, comment.Content, comment.ContentHTML, comment.ContentRenderVersion)
+		mentions := make([]commentMentionResponse, 0, len(commentMentions[comment.ID]))
+		for _, mention := range commentMentions[comment.ID] {
+			mentions = append(mentions, commentMentionResponse{
+				UserID: mention.UserID,
+				Handle: mention.User.Handle,
+				Start:  mention.Start,
+				End:    mention.End,
+			})
+		}
+		if comment.DeletedAt.Valid {
+			author = nil
+			mentions = []commentMentionResponse{}
+			content = constants.DeletedCommentPlaceholder
+			contentFormat = constants.ContentFormatPlain
+			contentHTML, _ = utils.RenderContent(constants.ContentFormatPlain, constants.DeletedCommentPlaceholder)
+		}
+
+		var viewer *commentViewerResponse
+		if viewerID != uuid.Nil {
+			viewer = &commentViewerResponse{
+				Liked:    viewerReactions[comment.ID] == constants.ReactionLike,
+				Disliked: viewerReactions[comment.ID] == constants.ReactionDislike,
+				Reaction: viewerReactions[comment.ID],
+				Authored: !comment.DeletedAt.Valid && comment.AuthorID == viewerID,
+			}
+		}
+
+		commentResponses = append(commentResponses, commentCreateUpdateResponse{
+			ID:     comment.ID,
+			Author: author,
+			Post: commentCreateUpdateResponsePost{
+				ID: comment.Post.ID,
+				Author: commentCreateUpdateResponsePostAuthor{
+					ID:        comment.Post.Author.ID,
+					FirstName: comment.Post.Author.FirstName,
+					LastName:  comment.Post.Author.LastName,
+					Email:     comment.Post.Author.Email,
+				},
+				Title:     comment.Post.Title,
+				Content:   comment.Post.Content,
+				Likes:     comment.Post.LikesCount,
+				Dislikes:  comment.Post.DislikesCount,
+				Comments:  comment.Post.CommentsCount,
+				Reactions: utils.ReactionCounts(comment.Post.ReactionCounts),
+				CreatedAt: comment.Post.CreatedAt,
+				UpdatedAt: comment.Post.UpdatedAt,
+			},
+			ParentID:      comment.ParentID,
+			Depth:         comment.Depth,
+			Content:       content,
+			ContentFormat: contentFormat,
+			ContentHTML:   contentHTML,
+			Mentions:      mentions,
+			Removed:       comment.DeletedAt.Valid,
+			Replies:       comment.RepliesCount,
+			Edited:        comment.EditCount > 0,
+			EditCount:     comment.EditCount,
+			Likes:         comment.LikesCount,
+			Dislikes:      comment.DislikesCount,
+			Reactions:     utils.ReactionCounts(comment.ReactionCounts),
+			Viewer:        viewer,
+			CreatedAt:     comment.CreatedAt,
+			UpdatedAt:     comment.UpdatedAt,
+		})
+	}
+
+	return commentResponses, nil
+}
 
-	author, err := h.AuthenticationStore.GetUserByID(comment.AuthorID.String())
+func (h *CommentsHandler) fetchCommentDetails(commentID, viewerID uuid.UUID) (*commentCreateUpdateResponse, error) {
+	comment, err := h.CommentsStore.GetCommentByID(commentID)
 	if err != nil {
 		return nil, err
 	}
 
-	postAuthor, err := h.AuthenticationStore.GetUserByID(post.AuthorID.String())
+	commentResponses, err := h.buildCommentResponses([]Comment{*comment}, viewerID)
 	if err != nil {
 		return nil, err
 	}
 
-	return &commentCreateUpdateResponse{
-		ID: comment.ID,
-		Author: commentCreateUpdateResponseAuthor{
-			ID:        author.ID,
-			FirstName: author.FirstName,
-			LastName:  author.LastName,
-			Email:     author.Email,
-		},
-		Post: commentCreateUpdateResponsePost{
-			ID: post.ID,
-			Author: commentCreateUpdateResponsePostAuthor{
-				ID:        postAuthor.ID,
-				FirstName: postAuthor.FirstName,
-				LastName:  postAuthor.LastName,
-				Email:     postAuthor.Email,
-			},
-			Title:     post.Title,
-			Content:   post.Content,
-			Likes:     postLikes,
-			Dislikes:  postDislikes,
-			CreatedAt: post.CreatedAt,
-			UpdatedAt: post.UpdatedAt,
-		},
-		Content:   comment.Content,
-		Likes:     commentLikes,
-		Dislikes:  commentDislikes,
-		CreatedAt: comment.CreatedAt,
-		UpdatedAt: comment.UpdatedAt,
-	}, nil
+	return &commentResponses[0], nil
 }
 
-func (h *CommentsHandler) verifyCommentOwnership(commentID uuid.UUID, authUserId string) (*Comment, error) {
-	comment, err := h.CommentsStore.GetCommentByID(commentID)
+func (h *CommentsHandler) resolveMentions(comment *Comment) ([]CommentMention, error) {
+	matches := utils.ExtractMentions(comment.Content)
+
+	mentionedUsers, err := h.UsersStore.ResolveMentions(comment.AuthorID, utils.MentionHandles(matches))
 	if err != nil {
 		return nil, err
 	}
 
-	commentAuthor, err := h.AuthenticationStore.GetUserByID(comment.AuthorID.String())
-	if err != nil {
-		return nil, err
+	userIDs := make(map[string]uuid.UUID, len(mentionedUsers))
+	for _, user := range mentionedUsers {
+		userIDs[*user.Handle] = user.ID
 	}
 
-	post, err := h.PostsStore.GetPostByID(comment.PostID)
+	mentions := make([]CommentMention, 0, len(matches))
+	for _, match := range matches {
+		if userID, ok := userIDs[match.Handle]; ok {
+			mentions = append(mentions, CommentMention{UserID: userID, Start: match.Start, End: match.End})
+		}
+	}
+
+	return mentions, nil
+}
+
+func (h *CommentsHandler) verifyCommentOwnership(commentID uuid.UUID, authUserId string) (*Comment, error) {
+	comment, err := h.CommentsStore.GetCommentByID(commentID)
 	if err != nil {
 		return nil, err
 	}
 
-	comment.Author = *commentAuthor
-	comment.Post = *post
+	if comment.DeletedAt.Valid {
+		return nil, errors.New("comment has been deleted")
+	}
 
 	if err := utils.VerifyOwnership(comment.AuthorID.String(), authUserId); err != nil {
 		return nil, err
@@ -105,48 +182,56 @@ func (h *CommentsHandler) verifyCommentOwnership(commentID uuid.UUID, authUserId
 	return comment, nil
 }
 
+func (h *CommentsHandler) verifyVisibility(w http.ResponseWriter, commentID, viewerID uuid.UUID) (*Comment, bool) {
+	comment, err := h.CommentsStore.GetCommentByID(commentID)
+	if err == nil {
+		_, err = h.PostsStore.GetVisiblePostByID(comment.PostID, viewerID)
+	}
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.WriteError(w, http.StatusNotFound, "comment not found")
+			return nil, false
+		}
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+
+	return comment, true
+}
+
+var likeDislikeConflicts = map[string]string{
+	"like":      "you have already liked this comment",
+	"unlike":    "you haven't liked this comment",
+	"dislike":   "you have already disliked this comment",
+	"undislike": "you haven't disliked this comment",
+}
+
 func (h *CommentsHandler) handleCommentLikeDislike(userUUID, commentID uuid.UUID, action string) error {
 	switch action {
 	case "like":
-		if liked, _ := h.CommentsStore.HasUserLikedComment(userUUID, commentID); liked {
-			return errors.New("you have already liked this comment")
-		}
-		if disliked, _ := h.CommentsStore.HasUserDislikedComment(userUUID, commentID); disliked {
-			h.CommentsStore.RemoveDislikeComment(commentID, userUUID)
-		}
-		return h.CommentsStore.LikeComment(&CommentLike{
-			UserID:    userUUID,
-			CommentID: commentID,
-		})
-
+		return h.CommentsStore.ReactToComment(userUUID, commentID, constants.ReactionLike)
 	case "unlike":
-		if liked, _ := h.CommentsStore.HasUserLikedComment(userUUID, commentID); !liked {
-			return errors.New("you haven't liked this comment")
-		}
-		return h.CommentsStore.RemoveLikeComment(userUUID, commentID)
-
+		return h.CommentsStore.RemoveCommentReaction(userUUID, commentID, constants.ReactionLike)
 	case "dislike":
-		if disliked, _ := h.CommentsStore.HasUserDislikedComment(userUUID, commentID); disliked {
-			return errors.New("you have already disliked this comment")
-		}
-		if liked, _ := h.CommentsStore.HasUserLikedComment(userUUID, commentID); liked {
-			h.CommentsStore.RemoveLikeComment(userUUID, commentID)
-		}
-		return h.CommentsStore.DislikeComment(&CommentDislike{
-			UserID:    userUUID,
-			CommentID: commentID,
-		})
-
+		return h.CommentsStore.ReactToComment(userUUID, commentID, constants.ReactionDislike)
 	case "undislike":
-		if disliked, _ := h.CommentsStore.HasUserDislikedComment(userUUID, commentID); !disliked {
-			return errors.New("you haven't disliked this comment")
-		}
-		return h.CommentsStore.RemoveDislikeComment(userUUID, commentID)
+		return h.CommentsStore.RemoveCommentReaction(userUUID, commentID, constants.ReactionDislike)
 	}
-
 	return nil
 }
 
+func writeLikeDislikeError(w http.ResponseWriter, action string, err error) {
+	switch {
+	case errors.Is(err, posts.ErrAlreadyReacted), errors.Is(err, posts.ErrReactionNotFound):
+		utils.WriteError(w, http.StatusConflict, likeDislikeConflicts[action])
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		utils.WriteError(w, http.StatusNotFound, "comment not found")
+	default:
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
 func (h *CommentsHandler) GetCommentByIDHandler(w http.ResponseWriter, r *http.Request) {
 	commentID, err := uuid.Parse(chi.URLParam(r, "commentID"))
 	if err != nil {
@@ -154,13 +239,20 @@ func (h *CommentsHandler) GetCommentByIDHandler(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	comment, err := h.fetchCommentDetails(commentID)
+	viewerID := utils.ViewerID(r)
+
+	comment, ok := h.verifyVisibility(w, commentID, viewerID)
+	if !ok {
+		return
+	}
+
+	commentResponses, err := h.buildCommentResponses([]Comment{*comment}, viewerID)
 	if err != nil {
-		utils.WriteError(w, http.StatusNotFound, err.Error())
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	utils.WriteJSON(w, http.StatusOK, comment)
+	utils.WriteJSON(w, http.StatusOK, commentResponses[0])
 }
 
 func (h *CommentsHandler) GetCommentsForPostHandler(w http.ResponseWriter, r *http.Request) {
@@ -172,67 +264,56 @@ func (h *CommentsHandler) GetCommentsForPostHandler(w http.ResponseWriter, r *ht
 
 	limit := r.Context().Value(constants.LimitKey).(int)
 	offset := r.Context().Value(constants.OffsetKey).(int)
+	cursor := r.Context().Value(constants.CursorKey).(*utils.Cursor)
 	orderby := r.Context().Value(constants.OrderByKey).(string)
 	desc := r.Context().Value(constants.DescKey).(string) == "true"
 
-	comments, err := h.CommentsStore.GetCommentsForPost(postID, limit, offset, orderby, desc)
-	if err != nil {
-		utils.WriteError(w, http.StatusNotFound, err.Error())
+	if err := utils.ValidateCursor(cursor, orderby, desc); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	commentResponses := make([]commentCreateUpdateResponse, 0)
-	for _, comment := range comments {
-		commentLikes, _ := h.CommentsStore.GetLikesCount(comment.ID)
-		commentDislikes, _ := h.CommentsStore.GetDislikesCount(comment.ID)
+	if _, err := h.PostsStore.GetVisiblePostByID(postID, utils.ViewerID(r)); err != nil {
+		utils.WriteError(w, http.StatusNotFound, "post not found")
+		return
+	}
 
-		author, err := h.AuthenticationStore.GetUserByID(comment.AuthorID.String())
-		if err != nil {
-			utils.WriteError(w, http.StatusNotFound, err.Error())
+	topLevel := false
+	if topLevelParam := r.URL.Query().Get("top_level"); topLevelParam != "" {
+		if topLevel, err = strconv.ParseBool(topLevelParam); err != nil {
+			utils.WriteError(w, http.StatusBadRequest, "invalid top_level: must be true or false")
 			return
 		}
+	}
 
-		post, err := h.PostsStore.GetPostByID(comment.PostID)
+	comments, err := h.CommentsStore.GetCommentsForPost(postID, topLevel, limit, offset, cursor, orderby, desc)
+	if err != nil {
+		utils.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	comments, pageInfo, err := utils.PaginateResults(r, comments)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if utils.IncludeTotal(r) {
+		total, err := h.CommentsStore.CountCommentsForPost(postID, topLevel)
 		if err != nil {
-			utils.WriteError(w, http.StatusNotFound, err.Error())
+			utils.WriteError(w, http.StatusInternalServerError, err.Error())
 			return
 		}
+		pageInfo.Total = &total
+	}
 
-		postLikes, _ := h.PostsStore.GetLikesCount(post.ID)
-		postDislikes, _ := h.PostsStore.GetDislikesCount(post.ID)
-
-		commentResponses = append(commentResponses, commentCreateUpdateResponse{
-			ID: comment.ID,
-			Author: commentCreateUpdateResponseAuthor{
-				ID:        author.ID,
-				FirstName: author.FirstName,
-				LastName:  author.LastName,
-				Email:     author.Email,
-			},
-			Post: commentCreateUpdateResponsePost{
-				ID: post.ID,
-				Author: commentCreateUpdateResponsePostAuthor{
-					ID:        author.ID,
-					FirstName: author.FirstName,
-					LastName:  author.LastName,
-					Email:     author.Email,
-				},
-				Title:     post.Title,
-				Content:   post.Content,
-				Likes:     postLikes,
-				Dislikes:  postDislikes,
-				CreatedAt: post.CreatedAt,
-				UpdatedAt: post.UpdatedAt,
-			},
-			Content:   comment.Content,
-			Likes:     commentLikes,
-			Dislikes:  commentDislikes,
-			CreatedAt: comment.CreatedAt,
-			UpdatedAt: comment.UpdatedAt,
-		})
+	commentResponses, err := h.buildCommentResponses(comments, utils.ViewerID(r))
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
 	}
 
-	utils.WriteJSON(w, http.StatusOK, commentResponses)
+	utils.WritePage(w, r, http.StatusOK, commentResponses, pageInfo)
 }
 
 func (h *CommentsHandler) CreateCommentHandler(w http.ResponseWriter, r *http.Request) {
@@ -253,20 +334,38 @@ func (h *CommentsHandler) CreateCommentHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	post, err := h.PostsStore.GetVisiblePostByID(postID, utils.ViewerID(r))
+	if err != nil {
+		utils.WriteError(w, http.StatusNotFound, "post not found")
+		return
+	}
+
+	if post.Status != constants.PostStatusPublished {
+		utils.WriteError(w, http.StatusConflict, "comments can only be added to published posts")
+		return
+	}
+
 	authUserID := r.Context().Value(constants.UserIDKey).(string)
 
 	comment := &Comment{
-		AuthorID: uuid.MustParse(authUserID),
-		PostID:   postID,
-		Content:  payload.Content,
+		AuthorID:      uuid.MustParse(authUserID),
+		PostID:        postID,
+		Content:       payload.Content,
+		ContentFormat: payload.ContentFormat,
+	}
+
+	mentions, err := h.resolveMentions(comment)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to resolve mentions")
+		return
 	}
 
-	if err := h.CommentsStore.CreateComment(comment); err != nil {
+	if err := h.CommentsStore.CreateComment(comment, mentions); err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	commentResponse, err := h.fetchCommentDetails(comment.ID)
+	commentResponse, err := h.fetchCommentDetails(comment.ID, utils.ViewerID(r))
 	if err != nil {
 		utils.WriteError(w, http.StatusNotFound, err.Error())
 		return
@@ -301,14 +400,28 @@ func (h *CommentsHandler) UpdateCommentHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	if COMMENT_EDIT_WINDOW > 0 && time.Since(time.Unix(comment.CreatedAt, 0)) > COMMENT_EDIT_WINDOW {
+		utils.WriteError(w, http.StatusForbidden, "the edit window for this comment has expired")
+		return
+	}
+
 	comment.Content = payload.Content
+	if payload.ContentFormat != "" {
+		comment.ContentFormat = payload.ContentFormat
+	}
 
-	if err := h.CommentsStore.UpdateComment(commentID, comment); err != nil {
+	mentions, err := h.resolveMentions(comment)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to resolve mentions")
+		return
+	}
+
+	if err := h.CommentsStore.UpdateComment(commentID, comment, comment.AuthorID, mentions); err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	commentResponse, err := h.fetchCommentDetails(commentID)
+	commentResponse, err := h.fetchCommentDetails(commentID, utils.ViewerID(r))
 	if err != nil {
 		utils.WriteError(w, http.StatusNotFound, err.Error())
 		return
@@ -324,15 +437,32 @@ func (h *CommentsHandler) DeleteCommentHandler(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	reason, err := utils.ParseDeleteReason(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	authUserID := r.Context().Value(constants.UserIDKey).(string)
 
-	_, err = h.verifyCommentOwnership(commentID, authUserID)
+	comment, err := h.CommentsStore.GetCommentByID(commentID)
+	if err != nil || comment.DeletedAt.Valid {
+		utils.WriteError(w, http.StatusNotFound, "comment not found")
+		return
+	}
+
+	user, err := h.AuthenticationStore.GetUserByID(authUserID)
 	if err != nil {
-		utils.WriteError(w, http.StatusUnauthorized, err.Error())
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if comment.AuthorID != user.ID && !utils.IsStaff(user.Role) {
+		utils.WriteError(w, http.StatusUnauthorized, "user does not own the resource")
 		return
 	}
 
-	if err := h.CommentsStore.DeleteComment(commentID); err != nil {
+	if err := h.CommentsStore.DeleteComment(commentID, user.ID, reason); err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -340,7 +470,7 @@ func (h *CommentsHandler) DeleteCommentHandler(w http.ResponseWriter, r *http.Re
 	utils.WriteJSON(w, http.StatusNoContent, nil)
 }
 
-func (h *CommentsHandler) LikeCommentHandler(w http.ResponseWriter, r *http.Request) {
+func (h *CommentsHandler) RestoreCommentHandler(w http.ResponseWriter, r *http.Request) {
 	commentID, err := uuid.Parse(chi.URLParam(r, "commentID"))
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err.Error())
@@ -349,61 +479,445 @@ func (h *CommentsHandler) LikeCommentHandler(w http.ResponseWriter, r *http.Requ
 
 	authUserID := r.Context().Value(constants.UserIDKey).(string)
 
-	if err := h.handleCommentLikeDislike(uuid.MustParse(authUserID), commentID, "like"); err != nil {
+	comment, err := h.CommentsStore.GetDeletedCommentByID(commentID)
+	if err != nil {
+		utils.WriteError(w, http.StatusNotFound, "deleted comment not found")
+		return
+	}
+
+	user, err := h.AuthenticationStore.GetUserByID(authUserID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err := utils.CanRestore(user.ID, user.Role, comment.AuthorID, comment.DeletedByID, comment.DeletedAt.Time); err != nil {
+		utils.WriteError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if err := h.CommentsStore.RestoreComment(commentID); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	commentResponse, err := h.fetchCommentDetails(commentID, user.ID)
+	if err != nil {
+		utils.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, commentResponse)
+}
+
+func (h *CommentsHandler) handleLikeDislikeRequest(w http.ResponseWriter, r *http.Request, action string) {
+	commentID, err := uuid.Parse(chi.URLParam(r, "commentID"))
+	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	authUserID := r.Context().Value(constants.UserIDKey).(string)
+	userUUID := uuid.MustParse(authUserID)
+
+	if _, ok := h.verifyVisibility(w, commentID, userUUID); !ok {
+		return
+	}
+
+	if err := h.handleCommentLikeDislike(userUUID, commentID, action); err != nil {
+		writeLikeDislikeError(w, action, err)
+		return
+	}
+
 	utils.WriteJSON(w, http.StatusOK, nil)
 }
 
+func (h *CommentsHandler) LikeCommentHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleLikeDislikeRequest(w, r, "like")
+}
+
 func (h *CommentsHandler) UnlikeCommentHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleLikeDislikeRequest(w, r, "unlike")
+}
+
+func (h *CommentsHandler) DislikeCommentHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleLikeDislikeRequest(w, r, "dislike")
+}
+
+func (h *CommentsHandler) UndislikeCommentHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleLikeDislikeRequest(w, r, "undislike")
+}
+
+func (h *CommentsHandler) parseReactionRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, constants.ReactionType, bool) {
 	commentID, err := uuid.Parse(chi.URLParam(r, "commentID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return uuid.Nil, uuid.Nil, "", false
+	}
+
+	reactionType := constants.ReactionType(chi.URLParam(r, "reactionType"))
+	if !utils.IsValidReactionType(reactionType) {
+		utils.WriteError(w, http.StatusBadRequest, "invalid reaction type")
+		return uuid.Nil, uuid.Nil, "", false
+	}
+
+	userUUID := utils.ViewerID(r)
+	if userUUID == uuid.Nil {
+		utils.WriteError(w, http.StatusUnauthorized, "user ID not found in context")
+		return uuid.Nil, uuid.Nil, "", false
+	}
+
+	if _, ok := h.verifyVisibility(w, commentID, userUUID); !ok {
+		return uuid.Nil, uuid.Nil, "", false
+	}
+
+	return commentID, userUUID, reactionType, true
+}
+
+func (h *CommentsHandler) PutCommentReactionHandler(w http.ResponseWriter, r *http.Request) {
+	commentID, userUUID, reactionType, ok := h.parseReactionRequest(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.CommentsStore.ReactToComment(userUUID, commentID, reactionType)
+	if err != nil && !errors.Is(err, posts.ErrAlreadyReacted) {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.WriteError(w, http.StatusNotFound, "comment not found")
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	commentResponse, _ := h.fetchCommentDetails(commentID, userUUID)
+	utils.WriteJSON(w, http.StatusOK, commentResponse)
+}
+
+func (h *CommentsHandler) DeleteCommentReactionHandler(w http.ResponseWriter, r *http.Request) {
+	commentID, userUUID, reactionType, ok := h.parseReactionRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.CommentsStore.RemoveCommentReaction(userUUID, commentID, reactionType); err != nil {
+		switch {
+		case errors.Is(err, posts.ErrReactionNotFound):
+			utils.WriteError(w, http.StatusNotFound, "reaction not found")
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.WriteError(w, http.StatusNotFound, "comment not found")
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	commentResponse, _ := h.fetchCommentDetails(commentID, userUUID)
+	utils.WriteJSON(w, http.StatusOK, commentResponse)
+}
+
+func (h *CommentsHandler) GetCommentReactionsHandler(w http.ResponseWriter, r *http.Request) {
+	commentID, err := uuid.Parse(chi.URLParam(r, "commentID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reactionType := constants.ReactionType(r.URL.Query().Get("type"))
+	if reactionType != "" && !utils.IsValidReactionType(reactionType) {
+		utils.WriteError(w, http.StatusBadRequest, "invalid reaction type")
+		return
+	}
+
+	if _, ok := h.verifyVisibility(w, commentID, utils.ViewerID(r)); !ok {
+		return
+	}
+
+	limit := r.Context().Value(constants.LimitKey).(int)
+	offset := r.Context().Value(constants.OffsetKey).(int)
+
+	reactions, err := h.CommentsStore.GetCommentReactions(commentID, reactionType, limit+1, offset)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	reactions, pageInfo, err := utils.PaginateResults(r, reactions)
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
+	if utils.IncludeTotal(r) {
+		total, err := h.CommentsStore.CountCommentReactions(commentID, reactionType)
+		if err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		pageInfo.Total = &total
+	}
+
+	reactionResponses := make([]commentReactionResponse, 0, len(reactions))
+	for _, reaction := range reactions {
+		definition, _ := utils.LookupReaction(reaction.Type)
+		reactionResponses = append(reactionResponses, commentReactionResponse{
+			User: commentCreateUpdateResponseAuthor{
+				ID:        reaction.User.ID,
+				FirstName: reaction.User.FirstName,
+				LastName:  reaction.User.LastName,
+				Email:     reaction.User.Email,
+			},
+			Type:      reaction.Type,
+			Emoji:     definition.Emoji,
+			CreatedAt: reaction.CreatedAt,
+		})
+	}
+
+	utils.WritePage(w, r, http.StatusOK, reactionResponses, pageInfo)
+}
+
+func (h *CommentsHandler) CreateReplyHandler(w http.ResponseWriter, r *http.Request) {
+	parentID, err := uuid.Parse(chi.URLParam(r, "commentID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var payload commentCreateUpdatePayload
+	if err := utils.ParseJSON(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := validate.Struct(payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	parent, ok := h.verifyVisibility(w, parentID, utils.ViewerID(r))
+	if !ok {
+		return
+	}
+
+	if parent.DeletedAt.Valid {
+		utils.WriteError(w, http.StatusConflict, "cannot reply to a deleted comment")
+		return
+	}
+
+	if parent.Post.Status != constants.PostStatusPublished {
+		utils.WriteError(w, http.StatusConflict, "comments can only be added to published posts")
+		return
+	}
+
+	if parent.Depth+1 > MAX_COMMENT_DEPTH {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Sprintf("replies cannot be nested more than %d levels deep", MAX_COMMENT_DEPTH))
+		return
+	}
+
 	authUserID := r.Context().Value(constants.UserIDKey).(string)
 
-	if err := h.handleCommentLikeDislike(uuid.MustParse(authUserID), commentID, "unlike"); err != nil {
+	comment := &Comment{
+		AuthorID:      uuid.MustParse(authUserID),
+		PostID:        parent.PostID,
+		ParentID:      &parent.ID,
+		Depth:         parent.Depth + 1,
+		Content:       payload.Content,
+		ContentFormat: payload.ContentFormat,
+	}
+
+	mentions, err := h.resolveMentions(comment)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to resolve mentions")
+		return
+	}
+
+	if err := h.CommentsStore.CreateComment(comment, mentions); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	commentResponse, err := h.fetchCommentDetails(comment.ID, utils.ViewerID(r))
+	if err != nil {
+		utils.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, commentResponse)
+}
+
+func (h *CommentsHandler) GetCommentRepliesHandler(w http.ResponseWriter, r *http.Request) {
+	commentID, err := uuid.Parse(chi.URLParam(r, "commentID"))
+	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	utils.WriteJSON(w, http.StatusOK, nil)
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = constants.CommentTreeFormatNested
+	}
+	if format != constants.CommentTreeFormatNested && format != constants.CommentTreeFormatFlat {
+		utils.WriteError(w, http.StatusBadRequest, "invalid format: must be nested or flat")
+		return
+	}
+
+	maxDepth, err := utils.ParseLimitOffsetQueryParam(r, "max_depth", MAX_COMMENT_DEPTH, 0, MAX_COMMENT_DEPTH)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, ok := h.verifyVisibility(w, commentID, utils.ViewerID(r)); !ok {
+		return
+	}
+
+	comments, err := h.CommentsStore.GetCommentSubtree(commentID, maxDepth, constants.MaxCommentTreeSize)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.WriteError(w, http.StatusNotFound, "comment not found")
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	commentResponses, err := h.buildCommentResponses(comments, utils.ViewerID(r))
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if format == constants.CommentTreeFormatFlat {
+		utils.WriteJSON(w, http.StatusOK, commentResponses)
+		return
+	}
+
+	nodes := make(map[uuid.UUID]*commentTreeResponse, len(commentResponses))
+	var root *commentTreeResponse
+	for _, commentResponse := range commentResponses {
+		node := &commentTreeResponse{commentCreateUpdateResponse: commentResponse, Children: make([]*commentTreeResponse, 0)}
+		nodes[node.ID] = node
+
+		if node.ID == commentID {
+			root = node
+			continue
+		}
+		if parent, ok := nodes[*node.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		}
+	}
+
+	utils.WriteJSON(w, http.StatusOK, root)
 }
 
-func (h *CommentsHandler) DislikeCommentHandler(w http.ResponseWriter, r *http.Request) {
+func (h *CommentsHandler) GetCommentRevisionsHandler(w http.ResponseWriter, r *http.Request) {
 	commentID, err := uuid.Parse(chi.URLParam(r, "commentID"))
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	authUserID := r.Context().Value(constants.UserIDKey).(string)
+	comment, ok := h.verifyVisibility(w, commentID, utils.ViewerID(r))
+	if !ok {
+		return
+	}
+
+	if comment.DeletedAt.Valid {
+		utils.WriteError(w, http.StatusNotFound, "comment not found")
+		return
+	}
+
+	limit := r.Context().Value(constants.LimitKey).(int)
+	offset := r.Context().Value(constants.OffsetKey).(int)
+
+	revisions, err := h.CommentsStore.GetCommentRevisions(commentID, limit+1, offset)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
 
-	if err := h.handleCommentLikeDislike(uuid.MustParse(authUserID), commentID, "dislike"); err != nil {
+	revisions, pageInfo, err := utils.PaginateResults(r, revisions)
+	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	utils.WriteJSON(w, http.StatusOK, nil)
+	if utils.IncludeTotal(r) {
+		total, err := h.CommentsStore.CountCommentRevisions(commentID)
+		if err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		pageInfo.Total = &total
+	}
+
+	revisionResponses := make([]commentRevisionResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		revisionResponses = append(revisionResponses, commentRevisionResponse{
+			Version: revision.Version,
+			Editor: commentCreateUpdateResponseAuthor{
+				ID:        revision.Editor.ID,
+				FirstName: revision.Editor.FirstName,
+				LastName:  revision.Editor.LastName,
+				Email:     revision.Editor.Email,
+			},
+			Content:   revision.Content,
+			CreatedAt: revision.CreatedAt,
+		})
+	}
+
+	utils.WritePage(w, r, http.StatusOK, revisionResponses, pageInfo)
 }
 
-func (h *CommentsHandler) UndislikeCommentHandler(w http.ResponseWriter, r *http.Request) {
+func (h *CommentsHandler) GetCommentRevisionsDiffHandler(w http.ResponseWriter, r *http.Request) {
 	commentID, err := uuid.Parse(chi.URLParam(r, "commentID"))
 	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	authUserID := r.Context().Value(constants.UserIDKey).(string)
+	comment, ok := h.verifyVisibility(w, commentID, utils.ViewerID(r))
+	if !ok {
+		return
+	}
+
+	if comment.DeletedAt.Valid {
+		utils.WriteError(w, http.StatusNotFound, "comment not found")
+		return
+	}
+
+	if comment.EditCount == 0 {
+		utils.WriteError(w, http.StatusNotFound, "comment has no revisions")
+		return
+	}
 
-	if err := h.handleCommentLikeDislike(uuid.MustParse(authUserID), commentID, "undislike"); err != nil {
+	to, err := utils.ParseRevisionVersion(r, "to", comment.EditCount+1)
+	if err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	utils.WriteJSON(w, http.StatusOK, nil)
+	from, err := utils.ParseRevisionVersion(r, "from", max(to-1, 1))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	fromRevision, err := h.CommentsStore.GetCommentRevision(commentID, from)
+	if err != nil {
+		utils.WriteError(w, http.StatusNotFound, "revision not found")
+		return
+	}
+
+	toRevision, err := h.CommentsStore.GetCommentRevision(commentID, to)
+	if err != nil {
+		utils.WriteError(w, http.StatusNotFound, "revision not found")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, commentRevisionDiffResponse{
+		From:    from,
+		To:      to,
+		Content: utils.DiffLines(fromRevision.Content, toRevision.Content),
+	})
 }
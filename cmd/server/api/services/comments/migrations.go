@@ -0,0 +1,66 @@
+package comments
+
+import "gopher-social-backend-server/internal/database"
+
+const RecomputeCountersSQL = `
+	WITH RECURSIVE visible AS (
+		SELECT id, parent_id FROM comments WHERE deleted_at IS NULL
+		UNION
+		SELECT parent.id, parent.parent_id FROM comments parent JOIN visible ON visible.parent_id = parent.id
+	)
+	UPDATE comments SET
+		replies_count = (SELECT COUNT(*) FROM visible WHERE visible.parent_id = comments.id),
+		likes_count = (SELECT COUNT(*) FROM comment_reactions WHERE comment_reactions.comment_id = comments.id AND comment_reactions.type = 'like'),
+		dislikes_count = (SELECT COUNT(*) FROM comment_reactions WHERE comment_reactions.comment_id = comments.id AND comment_reactions.type = 'dislike'),
+		reaction_counts = COALESCE((
+			SELECT jsonb_object_agg(type, total) FROM (
+				SELECT type, COUNT(*) AS total FROM comment_reactions WHERE comment_reactions.comment_id = comments.id GROUP BY type
+			) counts
+		), '{}'::jsonb);
+`
+
+var Migrations = []database.Migration{
+	{
+		ID:  "0007_backfill_comment_counters",
+		SQL: RecomputeCountersSQL,
+	},
+	{
+		ID: "0009_comment_likes_dislikes_to_reactions",
+		SQL: `
+			DO $$
+			BEGIN
+				IF to_regclass('comment_likes') IS NOT NULL THEN
+					INSERT INTO comment_reactions (user_id, comment_id, type, created_at, updated_at)
+					SELECT DISTINCT ON (user_id, comment_id) user_id, comment_id, 'like', EXTRACT(EPOCH FROM now())::bigint, EXTRACT(EPOCH FROM now())::bigint
+					FROM comment_likes
+					ON CONFLICT (user_id, comment_id) DO NOTHING;
+					DROP TABLE comment_likes;
+				END IF;
+
+				IF to_regclass('comment_dislikes') IS NOT NULL THEN
+					INSERT INTO comment_reactions (user_id, comment_id, type, created_at, updated_at)
+					SELECT DISTINCT ON (user_id, comment_id) user_id, comment_id, 'dislike', EXTRACT(EPOCH FROM now())::bigint, EXTRACT(EPOCH FROM now())::bigint
+					FROM comment_dislikes
+					ON CONFLICT (user_id, comment_id) DO NOTHING;
+					DROP TABLE comment_dislikes;
+				END IF;
+			END $$;
+		` + RecomputeCountersSQL,
+	},
+	{
+		ID:  "0011_backfill_comment_reaction_counts",
+		SQL: RecomputeCountersSQL,
+	},
+	{
+		ID: "0012_comments_removed_to_deleted_at",
+		SQL: `
+			DO $$
+			BEGIN
+				IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = 'comments' AND column_name = 'removed') THEN
+					UPDATE comments SET deleted_at = now() WHERE removed AND deleted_at IS NULL;
+					ALTER TABLE comments DROP COLUMN removed;
+				END IF;
+			END $$;
+		`,
+	},
+}
@@ -3,33 +3,66 @@ package comments
 import (
 	"gopher-social-backend-server/cmd/server/api/services/authentication"
 	"gopher-social-backend-server/cmd/server/api/services/posts"
+	"gopher-social-backend-server/pkg/constants"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Comment struct {
-	ID        uuid.UUID           `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	AuthorID  uuid.UUID           `json:"author_id" gorm:"type:uuid;not null;index"`
-	Author    authentication.User `json:"author" gorm:"foreignKey:AuthorID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
-	PostID    uuid.UUID           `json:"post_id" gorm:"type:uuid;not null;index"`
-	Post      posts.Post          `json:"post" gorm:"foreignKey:PostID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
-	Content   string              `json:"content" gorm:"type:text;not null"`
-	CreatedAt int64               `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt int64               `json:"updated_at" gorm:"autoUpdateTime"`
+	ID                   uuid.UUID                        `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	AuthorID             uuid.UUID                        `json:"author_id" gorm:"type:uuid;not null;index"`
+	Author               authentication.User              `json:"author" gorm:"foreignKey:AuthorID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	PostID               uuid.UUID                        `json:"post_id" gorm:"type:uuid;not null;index"`
+	Post                 posts.Post                       `json:"post" gorm:"foreignKey:PostID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	ParentID             *uuid.UUID                       `json:"parent_id" gorm:"type:uuid;index"`
+	Parent               *Comment                         `json:"-" gorm:"foreignKey:ParentID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL"`
+	Depth                int                              `json:"depth" gorm:"not null;default:0"`
+	Content              string                           `json:"content" gorm:"type:text;not null"`
+	ContentFormat        constants.ContentFormat          `json:"content_format" gorm:"type:varchar(16);not null;default:'plain'"`
+	ContentHTML          string                           `json:"content_html" gorm:"type:text;not null;default:''"`
+	ContentRenderVersion int                              `json:"-" gorm:"not null;default:0"`
+	RepliesCount         int64                            `json:"replies_count" gorm:"not null;default:0;index"`
+	EditCount            int64                            `json:"edit_count" gorm:"not null;default:0"`
+	LikesCount           int64                            `json:"likes_count" gorm:"not null;default:0;index"`
+	DislikesCount        int64                            `json:"dislikes_count" gorm:"not null;default:0;index"`
+	ReactionCounts       map[constants.ReactionType]int64 `json:"reaction_counts" gorm:"type:jsonb;not null;default:'{}';serializer:json"`
+	CreatedAt            int64                            `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt            int64                            `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt            gorm.DeletedAt                   `json:"-" gorm:"index"`
+	DeletedByID          *uuid.UUID                       `json:"-" gorm:"type:uuid"`
+	DeleteReason         string                           `json:"-" gorm:"type:varchar(255)"`
 }
 
-type CommentLike struct {
+type CommentReaction struct {
+	ID        uuid.UUID              `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserID    uuid.UUID              `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_comment_reactions_user_comment"`
+	User      authentication.User    `json:"user" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	CommentID uuid.UUID              `json:"comment_id" gorm:"type:uuid;not null;uniqueIndex:idx_comment_reactions_user_comment;index;index:idx_comment_reactions_comment_type"`
+	Comment   Comment                `json:"comment" gorm:"foreignKey:CommentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Type      constants.ReactionType `json:"type" gorm:"type:varchar(32);not null;index:idx_comment_reactions_comment_type"`
+	CreatedAt int64                  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt int64                  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+type CommentRevision struct {
 	ID        uuid.UUID           `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	UserID    uuid.UUID           `json:"user_id" gorm:"type:uuid;not null;index"`
-	User      authentication.User `json:"user" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
-	CommentID uuid.UUID           `json:"comment_id" gorm:"type:uuid;not null;index"`
+	CommentID uuid.UUID           `json:"comment_id" gorm:"type:uuid;not null;uniqueIndex:idx_comment_revisions_comment_version"`
 	Comment   Comment             `json:"comment" gorm:"foreignKey:CommentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Version   int64               `json:"version" gorm:"not null;uniqueIndex:idx_comment_revisions_comment_version"`
+	EditorID  uuid.UUID           `json:"editor_id" gorm:"type:uuid;not null;index"`
+	Editor    authentication.User `json:"editor" gorm:"foreignKey:EditorID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Content   string              `json:"content" gorm:"type:text;not null"`
+	CreatedAt int64               `json:"created_at" gorm:"autoCreateTime"`
 }
 
-type CommentDislike struct {
+type CommentMention struct {
 	ID        uuid.UUID           `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	CommentID uuid.UUID           `json:"comment_id" gorm:"type:uuid;not null;uniqueIndex:idx_comment_mentions_comment_start"`
+	Comment   Comment             `json:"comment" gorm:"foreignKey:CommentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
 	UserID    uuid.UUID           `json:"user_id" gorm:"type:uuid;not null;index"`
 	User      authentication.User `json:"user" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
-	CommentID uuid.UUID           `json:"comment_id" gorm:"type:uuid;not null;index"`
-	Comment   Comment             `json:"comment" gorm:"foreignKey:CommentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Start     int                 `json:"start" gorm:"not null;uniqueIndex:idx_comment_mentions_comment_start"`
+	End       int                 `json:"end" gorm:"not null"`
+	CreatedAt int64               `json:"created_at" gorm:"autoCreateTime"`
 }
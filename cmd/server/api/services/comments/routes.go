@@ -2,18 +2,30 @@ package comments
 
 import (
 	"gopher-social-backend-server/internal/middlewares"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/ratelimiter"
 
 	"github.com/go-chi/chi/v5"
 )
 
 func RegisterCommentsRoutes(router chi.Router, handler *CommentsHandler) {
-	router.Get("/comments/{commentID}", handler.GetCommentByIDHandler)
-	router.With(middlewares.PaginationMiddleware, middlewares.OrderingMiddleware).Get("/posts/{postID}/comments", handler.GetCommentsForPostHandler)
+	diffRateLimiter := ratelimiter.NewRateLimiter(DIFF_RATE_LIMIT)
+
+	router.With(middlewares.OptionalAuthMiddleware).Get("/comments/{commentID}", handler.GetCommentByIDHandler)
+	router.With(middlewares.OptionalAuthMiddleware, middlewares.CursorPaginationMiddleware, middlewares.OrderingMiddleware(constants.CommentSortColumns)).Get("/posts/{postID}/comments", handler.GetCommentsForPostHandler)
+	router.With(middlewares.OptionalAuthMiddleware).Get("/comments/{commentID}/replies", handler.GetCommentRepliesHandler)
+	router.With(middlewares.OptionalAuthMiddleware, middlewares.PaginationMiddleware).Get("/comments/{commentID}/reactions", handler.GetCommentReactionsHandler)
+	router.With(middlewares.OptionalAuthMiddleware, middlewares.PaginationMiddleware).Get("/comments/{commentID}/revisions", handler.GetCommentRevisionsHandler)
+	router.With(middlewares.OptionalAuthMiddleware, middlewares.RateLimiterMiddleware(diffRateLimiter)).Get("/comments/{commentID}/revisions/diff", handler.GetCommentRevisionsDiffHandler)
 	router.With(middlewares.AuthMiddleware).Post("/posts/{postID}/comments", handler.CreateCommentHandler)
+	router.With(middlewares.AuthMiddleware).Post("/comments/{commentID}/replies", handler.CreateReplyHandler)
 	router.With(middlewares.AuthMiddleware).Put("/comments/{commentID}", handler.UpdateCommentHandler)
 	router.With(middlewares.AuthMiddleware).Delete("/comments/{commentID}", handler.DeleteCommentHandler)
+	router.With(middlewares.AuthMiddleware).Post("/comments/{commentID}/restore", handler.RestoreCommentHandler)
 	router.With(middlewares.AuthMiddleware).Post("/comments/{commentID}/like", handler.LikeCommentHandler)
 	router.With(middlewares.AuthMiddleware).Delete("/comments/{commentID}/like", handler.UnlikeCommentHandler)
 	router.With(middlewares.AuthMiddleware).Post("/comments/{commentID}/dislike", handler.DislikeCommentHandler)
 	router.With(middlewares.AuthMiddleware).Delete("/comments/{commentID}/dislike", handler.UndislikeCommentHandler)
+	router.With(middlewares.AuthMiddleware).Put("/comments/{commentID}/reactions/{reactionType}", handler.PutCommentReactionHandler)
+	router.With(middlewares.AuthMiddleware).Delete("/comments/{commentID}/reactions/{reactionType}", handler.DeleteCommentReactionHandler)
 }
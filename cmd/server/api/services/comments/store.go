@@ -1,24 +1,40 @@
 package comments
 
 import (
+	"gopher-social-backend-server/cmd/server/api/services/posts"
+	"gopher-social-backend-server/internal/database"
+	"gopher-social-backend-server/internal/events"
+	"gopher-social-backend-server/internal/pubsub"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/utils"
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CommentsStore interface {
-	CreateComment(comment *Comment) error
+	CreateComment(comment *Comment, mentions []CommentMention) error
 	GetCommentByID(commentID uuid.UUID) (*Comment, error)
-	GetCommentsForPost(postID uuid.UUID, limit, offset int, orderby string, desc bool) ([]Comment, error)
-	UpdateComment(commentID uuid.UUID, comment *Comment) error
-	DeleteComment(commentID uuid.UUID) error
-	LikeComment(like *CommentLike) error
-	RemoveLikeComment(userID, commentID uuid.UUID) error
-	DislikeComment(dislike *CommentDislike) error
-	RemoveDislikeComment(userID, commentID uuid.UUID) error
-	HasUserLikedComment(userID, commentID uuid.UUID) (bool, error)
-	HasUserDislikedComment(userID, commentID uuid.UUID) (bool, error)
-	GetLikesCount(commentId uuid.UUID) (int64, error)
-	GetDislikesCount(commentId uuid.UUID) (int64, error)
+	GetCommentsForPost(postID uuid.UUID, topLevel bool, limit, offset int, cursor *utils.Cursor, orderby string, desc bool) ([]Comment, error)
+	CountCommentsForPost(postID uuid.UUID, topLevel bool) (int64, error)
+	GetCommentSubtree(commentID uuid.UUID, maxDepth, limit int) ([]Comment, error)
+	UpdateComment(commentID uuid.UUID, comment *Comment, editorID uuid.UUID, mentions []CommentMention) error
+	GetCommentRevisions(commentID uuid.UUID, limit, offset int) ([]CommentRevision, error)
+	CountCommentRevisions(commentID uuid.UUID) (int64, error)
+	GetCommentRevision(commentID uuid.UUID, version int64) (*CommentRevision, error)
+	DeleteComment(commentID, deletedByID uuid.UUID, reason string) error
+	GetDeletedCommentByID(commentID uuid.UUID) (*Comment, error)
+	RestoreComment(commentID uuid.UUID) error
+	PurgeDeletedComments(before time.Time) (int64, error)
+	ReactToComment(userID, commentID uuid.UUID, reactionType constants.ReactionType) error
+	RemoveCommentReaction(userID, commentID uuid.UUID, reactionType constants.ReactionType) error
+	GetUserReactionsForComments(userID uuid.UUID, commentIDs []uuid.UUID) (map[uuid.UUID]constants.ReactionType, error)
+	GetCommentReactions(commentID uuid.UUID, reactionType constants.ReactionType, limit, offset int) ([]CommentReaction, error)
+	CountCommentReactions(commentID uuid.UUID, reactionType constants.ReactionType) (int64, error)
+	RecomputeCounters() error
+	GetMentionsForComments(commentIDs []uuid.UUID) (map[uuid.UUID][]CommentMention, error)
 }
 
 type commentsStore struct {
@@ -31,83 +47,608 @@ func NewCommentStore(postgresDB *gorm.DB) CommentsStore {
 	}
 }
 
-func (cs *commentsStore) CreateComment(comment *Comment) error {
-	return cs.postgresDB.Create(comment).Error
+func incrementCounter(tx *gorm.DB, model interface{}, id uuid.UUID, column string, delta int64) error {
+	return tx.Unscoped().Model(model).Where("id = ?", id).UpdateColumn(column, gorm.Expr(column+" + ?", delta)).Error
+}
+
+func visibleComments(db *gorm.DB) *gorm.DB {
+	return db.
+		Where("(comments.deleted_at IS NULL OR comments.replies_count > 0)").
+		Where("EXISTS (SELECT 1 FROM posts WHERE posts.id = comments.post_id AND posts.deleted_at IS NULL)")
+}
+
+func adjustReactionCount(tx *gorm.DB, commentID uuid.UUID, reactionType constants.ReactionType, delta int64) error {
+	updates := map[string]interface{}{
+		"reaction_counts": gorm.Expr(
+			"jsonb_set(reaction_counts, ARRAY[?::text], to_jsonb(COALESCE((reaction_counts->>?::text)::bigint, 0) + ?))",
+			reactionType, reactionType, delta,
+		),
+	}
+	if column, ok := constants.ReactionCounterColumns[reactionType]; ok {
+		updates[column] = gorm.Expr(column+" + ?", delta)
+	}
+
+	return tx.Model(&Comment{}).Where("id = ?", commentID).UpdateColumns(updates).Error
+}
+
+func renderCommentContent(comment *Comment) error {
+	if comment.ContentFormat == "" {
+		comment.ContentFormat = constants.ContentFormatPlain
+	}
+
+	rendered, err := utils.RenderContent(comment.ContentFormat, comment.Content)
+	if err != nil {
+		return err
+	}
+
+	comment.ContentHTML = rendered
+	comment.ContentRenderVersion = constants.ContentRenderVersion
+	return nil
+}
+
+func (cs *commentsStore) CreateComment(comment *Comment, mentions []CommentMention) error {
+	if comment.ReactionCounts == nil {
+		comment.ReactionCounts = make(map[constants.ReactionType]int64)
+	}
+
+	if err := renderCommentContent(comment); err != nil {
+		return err
+	}
+
+	return cs.postgresDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+
+		if comment.ParentID != nil {
+			if err := incrementCounter(tx, &Comment{}, *comment.ParentID, "replies_count", 1); err != nil {
+				return err
+			}
+		}
+
+		if err := incrementCounter(tx, &posts.Post{}, comment.PostID, "comments_count", 1); err != nil {
+			return err
+		}
+
+		mentioned, err := setCommentMentions(tx, comment.ID, mentions)
+		if err != nil {
+			return err
+		}
+
+		if err := recordCommentMentions(tx, comment, mentioned); err != nil {
+			return err
+		}
+
+		if err := events.Record(tx, constants.DomainEventCommentCreated, events.CommentCreated{
+			CommentID: comment.ID,
+			PostID:    comment.PostID,
+			ParentID:  comment.ParentID,
+			AuthorID:  comment.AuthorID,
+		}); err != nil {
+			return err
+		}
+
+		return publishComment(tx, comment, constants.StreamEventCommentCreated)
+	})
+}
+
+func publishComment(tx *gorm.DB, comment *Comment, eventType constants.StreamEventType) error {
+	return pubsub.Publish(tx, pubsub.PostTopic(comment.PostID), eventType, commentEvent{
+		ID:        comment.ID,
+		PostID:    comment.PostID,
+		ParentID:  comment.ParentID,
+		AuthorID:  comment.AuthorID,
+		Depth:     comment.Depth,
+		CreatedAt: comment.CreatedAt,
+	})
 }
 
 func (cs *commentsStore) GetCommentByID(commentID uuid.UUID) (*Comment, error) {
 	var comment Comment
-	err := cs.postgresDB.Preload("Author").Preload("Post").First(&comment, "id = ?", commentID).Error
+	err := cs.postgresDB.Unscoped().Preload("Author").Preload("Post.Author").Scopes(visibleComments).First(&comment, "comments.id = ?", commentID).Error
 	return &comment, err
 }
 
-func (cs *commentsStore) GetCommentsForPost(postID uuid.UUID, limit, offset int, orderby string, desc bool) ([]Comment, error) {
+func filterPostComments(postID uuid.UUID, topLevel bool) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("comments.post_id = ?", postID)
+		if topLevel {
+			db = db.Where("comments.parent_id IS NULL")
+		}
+		return db
+	}
+}
+
+func (cs *commentsStore) GetCommentsForPost(postID uuid.UUID, topLevel bool, limit, offset int, cursor *utils.Cursor, orderby string, desc bool) ([]Comment, error) {
 	var comments []Comment
 
-	order := orderby
-	if desc {
-		order += " DESC"
-	} else {
-		order += " ASC"
+	if err := cs.postgresDB.Unscoped().Preload("Author").Preload("Post.Author").Scopes(visibleComments, filterPostComments(postID, topLevel), database.Paginate("comments", limit, offset, cursor, orderby, desc)).Find(&comments).Error; err != nil {
+		return nil, err
 	}
 
-	if err := cs.postgresDB.Preload("Author").Preload("Post").Limit(limit).Offset(offset).Order(order).Find(&comments, "post_id = ?", postID).Error; err != nil {
+	return comments, nil
+}
+
+func (cs *commentsStore) CountCommentsForPost(postID uuid.UUID, topLevel bool) (int64, error) {
+	var count int64
+	err := cs.postgresDB.Unscoped().Model(&Comment{}).Scopes(visibleComments, filterPostComments(postID, topLevel)).Count(&count).Error
+	return count, err
+}
+
+func (cs *commentsStore) GetCommentSubtree(commentID uuid.UUID, maxDepth, limit int) ([]Comment, error) {
+	var commentIDs []uuid.UUID
+
+	if err := cs.postgresDB.Raw(`
+		WITH RECURSIVE thread AS (
+			SELECT id, 0 AS level, ARRAY[lpad(created_at::text, 20, '0') || id::text] AS path
+			FROM comments
+			WHERE id = ? AND (deleted_at IS NULL OR replies_count > 0)
+			UNION ALL
+			SELECT c.id, thread.level + 1, thread.path || (lpad(c.created_at::text, 20, '0') || c.id::text)
+			FROM comments c
+			JOIN thread ON c.parent_id = thread.id
+			WHERE thread.level < ? AND (c.deleted_at IS NULL OR c.replies_count > 0)
+		)
+		SELECT id FROM thread ORDER BY path LIMIT ?
+	`, commentID, maxDepth, limit).Scan(&commentIDs).Error; err != nil {
 		return nil, err
 	}
 
-	return comments, nil
+	if len(commentIDs) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	var comments []Comment
+	if err := cs.postgresDB.Unscoped().Preload("Author").Preload("Post.Author").Scopes(visibleComments).Where("comments.id IN ?", commentIDs).Find(&comments).Error; err != nil {
+		return nil, err
+	}
+
+	commentsByID := make(map[uuid.UUID]Comment, len(comments))
+	for _, comment := range comments {
+		commentsByID[comment.ID] = comment
+	}
+
+	ordered := make([]Comment, 0, len(commentIDs))
+	for _, id := range commentIDs {
+		if comment, ok := commentsByID[id]; ok {
+			ordered = append(ordered, comment)
+		}
+	}
+
+	return ordered, nil
+}
+
+func (cs *commentsStore) UpdateComment(commentID uuid.UUID, comment *Comment, editorID uuid.UUID, mentions []CommentMention) error {
+	return cs.postgresDB.Transaction(func(tx *gorm.DB) error {
+		var current Comment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "post_id", "parent_id", "author_id", "depth", "content", "content_format", "edit_count", "created_at").First(&current, "id = ?", commentID).Error; err != nil {
+			return err
+		}
+
+		comment.EditCount = current.EditCount
+		if current.Content == comment.Content && current.ContentFormat == comment.ContentFormat {
+			return nil
+		}
+
+		if err := renderCommentContent(comment); err != nil {
+			return err
+		}
+
+		if current.Content == comment.Content {
+			if err := tx.Model(&Comment{}).Where("id = ?", commentID).Select("content_format", "content_html", "content_render_version", "updated_at").Updates(comment).Error; err != nil {
+				return err
+			}
+			return publishComment(tx, &current, constants.StreamEventCommentUpdated)
+		}
+
+		if current.EditCount == 0 {
+			if err := tx.Create(&CommentRevision{
+				CommentID: current.ID,
+				Version:   1,
+				EditorID:  current.AuthorID,
+				Content:   current.Content,
+				CreatedAt: current.CreatedAt,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		comment.EditCount = current.EditCount + 1
+		if err := tx.Create(&CommentRevision{
+			CommentID: commentID,
+			Version:   comment.EditCount + 1,
+			EditorID:  editorID,
+			Content:   comment.Content,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&Comment{}).Where("id = ?", commentID).Select("content", "content_format", "content_html", "content_render_version", "edit_count", "updated_at").Updates(comment).Error; err != nil {
+			return err
+		}
+
+		added, err := setCommentMentions(tx, commentID, mentions)
+		if err != nil {
+			return err
+		}
+
+		if err := recordCommentMentions(tx, &current, added); err != nil {
+			return err
+		}
+
+		return publishComment(tx, &current, constants.StreamEventCommentUpdated)
+	})
 }
 
-func (cs *commentsStore) UpdateComment(commentID uuid.UUID, comment *Comment) error {
-	return cs.postgresDB.Model(&Comment{}).Where("id = ?", commentID).Updates(comment).Error
+func (cs *commentsStore) GetCommentRevisions(commentID uuid.UUID, limit, offset int) ([]CommentRevision, error) {
+	var revisions []CommentRevision
+
+	if err := cs.postgresDB.Preload("Editor").Where("comment_id = ?", commentID).
+		Order("version DESC").Limit(limit).Offset(offset).
+		Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
 }
 
-func (cs *commentsStore) DeleteComment(commentID uuid.UUID) error {
-	return cs.postgresDB.Delete(&Comment{}, "id = ?", commentID).Error
+func (cs *commentsStore) CountCommentRevisions(commentID uuid.UUID) (int64, error) {
+	var count int64
+	err := cs.postgresDB.Model(&CommentRevision{}).Where("comment_id = ?", commentID).Count(&count).Error
+	return count, err
 }
 
-func (cs *commentsStore) LikeComment(like *CommentLike) error {
-	return cs.postgresDB.Create(like).Error
+func (cs *commentsStore) GetCommentRevision(commentID uuid.UUID, version int64) (*CommentRevision, error) {
+	var revision CommentRevision
+	err := cs.postgresDB.Preload("Editor").First(&revision, "comment_id = ? AND version = ?", commentID, version).Error
+	return &revision, err
 }
 
-func (cs *commentsStore) RemoveLikeComment(userID, commentID uuid.UUID) error {
-	return cs.postgresDB.Where("user_id = ? AND comment_id = ?", userID, commentID).Delete(&CommentLike{}).Error
+func (cs *commentsStore) DeleteComment(commentID, deletedByID uuid.UUID, reason string) error {
+	return cs.postgresDB.Transaction(func(tx *gorm.DB) error {
+		var comment Comment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "post_id", "parent_id", "author_id", "depth", "replies_count", "created_at").First(&comment, "id = ?", commentID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&Comment{}).Where("id = ?", commentID).UpdateColumns(map[string]interface{}{
+			"deleted_at":    time.Now(),
+			"deleted_by_id": deletedByID,
+			"delete_reason": reason,
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := incrementCounter(tx, &posts.Post{}, comment.PostID, "comments_count", -1); err != nil {
+			return err
+		}
+
+		if err := publishComment(tx, &comment, constants.StreamEventCommentDeleted); err != nil {
+			return err
+		}
+
+		if comment.RepliesCount > 0 {
+			return nil
+		}
+
+		return hideAncestors(tx, comment.ParentID)
+	})
 }
 
-func (cs *commentsStore) DislikeComment(dislike *CommentDislike) error {
-	return cs.postgresDB.Create(dislike).Error
+func hideAncestors(tx *gorm.DB, parentID *uuid.UUID) error {
+	for parentID != nil {
+		if err := incrementCounter(tx, &Comment{}, *parentID, "replies_count", -1); err != nil {
+			return err
+		}
+
+		var parent Comment
+		if err := tx.Unscoped().Select("id", "parent_id", "deleted_at", "replies_count").First(&parent, "id = ?", *parentID).Error; err != nil {
+			return err
+		}
+
+		if !parent.DeletedAt.Valid || parent.RepliesCount > 0 {
+			return nil
+		}
+
+		parentID = parent.ParentID
+	}
+
+	return nil
 }
 
-func (cs *commentsStore) RemoveDislikeComment(userID, commentID uuid.UUID) error {
-	return cs.postgresDB.Where("user_id = ? AND comment_id = ?", userID, commentID).Delete(&CommentDislike{}).Error
+func revealAncestors(tx *gorm.DB, parentID *uuid.UUID) error {
+	for parentID != nil {
+		if err := incrementCounter(tx, &Comment{}, *parentID, "replies_count", 1); err != nil {
+			return err
+		}
+
+		var parent Comment
+		if err := tx.Unscoped().Select("id", "parent_id", "deleted_at", "replies_count").First(&parent, "id = ?", *parentID).Error; err != nil {
+			return err
+		}
+
+		if !parent.DeletedAt.Valid || parent.RepliesCount > 1 {
+			return nil
+		}
+
+		parentID = parent.ParentID
+	}
+
+	return nil
 }
 
-func (cs *commentsStore) HasUserLikedComment(userID, commentID uuid.UUID) (bool, error) {
-	var like CommentLike
-	err := cs.postgresDB.First(&like, "user_id = ? AND comment_id = ?", userID, commentID).Error
-	if err != nil {
-		return false, err
+func (cs *commentsStore) GetDeletedCommentByID(commentID uuid.UUID) (*Comment, error) {
+	var comment Comment
+	err := cs.postgresDB.Unscoped().Preload("Author").Where("deleted_at IS NOT NULL").First(&comment, "id = ?", commentID).Error
+	return &comment, err
+}
+
+func (cs *commentsStore) RestoreComment(commentID uuid.UUID) error {
+	return cs.postgresDB.Transaction(func(tx *gorm.DB) error {
+		var comment Comment
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "post_id", "parent_id", "author_id", "depth", "replies_count", "created_at").
+			Where("deleted_at IS NOT NULL").First(&comment, "id = ?", commentID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Unscoped().Model(&Comment{}).Where("id = ?", commentID).UpdateColumns(map[string]interface{}{
+			"deleted_at":    nil,
+			"deleted_by_id": nil,
+			"delete_reason": "",
+		}).Error; err != nil {
+			return err
+		}
+
+		if err := incrementCounter(tx, &posts.Post{}, comment.PostID, "comments_count", 1); err != nil {
+			return err
+		}
+
+		if err := publishComment(tx, &comment, constants.StreamEventCommentRestored); err != nil {
+			return err
+		}
+
+		if comment.RepliesCount > 0 {
+			return nil
+		}
+
+		return revealAncestors(tx, comment.ParentID)
+	})
+}
+
+func (cs *commentsStore) PurgeDeletedComments(before time.Time) (int64, error) {
+	result := cs.postgresDB.Unscoped().Where("deleted_at < ? AND replies_count = 0", before).Delete(&Comment{})
+	return result.RowsAffected, result.Error
+}
+
+func publishReactions(tx *gorm.DB, commentID uuid.UUID) error {
+	var comment Comment
+	if err := tx.Select("id", "post_id", "likes_count", "dislikes_count", "reaction_counts").First(&comment, "id = ?", commentID).Error; err != nil {
+		return err
 	}
-	return true, nil
+
+	return pubsub.Publish(tx, pubsub.PostTopic(comment.PostID), constants.StreamEventReactionsUpdated, reactionsEvent{
+		PostID:    comment.PostID,
+		CommentID: &comment.ID,
+		Likes:     comment.LikesCount,
+		Dislikes:  comment.DislikesCount,
+		Reactions: utils.ReactionCounts(comment.ReactionCounts),
+	})
+}
+
+func recordReaction(tx *gorm.DB, userID, commentID uuid.UUID, reactionType, previous constants.ReactionType) error {
+	var comment Comment
+	if err := tx.Select("id", "post_id").First(&comment, "id = ?", commentID).Error; err != nil {
+		return err
+	}
+
+	return events.Record(tx, constants.DomainEventReactionChanged, events.ReactionChanged{
+		UserID:    userID,
+		PostID:    comment.PostID,
+		CommentID: &comment.ID,
+		Type:      reactionType,
+		Previous:  previous,
+	})
 }
 
-func (cs *commentsStore) HasUserDislikedComment(userID, commentID uuid.UUID) (bool, error) {
-	var dislike CommentDislike
-	err := cs.postgresDB.First(&dislike, "user_id = ? AND comment_id = ?", userID, commentID).Error
+func lockReaction(tx *gorm.DB, userID, commentID uuid.UUID) (*CommentReaction, error) {
+	var comment Comment
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&comment, "id = ?", commentID).Error; err != nil {
+		return nil, err
+	}
+
+	var reaction CommentReaction
+	err := tx.Where("user_id = ? AND comment_id = ?", userID, commentID).First(&reaction).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
 	if err != nil {
-		return false, err
+		return nil, err
 	}
-	return true, nil
+
+	return &reaction, nil
 }
 
-func (cs *commentsStore) GetLikesCount(commentId uuid.UUID) (int64, error) {
-	var count int64
-	err := cs.postgresDB.Model(&CommentLike{}).Where("comment_id = ?", commentId).Count(&count).Error
-	return count, err
+func (cs *commentsStore) ReactToComment(userID, commentID uuid.UUID, reactionType constants.ReactionType) error {
+	return cs.postgresDB.Transaction(func(tx *gorm.DB) error {
+		existing, err := lockReaction(tx, userID, commentID)
+		if err != nil {
+			return err
+		}
+
+		if existing != nil && existing.Type == reactionType {
+			return posts.ErrAlreadyReacted
+		}
+
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "comment_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"type", "updated_at"}),
+		}).Create(&CommentReaction{UserID: userID, CommentID: commentID, Type: reactionType}).Error; err != nil {
+			return err
+		}
+
+		if existing != nil {
+			if err := adjustReactionCount(tx, commentID, existing.Type, -1); err != nil {
+				return err
+			}
+		}
+
+		if err := adjustReactionCount(tx, commentID, reactionType, 1); err != nil {
+			return err
+		}
+
+		previous := constants.ReactionType("")
+		if existing != nil {
+			previous = existing.Type
+		}
+		if err := recordReaction(tx, userID, commentID, reactionType, previous); err != nil {
+			return err
+		}
+
+		return publishReactions(tx, commentID)
+	})
+}
+
+func (cs *commentsStore) RemoveCommentReaction(userID, commentID uuid.UUID, reactionType constants.ReactionType) error {
+	return cs.postgresDB.Transaction(func(tx *gorm.DB) error {
+		existing, err := lockReaction(tx, userID, commentID)
+		if err != nil {
+			return err
+		}
+
+		if existing == nil || existing.Type != reactionType {
+			return posts.ErrReactionNotFound
+		}
+
+		if err := tx.Delete(existing).Error; err != nil {
+			return err
+		}
+
+		if err := adjustReactionCount(tx, commentID, reactionType, -1); err != nil {
+			return err
+		}
+
+		if err := recordReaction(tx, userID, commentID, "", reactionType); err != nil {
+			return err
+		}
+
+		return publishReactions(tx, commentID)
+	})
+}
+
+func (cs *commentsStore) GetUserReactionsForComments(userID uuid.UUID, commentIDs []uuid.UUID) (map[uuid.UUID]constants.ReactionType, error) {
+	reactionsByComment := make(map[uuid.UUID]constants.ReactionType, len(commentIDs))
+	if userID == uuid.Nil || len(commentIDs) == 0 {
+		return reactionsByComment, nil
+	}
+
+	var reactions []CommentReaction
+	if err := cs.postgresDB.Select("comment_id", "type").Where("user_id = ? AND comment_id IN ?", userID, commentIDs).Find(&reactions).Error; err != nil {
+		return nil, err
+	}
+
+	for _, reaction := range reactions {
+		reactionsByComment[reaction.CommentID] = reaction.Type
+	}
+
+	return reactionsByComment, nil
+}
+
+func filterReactions(commentID uuid.UUID, reactionType constants.ReactionType) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("comment_id = ?", commentID)
+		if reactionType != "" {
+			db = db.Where("type = ?", reactionType)
+		}
+		return db
+	}
+}
+
+func (cs *commentsStore) GetCommentReactions(commentID uuid.UUID, reactionType constants.ReactionType, limit, offset int) ([]CommentReaction, error) {
+	var reactions []CommentReaction
+
+	if err := cs.postgresDB.Preload("User").Scopes(filterReactions(commentID, reactionType)).
+		Order("created_at DESC, id").Limit(limit).Offset(offset).
+		Find(&reactions).Error; err != nil {
+		return nil, err
+	}
+
+	return reactions, nil
 }
 
-func (cs *commentsStore) GetDislikesCount(commentId uuid.UUID) (int64, error) {
+func (cs *commentsStore) CountCommentReactions(commentID uuid.UUID, reactionType constants.ReactionType) (int64, error) {
 	var count int64
-	err := cs.postgresDB.Model(&CommentDislike{}).Where("comment_id = ?", commentId).Count(&count).Error
+	err := cs.postgresDB.Model(&CommentReaction{}).Scopes(filterReactions(commentID, reactionType)).Count(&count).Error
 	return count, err
 }
+
+func (cs *commentsStore) RecomputeCounters() error {
+	return cs.postgresDB.Exec(RecomputeCountersSQL).Error
+}
+
+func setCommentMentions(tx *gorm.DB, commentID uuid.UUID, mentions []CommentMention) ([]uuid.UUID, error) {
+	var existing []uuid.UUID
+	if err := tx.Model(&CommentMention{}).Where("comment_id = ?", commentID).Distinct().Pluck("user_id", &existing).Error; err != nil {
+		return nil, err
+	}
+
+	if err := tx.Where("comment_id = ?", commentID).Delete(&CommentMention{}).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range mentions {
+		mentions[i].CommentID = commentID
+	}
+
+	if len(mentions) > 0 {
+		if err := tx.Create(&mentions).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	seen := make(map[uuid.UUID]struct{}, len(existing))
+	for _, userID := range existing {
+		seen[userID] = struct{}{}
+	}
+
+	var added []uuid.UUID
+	for _, mention := range mentions {
+		if _, exists := seen[mention.UserID]; exists {
+			continue
+		}
+		seen[mention.UserID] = struct{}{}
+		added = append(added, mention.UserID)
+	}
+
+	return added, nil
+}
+
+func recordCommentMentions(tx *gorm.DB, comment *Comment, userIDs []uuid.UUID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	return events.Record(tx, constants.DomainEventUsersMentioned, events.UsersMentioned{
+		ActorID:   comment.AuthorID,
+		PostID:    comment.PostID,
+		CommentID: &comment.ID,
+		UserIDs:   userIDs,
+	})
+}
+
+func (cs *commentsStore) GetMentionsForComments(commentIDs []uuid.UUID) (map[uuid.UUID][]CommentMention, error) {
+	mentions := make(map[uuid.UUID][]CommentMention)
+	if len(commentIDs) == 0 {
+		return mentions, nil
+	}
+
+	var rows []CommentMention
+	if err := cs.postgresDB.Preload("User").Where("comment_id IN ?", commentIDs).Order("start").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		mentions[row.CommentID] = append(mentions[row.CommentID], row)
+	}
+
+	return mentions, nil
+}
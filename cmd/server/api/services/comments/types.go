@@ -1,9 +1,15 @@
 package comments
 
-import "github.com/google/uuid"
+import (
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/utils"
+
+	"github.com/google/uuid"
+)
 
 type commentCreateUpdatePayload struct {
-	Content string `json:"content" validate:"required,min=1,max=10000"`
+	Content       string                  `json:"content" validate:"required,min=1,max=10000"`
+	ContentFormat constants.ContentFormat `json:"content_format" validate:"omitempty,oneof=plain markdown"`
 }
 
 type commentCreateUpdateResponseAuthor struct {
@@ -27,17 +33,86 @@ type commentCreateUpdateResponsePost struct {
 	Content   string                                `json:"content"`
 	Likes     int64                                 `json:"likes"`
 	Dislikes  int64                                 `json:"dislikes"`
+	Comments  int64                                 `json:"comments"`
+	Reactions []utils.ReactionCount                 `json:"reactions"`
 	CreatedAt int64                                 `json:"created_at"`
 	UpdatedAt int64                                 `json:"updated_at"`
 }
 
+type commentMentionResponse struct {
+	UserID uuid.UUID `json:"user_id"`
+	Handle *string   `json:"handle"`
+	Start  int       `json:"start"`
+	End    int       `json:"end"`
+}
+
+type commentEvent struct {
+	ID        uuid.UUID  `json:"id"`
+	PostID    uuid.UUID  `json:"post_id"`
+	ParentID  *uuid.UUID `json:"parent_id"`
+	AuthorID  uuid.UUID  `json:"author_id"`
+	Depth     int        `json:"depth"`
+	CreatedAt int64      `json:"created_at"`
+}
+
+type reactionsEvent struct {
+	PostID    uuid.UUID             `json:"post_id"`
+	CommentID *uuid.UUID            `json:"comment_id"`
+	Likes     int64                 `json:"likes"`
+	Dislikes  int64                 `json:"dislikes"`
+	Reactions []utils.ReactionCount `json:"reactions"`
+}
+
+type commentViewerResponse struct {
+	Liked    bool                   `json:"liked"`
+	Disliked bool                   `json:"disliked"`
+	Reaction constants.ReactionType `json:"reaction,omitempty"`
+	Authored bool                   `json:"authored"`
+}
+
 type commentCreateUpdateResponse struct {
-	ID        uuid.UUID                         `json:"id"`
-	Author    commentCreateUpdateResponseAuthor `json:"author"`
-	Post      commentCreateUpdateResponsePost   `json:"post"`
+	ID            uuid.UUID                          `json:"id"`
+	Author        *commentCreateUpdateResponseAuthor `json:"author"`
+	Post          commentCreateUpdateResponsePost    `json:"post"`
+	ParentID      *uuid.UUID                         `json:"parent_id"`
+	Depth         int                                `json:"depth"`
+	Content       string                             `json:"content"`
+	ContentFormat constants.ContentFormat            `json:"content_format"`
+	ContentHTML   string                             `json:"content_html"`
+	Mentions      []commentMentionResponse           `json:"mentions"`
+	Removed       bool                               `json:"removed"`
+	Replies       int64                              `json:"replies"`
+	Edited        bool                               `json:"edited"`
+	EditCount     int64                              `json:"edit_count"`
+	Likes         int64                              `json:"likes"`
+	Dislikes      int64                              `json:"dislikes"`
+	Reactions     []utils.ReactionCount              `json:"reactions"`
+	Viewer        *commentViewerResponse             `json:"viewer,omitempty"`
+	CreatedAt     int64                              `json:"created_at"`
+	UpdatedAt     int64                              `json:"updated_at"`
+}
+
+type commentTreeResponse struct {
+	commentCreateUpdateResponse
+	Children []*commentTreeResponse `json:"children"`
+}
+
+type commentReactionResponse struct {
+	User      commentCreateUpdateResponseAuthor `json:"user"`
+	Type      constants.ReactionType            `json:"type"`
+	Emoji     string                            `json:"emoji"`
+	CreatedAt int64                             `json:"created_at"`
+}
+
+type commentRevisionResponse struct {
+	Version   int64                             `json:"version"`
+	Editor    commentCreateUpdateResponseAuthor `json:"editor"`
 	Content   string                            `json:"content"`
-	Likes     int64                             `json:"likes"`
-	Dislikes  int64                             `json:"dislikes"`
 	CreatedAt int64                             `json:"created_at"`
-	UpdatedAt int64                             `json:"updated_at"`
+}
+
+type commentRevisionDiffResponse struct {
+	From    int64            `json:"from"`
+	To      int64            `json:"to"`
+	Content []utils.DiffLine `json:"content"`
 }
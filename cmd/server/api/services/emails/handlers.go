@@ -0,0 +1,97 @@
+package emails
+
+import (
+	"errors"
+	"gopher-social-backend-server/cmd/server/api/services/authentication"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/mailer"
+	"gopher-social-backend-server/pkg/utils"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type EmailsHandler struct {
+	EmailTemplates      *mailer.Registry
+	AuthenticationStore authentication.AuthenticationStore
+}
+
+func (h *EmailsHandler) authStaff(w http.ResponseWriter, r *http.Request) bool {
+	user, err := h.AuthenticationStore.GetUserByID(utils.ViewerID(r).String())
+	if err != nil {
+		utils.WriteError(w, http.StatusUnauthorized, "user not found")
+		return false
+	}
+
+	if !utils.IsStaff(user.Role) {
+		utils.WriteError(w, http.StatusForbidden, "only staff can preview emails")
+		return false
+	}
+
+	return true
+}
+
+func (h *EmailsHandler) GetTemplatesHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authStaff(w, r) {
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, templatesResponse{
+		Templates: h.EmailTemplates.Templates(),
+		Locales:   h.EmailTemplates.Locales(),
+	})
+}
+
+func (h *EmailsHandler) PreviewTemplateHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authStaff(w, r) {
+		return
+	}
+
+	name := chi.URLParam(r, "template")
+
+	locale := r.URL.Query().Get("locale")
+	if locale == "" {
+		locale = constants.DefaultLocale
+	}
+	if !h.EmailTemplates.HasLocale(locale) {
+		utils.WriteError(w, http.StatusBadRequest, "invalid locale: "+locale)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "", "json", "html", "text":
+	default:
+		utils.WriteError(w, http.StatusBadRequest, "invalid format: must be json, html or text")
+		return
+	}
+
+	message, err := h.EmailTemplates.Preview(name, locale)
+	if err != nil {
+		if errors.Is(err, mailer.ErrTemplateNotFound) {
+			utils.WriteError(w, http.StatusNotFound, "template not found")
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	switch format {
+	case "html":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(message.HTML))
+	case "text":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(message.Text))
+	default:
+		utils.WriteJSON(w, http.StatusOK, previewResponse{
+			Template: name,
+			Locale:   locale,
+			Subject:  message.Subject,
+			HTML:     message.HTML,
+			Text:     message.Text,
+		})
+	}
+}
@@ -0,0 +1,12 @@
+package emails
+
+import (
+	"gopher-social-backend-server/internal/middlewares"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterEmailsRoutes(router chi.Router, handler *EmailsHandler) {
+	router.With(middlewares.AuthMiddleware).Get("/admin/emails", handler.GetTemplatesHandler)
+	router.With(middlewares.AuthMiddleware).Get("/admin/emails/{template}", handler.PreviewTemplateHandler)
+}
@@ -0,0 +1,14 @@
+package emails
+
+type templatesResponse struct {
+	Templates []string `json:"templates"`
+	Locales   []string `json:"locales"`
+}
+
+type previewResponse struct {
+	Template string `json:"template"`
+	Locale   string `json:"locale"`
+	Subject  string `json:"subject"`
+	HTML     string `json:"html"`
+	Text     string `json:"text"`
+}
@@ -0,0 +1,166 @@
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"gopher-social-backend-server/cmd/server/api/services/authentication"
+	"gopher-social-backend-server/internal/queue"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/utils"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type JobsHandler struct {
+	JobsStore           queue.JobsStore
+	AuthenticationStore authentication.AuthenticationStore
+}
+
+func newJobResponse(job queue.Job) jobResponse {
+	return jobResponse{
+		ID:          job.ID,
+		Kind:        job.Kind,
+		Key:         job.Key,
+		Payload:     json.RawMessage(job.Payload),
+		Status:      job.Status,
+		Attempts:    job.Attempts,
+		RunAt:       job.RunAt,
+		LockedUntil: job.LockedUntil,
+		LastError:   job.LastError,
+		FinishedAt:  job.FinishedAt,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+	}
+}
+
+func (h *JobsHandler) authAdmin(w http.ResponseWriter, r *http.Request) bool {
+	user, err := h.AuthenticationStore.GetUserByID(utils.ViewerID(r).String())
+	if err != nil {
+		utils.WriteError(w, http.StatusUnauthorized, "user not found")
+		return false
+	}
+
+	if user.Role != constants.RoleAdmin {
+		utils.WriteError(w, http.StatusForbidden, "only admins can manage jobs")
+		return false
+	}
+
+	return true
+}
+
+func (h *JobsHandler) getJob(w http.ResponseWriter, r *http.Request) (*queue.Job, bool) {
+	jobID, err := uuid.Parse(chi.URLParam(r, "jobID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	job, err := h.JobsStore.GetJobByID(jobID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.WriteError(w, http.StatusNotFound, "job not found")
+			return nil, false
+		}
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+
+	return job, true
+}
+
+func (h *JobsHandler) GetJobsHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authAdmin(w, r) {
+		return
+	}
+
+	status := constants.JobStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "", constants.JobPending, constants.JobRunning, constants.JobSucceeded, constants.JobDead:
+	default:
+		utils.WriteError(w, http.StatusBadRequest, "invalid status: must be pending, running, succeeded or dead")
+		return
+	}
+
+	kind := constants.JobKind(r.URL.Query().Get("kind"))
+	if kind != "" && !slices.Contains(constants.JobKinds, kind) {
+		utils.WriteError(w, http.StatusBadRequest, "invalid kind: "+string(kind))
+		return
+	}
+
+	limit := r.Context().Value(constants.LimitKey).(int)
+	offset := r.Context().Value(constants.OffsetKey).(int)
+
+	jobs, err := h.JobsStore.GetJobs(status, kind, limit+1, offset)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	jobs, pageInfo, err := utils.PaginateResults(r, jobs)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if utils.IncludeTotal(r) {
+		total, err := h.JobsStore.CountJobs(status, kind)
+		if err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		pageInfo.Total = &total
+	}
+
+	jobResponses := make([]jobResponse, 0, len(jobs))
+	for _, job := range jobs {
+		jobResponses = append(jobResponses, newJobResponse(job))
+	}
+
+	utils.WritePage(w, r, http.StatusOK, jobResponses, pageInfo)
+}
+
+func (h *JobsHandler) GetJobHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authAdmin(w, r) {
+		return
+	}
+
+	job, ok := h.getJob(w, r)
+	if !ok {
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, newJobResponse(*job))
+}
+
+func (h *JobsHandler) RetryJobHandler(w http.ResponseWriter, r *http.Request) {
+	if !h.authAdmin(w, r) {
+		return
+	}
+
+	job, ok := h.getJob(w, r)
+	if !ok {
+		return
+	}
+
+	if job.Status != constants.JobPending && job.Status != constants.JobDead {
+		utils.WriteError(w, http.StatusConflict, "only pending or dead jobs can be retried")
+		return
+	}
+
+	job, err := h.JobsStore.RetryJob(job.ID, time.Now())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.WriteError(w, http.StatusConflict, "only pending or dead jobs can be retried")
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusAccepted, newJobResponse(*job))
+}
@@ -0,0 +1,13 @@
+package jobs
+
+import (
+	"gopher-social-backend-server/internal/middlewares"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterJobsRoutes(router chi.Router, handler *JobsHandler) {
+	router.With(middlewares.AuthMiddleware, middlewares.PaginationMiddleware).Get("/admin/jobs", handler.GetJobsHandler)
+	router.With(middlewares.AuthMiddleware).Get("/admin/jobs/{jobID}", handler.GetJobHandler)
+	router.With(middlewares.AuthMiddleware).Post("/admin/jobs/{jobID}/retry", handler.RetryJobHandler)
+}
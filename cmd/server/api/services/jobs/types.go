@@ -0,0 +1,23 @@
+package jobs
+
+import (
+	"encoding/json"
+	"gopher-social-backend-server/pkg/constants"
+
+	"github.com/google/uuid"
+)
+
+type jobResponse struct {
+	ID          uuid.UUID           `json:"id"`
+	Kind        constants.JobKind   `json:"kind"`
+	Key         *string             `json:"key"`
+	Payload     json.RawMessage     `json:"payload"`
+	Status      constants.JobStatus `json:"status"`
+	Attempts    int                 `json:"attempts"`
+	RunAt       int64               `json:"run_at"`
+	LockedUntil *int64              `json:"locked_until"`
+	LastError   string              `json:"last_error"`
+	FinishedAt  *int64              `json:"finished_at"`
+	CreatedAt   int64               `json:"created_at"`
+	UpdatedAt   int64               `json:"updated_at"`
+}
@@ -0,0 +1,248 @@
+package notifications
+
+import (
+	"errors"
+	"fmt"
+	"gopher-social-backend-server/cmd/server/api/services/authentication"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/utils"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+)
+
+type NotificationsHandler struct {
+	NotificationsStore NotificationsStore
+}
+
+var notificationActions = map[constants.NotificationType][2]string{
+	constants.NotificationLike:    {"liked your post", "liked your comment"},
+	constants.NotificationComment: {"commented on your post", "commented on your post"},
+	constants.NotificationReply:   {"replied to your comment", "replied to your comment"},
+	constants.NotificationFollow:  {"followed you", "followed you"},
+	constants.NotificationMention: {"mentioned you in a post", "mentioned you in a comment"},
+}
+
+func authUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userID := utils.ViewerID(r)
+	if userID == uuid.Nil {
+		utils.WriteError(w, http.StatusUnauthorized, "user ID not found in context")
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+func newActorResponse(actor authentication.User) notificationActorResponse {
+	return notificationActorResponse{
+		ID:        actor.ID,
+		Handle:    actor.Handle,
+		FirstName: actor.FirstName,
+		LastName:  actor.LastName,
+	}
+}
+
+func actorName(actor authentication.User) string {
+	if actor.FirstName != "" {
+		return actor.FirstName
+	}
+	if actor.Handle != nil {
+		return "@" + *actor.Handle
+	}
+	return "Someone"
+}
+
+func notificationSummary(notification Notification) string {
+	action := notificationActions[notification.Type][0]
+	if notification.CommentID != nil {
+		action = notificationActions[notification.Type][1]
+	}
+
+	name := actorName(notification.Actor)
+	switch others := notification.ActorsCount - 1; {
+	case others == 1:
+		return fmt.Sprintf("%s and 1 other %s", name, action)
+	case others > 1:
+		return fmt.Sprintf("%s and %d others %s", name, others, action)
+	}
+	return fmt.Sprintf("%s %s", name, action)
+}
+
+func (h *NotificationsHandler) GetNotificationsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	unreadOnly := false
+	if unreadParam := r.URL.Query().Get("unread"); unreadParam != "" {
+		var err error
+		if unreadOnly, err = strconv.ParseBool(unreadParam); err != nil {
+			utils.WriteError(w, http.StatusBadRequest, "invalid unread: must be true or false")
+			return
+		}
+	}
+
+	limit := r.Context().Value(constants.LimitKey).(int)
+	offset := r.Context().Value(constants.OffsetKey).(int)
+
+	notifications, err := h.NotificationsStore.GetNotifications(userID, unreadOnly, limit+1, offset)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	notifications, pageInfo, err := utils.PaginateResults(r, notifications)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if utils.IncludeTotal(r) {
+		total, err := h.NotificationsStore.CountNotifications(userID, unreadOnly)
+		if err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		pageInfo.Total = &total
+	}
+
+	notificationIDs := make([]uuid.UUID, 0, len(notifications))
+	for _, notification := range notifications {
+		notificationIDs = append(notificationIDs, notification.ID)
+	}
+
+	notificationActors, err := h.NotificationsStore.GetNotificationActors(notificationIDs, constants.MaxNotificationActors)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	notificationResponses := make([]notificationResponse, 0, len(notifications))
+	for _, notification := range notifications {
+		actors := make([]notificationActorResponse, 0, len(notificationActors[notification.ID]))
+		for _, actor := range notificationActors[notification.ID] {
+			actors = append(actors, newActorResponse(actor.Actor))
+		}
+
+		notificationResponses = append(notificationResponses, notificationResponse{
+			ID:          notification.ID,
+			Type:        notification.Type,
+			Summary:     notificationSummary(notification),
+			Actor:       newActorResponse(notification.Actor),
+			Actors:      actors,
+			ActorsCount: notification.ActorsCount,
+			PostID:      notification.PostID,
+			CommentID:   notification.CommentID,
+			Read:        notification.ReadAt != nil,
+			CreatedAt:   notification.CreatedAt,
+			UpdatedAt:   notification.UpdatedAt,
+		})
+	}
+
+	utils.WritePage(w, r, http.StatusOK, notificationResponses, pageInfo)
+}
+
+func (h *NotificationsHandler) GetUnreadCountHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	unread, err := h.NotificationsStore.CountNotifications(userID, true)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, unreadCountResponse{Unread: unread})
+}
+
+func (h *NotificationsHandler) MarkNotificationReadHandler(w http.ResponseWriter, r *http.Request) {
+	notificationID, err := uuid.Parse(chi.URLParam(r, "notificationID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.NotificationsStore.MarkNotificationRead(userID, notificationID); err != nil {
+		if errors.Is(err, ErrNotificationNotFound) {
+			utils.WriteError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusNoContent, nil)
+}
+
+func (h *NotificationsHandler) MarkAllNotificationsReadHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	updated, err := h.NotificationsStore.MarkAllNotificationsRead(userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, markAllReadResponse{Updated: updated})
+}
+
+func (h *NotificationsHandler) GetPreferencesHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	preferences, err := h.NotificationsStore.GetNotificationPreferences(userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, preferences)
+}
+
+func (h *NotificationsHandler) UpdatePreferencesHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var payload map[constants.NotificationType]bool
+	if err := utils.ParseJSON(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	for notificationType := range payload {
+		if !slices.Contains(constants.NotificationTypes, notificationType) {
+			utils.WriteError(w, http.StatusBadRequest, fmt.Sprintf("invalid notification type: %s", notificationType))
+			return
+		}
+	}
+
+	if err := h.NotificationsStore.SetNotificationPreferences(userID, payload); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	preferences, err := h.NotificationsStore.GetNotificationPreferences(userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, preferences)
+}
@@ -0,0 +1,42 @@
+package notifications
+
+import (
+	"gopher-social-backend-server/cmd/server/api/services/authentication"
+	"gopher-social-backend-server/pkg/constants"
+
+	"github.com/google/uuid"
+)
+
+type Notification struct {
+	ID          uuid.UUID                  `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserID      uuid.UUID                  `json:"user_id" gorm:"type:uuid;not null;index:idx_notifications_user_updated;uniqueIndex:idx_notifications_unread_group,where:read_at IS NULL"`
+	User        authentication.User        `json:"user" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	ActorID     uuid.UUID                  `json:"actor_id" gorm:"type:uuid;not null;index"`
+	Actor       authentication.User        `json:"actor" gorm:"foreignKey:ActorID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Type        constants.NotificationType `json:"type" gorm:"type:varchar(32);not null"`
+	PostID      *uuid.UUID                 `json:"post_id" gorm:"type:uuid;index"`
+	CommentID   *uuid.UUID                 `json:"comment_id" gorm:"type:uuid;index"`
+	GroupKey    string                     `json:"group_key" gorm:"type:varchar(128);not null;uniqueIndex:idx_notifications_unread_group,where:read_at IS NULL"`
+	ActorsCount int64                      `json:"actors_count" gorm:"not null;default:0"`
+	ReadAt      *int64                     `json:"read_at"`
+	CreatedAt   int64                      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   int64                      `json:"updated_at" gorm:"autoUpdateTime;index:idx_notifications_user_updated"`
+}
+
+type NotificationActor struct {
+	ID             uuid.UUID           `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	NotificationID uuid.UUID           `json:"notification_id" gorm:"type:uuid;not null;uniqueIndex:idx_notification_actors_notification_actor"`
+	Notification   Notification        `json:"notification" gorm:"foreignKey:NotificationID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	ActorID        uuid.UUID           `json:"actor_id" gorm:"type:uuid;not null;uniqueIndex:idx_notification_actors_notification_actor"`
+	Actor          authentication.User `json:"actor" gorm:"foreignKey:ActorID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	CreatedAt      int64               `json:"created_at" gorm:"autoCreateTime"`
+}
+
+type NotificationPreference struct {
+	ID        uuid.UUID                  `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserID    uuid.UUID                  `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_notification_preferences_user_type"`
+	User      authentication.User        `json:"user" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Type      constants.NotificationType `json:"type" gorm:"type:varchar(32);not null;uniqueIndex:idx_notification_preferences_user_type"`
+	Enabled   bool                       `json:"enabled" gorm:"not null"`
+	UpdatedAt int64                      `json:"updated_at" gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,16 @@
+package notifications
+
+import (
+	"gopher-social-backend-server/internal/middlewares"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterNotificationsRoutes(router chi.Router, handler *NotificationsHandler) {
+	router.With(middlewares.AuthMiddleware, middlewares.PaginationMiddleware).Get("/notifications", handler.GetNotificationsHandler)
+	router.With(middlewares.AuthMiddleware).Get("/notifications/unread-count", handler.GetUnreadCountHandler)
+	router.With(middlewares.AuthMiddleware).Post("/notifications/read", handler.MarkAllNotificationsReadHandler)
+	router.With(middlewares.AuthMiddleware).Post("/notifications/{notificationID}/read", handler.MarkNotificationReadHandler)
+	router.With(middlewares.AuthMiddleware).Get("/notifications/preferences", handler.GetPreferencesHandler)
+	router.With(middlewares.AuthMiddleware).Patch("/notifications/preferences", handler.UpdatePreferencesHandler)
+}
@@ -0,0 +1,241 @@
+package notifications
+
+import (
+	"errors"
+	"gopher-social-backend-server/internal/pubsub"
+	"gopher-social-backend-server/pkg/constants"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+type NotificationsStore interface {
+	CreateNotifications(notifications []Notification) error
+	GetNotifications(userID uuid.UUID, unreadOnly bool, limit, offset int) ([]Notification, error)
+	GetDigestNotifications(userID uuid.UUID, notificationType constants.NotificationType, since time.Time, limit int) ([]Notification, error)
+	CountNotifications(userID uuid.UUID, unreadOnly bool) (int64, error)
+	GetNotificationActors(notificationIDs []uuid.UUID, limit int) (map[uuid.UUID][]NotificationActor, error)
+	MarkNotificationRead(userID, notificationID uuid.UUID) error
+	MarkAllNotificationsRead(userID uuid.UUID) (int64, error)
+	GetNotificationPreferences(userID uuid.UUID) (map[constants.NotificationType]bool, error)
+	SetNotificationPreferences(userID uuid.UUID, preferences map[constants.NotificationType]bool) error
+}
+
+type notificationsStore struct {
+	postgresDB *gorm.DB
+}
+
+func NewNotificationsStore(postgresDB *gorm.DB) NotificationsStore {
+	return &notificationsStore{
+		postgresDB: postgresDB,
+	}
+}
+
+func groupKey(notification Notification) string {
+	switch {
+	case notification.CommentID != nil:
+		return string(notification.Type) + ":" + notification.CommentID.String()
+	case notification.PostID != nil:
+		return string(notification.Type) + ":" + notification.PostID.String()
+	}
+	return string(notification.Type) + ":"
+}
+
+func (s *notificationsStore) CreateNotifications(notifications []Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+
+	return s.postgresDB.Transaction(func(tx *gorm.DB) error {
+		for _, notification := range notifications {
+			if notification.UserID == notification.ActorID {
+				continue
+			}
+
+			var suppressed bool
+			if err := tx.Raw(`
+				SELECT EXISTS (
+					SELECT 1 FROM notification_preferences WHERE user_id = ? AND type = ? AND NOT enabled
+				) OR EXISTS (
+					SELECT 1 FROM blocks WHERE (blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)
+				)`,
+				notification.UserID, notification.Type,
+				notification.UserID, notification.ActorID, notification.ActorID, notification.UserID,
+			).Scan(&suppressed).Error; err != nil {
+				return err
+			}
+			if suppressed {
+				continue
+			}
+
+			now := time.Now().Unix()
+			notification.GroupKey = groupKey(notification)
+
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:     []clause.Column{{Name: "user_id"}, {Name: "group_key"}},
+				TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "read_at IS NULL"}}},
+				DoUpdates:   clause.Assignments(map[string]any{"updated_at": now}),
+			}).Create(&notification).Error; err != nil {
+				return err
+			}
+
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&NotificationActor{
+				NotificationID: notification.ID,
+				ActorID:        notification.ActorID,
+			})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				continue
+			}
+
+			if err := tx.Model(&notification).Clauses(clause.Returning{}).Updates(map[string]any{
+				"actor_id":     notification.ActorID,
+				"actors_count": gorm.Expr("actors_count + 1"),
+				"updated_at":   now,
+			}).Error; err != nil {
+				return err
+			}
+
+			if err := pubsub.Publish(tx, pubsub.UserTopic(notification.UserID), constants.StreamEventNotification, notificationEvent{
+				ID:          notification.ID,
+				Type:        notification.Type,
+				ActorID:     notification.ActorID,
+				ActorsCount: notification.ActorsCount,
+				PostID:      notification.PostID,
+				CommentID:   notification.CommentID,
+				UpdatedAt:   notification.UpdatedAt,
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (s *notificationsStore) notificationsQuery(userID uuid.UUID, unreadOnly bool) *gorm.DB {
+	query := s.postgresDB.Model(&Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	return query
+}
+
+func (s *notificationsStore) GetNotifications(userID uuid.UUID, unreadOnly bool, limit, offset int) ([]Notification, error) {
+	var notifications []Notification
+
+	if err := s.notificationsQuery(userID, unreadOnly).Preload("Actor").
+		Order("updated_at DESC").Order("id").Limit(limit).Offset(offset).
+		Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (s *notificationsStore) GetDigestNotifications(userID uuid.UUID, notificationType constants.NotificationType, since time.Time, limit int) ([]Notification, error) {
+	var notifications []Notification
+
+	if err := s.notificationsQuery(userID, true).Preload("Actor").
+		Where("type = ? AND updated_at >= ?", notificationType, since.Unix()).
+		Order("updated_at DESC").Order("id").Limit(limit).
+		Find(&notifications).Error; err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+func (s *notificationsStore) CountNotifications(userID uuid.UUID, unreadOnly bool) (int64, error) {
+	var count int64
+	err := s.notificationsQuery(userID, unreadOnly).Count(&count).Error
+	return count, err
+}
+
+func (s *notificationsStore) GetNotificationActors(notificationIDs []uuid.UUID, limit int) (map[uuid.UUID][]NotificationActor, error) {
+	actors := make(map[uuid.UUID][]NotificationActor)
+	if len(notificationIDs) == 0 {
+		return actors, nil
+	}
+
+	ranked := s.postgresDB.Model(&NotificationActor{}).
+		Select("*, row_number() OVER (PARTITION BY notification_id ORDER BY created_at DESC, id) AS position").
+		Where("notification_id IN ?", notificationIDs)
+
+	var rows []NotificationActor
+	if err := s.postgresDB.Table("(?) AS notification_actors", ranked).Preload("Actor").
+		Where("position <= ?", limit).Order("notification_id").Order("position").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		actors[row.NotificationID] = append(actors[row.NotificationID], row)
+	}
+
+	return actors, nil
+}
+
+func (s *notificationsStore) MarkNotificationRead(userID, notificationID uuid.UUID) error {
+	result := s.postgresDB.Model(&Notification{}).
+		Where("id = ? AND user_id = ?", notificationID, userID).
+		UpdateColumn("read_at", gorm.Expr("COALESCE(read_at, ?)", time.Now().Unix()))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+func (s *notificationsStore) MarkAllNotificationsRead(userID uuid.UUID) (int64, error) {
+	result := s.postgresDB.Model(&Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		UpdateColumn("read_at", time.Now().Unix())
+	return result.RowsAffected, result.Error
+}
+
+func (s *notificationsStore) GetNotificationPreferences(userID uuid.UUID) (map[constants.NotificationType]bool, error) {
+	preferences := make(map[constants.NotificationType]bool, len(constants.NotificationTypes))
+	for _, notificationType := range constants.NotificationTypes {
+		preferences[notificationType] = true
+	}
+
+	var rows []NotificationPreference
+	if err := s.postgresDB.Where("user_id = ?", userID).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		preferences[row.Type] = row.Enabled
+	}
+
+	return preferences, nil
+}
+
+func (s *notificationsStore) SetNotificationPreferences(userID uuid.UUID, preferences map[constants.NotificationType]bool) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+
+	rows := make([]NotificationPreference, 0, len(preferences))
+	for notificationType, enabled := range preferences {
+		rows = append(rows, NotificationPreference{
+			UserID:  userID,
+			Type:    notificationType,
+			Enabled: enabled,
+		})
+	}
+
+	return s.postgresDB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+	}).Create(&rows).Error
+}
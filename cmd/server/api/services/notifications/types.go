@@ -0,0 +1,46 @@
+package notifications
+
+import (
+	"gopher-social-backend-server/pkg/constants"
+
+	"github.com/google/uuid"
+)
+
+type notificationActorResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Handle    *string   `json:"handle"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+}
+
+type notificationResponse struct {
+	ID          uuid.UUID                   `json:"id"`
+	Type        constants.NotificationType  `json:"type"`
+	Summary     string                      `json:"summary"`
+	Actor       notificationActorResponse   `json:"actor"`
+	Actors      []notificationActorResponse `json:"actors"`
+	ActorsCount int64                       `json:"actors_count"`
+	PostID      *uuid.UUID                  `json:"post_id"`
+	CommentID   *uuid.UUID                  `json:"comment_id"`
+	Read        bool                        `json:"read"`
+	CreatedAt   int64                       `json:"created_at"`
+	UpdatedAt   int64                       `json:"updated_at"`
+}
+
+type notificationEvent struct {
+	ID          uuid.UUID                  `json:"id"`
+	Type        constants.NotificationType `json:"type"`
+	ActorID     uuid.UUID                  `json:"actor_id"`
+	ActorsCount int64                      `json:"actors_count"`
+	PostID      *uuid.UUID                 `json:"post_id"`
+	CommentID   *uuid.UUID                 `json:"comment_id"`
+	UpdatedAt   int64                      `json:"updated_at"`
+}
+
+type unreadCountResponse struct {
+	Unread int64 `json:"unread"`
+}
+
+type markAllReadResponse struct {
+	Updated int64 `json:"updated"`
+}
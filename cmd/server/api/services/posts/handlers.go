@@ -3,105 +3,234 @@ package posts
 import (
 	"errors"
 	"gopher-social-backend-server/cmd/server/api/services/authentication"
+	"gopher-social-backend-server/cmd/server/api/services/tags"
+	"gopher-social-backend-server/cmd/server/api/services/users"
 	"gopher-social-backend-server/pkg/constants"
 	"gopher-social-backend-server/pkg/utils"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type PostsHandler struct {
 	PostsStore          PostsStore
 	AuthenticationStore authentication.AuthenticationStore
+	TagsStore           tags.TagsStore
+	UsersStore          users.UsersStore
 }
 
+var POST_EDIT_WINDOW = utils.GetEnvAsDuration("POST_EDIT_WINDOW", constants.DefaultPostEditWindow)
+var DIFF_RATE_LIMIT = utils.GetEnvAsDuration("DIFF_RATE_LIMIT", constants.DefaultDiffRateLimit)
+
 var validate = validator.New()
 
-func (h *PostsHandler) fetchPostDetails(postID uuid.UUID) (*postCreateUpdateResponse, error) {
-	post, err := h.PostsStore.GetPostByID(postID)
+var errInvalidTag = errors.New("invalid tag: tags may only contain letters, numbers and underscores")
+
+func (h *PostsHandler) buildPostResponses(posts []Post, viewerID uuid.UUID) ([]postCreateUpdateResponse, error) {
+	postIDs := make([]uuid.UUID, 0, len(posts))
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID)
+	}
+
+	postTags, err := h.TagsStore.GetTagsForPosts(postIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	viewerReactions, err := h.PostsStore.GetUserReactionsForPosts(viewerID, postIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	viewerBookmarks, err := h.PostsStore.GetUserBookmarksForPosts(viewerID, postIDs)
 	if err != nil {
 		return nil, err
 	}
 
-	postAuthor, err := h.AuthenticationStore.GetUserByID(post.AuthorID.String())
+	postMentions, err := h.PostsStore.GetMentionsForPosts(postIDs)
 	if err != nil {
 		return nil, err
 	}
 
-	post.Author = *postAuthor
-	likes, _ := h.PostsStore.GetLikesCount(postID)
-	dislikes, _ := h.PostsStore.GetDislikesCount(postID)
+	postResponses := make([]postCreateUpdateResponse, 0, len(posts))
+	for _, post := range posts {
+		var viewer *postViewerResponse
+		if viewerID != uuid.Nil {
+			viewer = &postViewerResponse{
+				Liked:      viewerReactions[post.ID] == constants.ReactionLike,
+				Disliked:   viewerReactions[post.ID] == constants.ReactionDislike,
+				Reaction:   viewerReactions[post.ID],
+				Bookmarked: viewerBookmarks[post.ID],
+				Authored:   post.AuthorID == viewerID,
+			}
+		}
+
+		mentions := make([]postMentionResponse, 0, len(postMentions[post.ID]))
+		for _, mention := range postMentions[post.ID] {
+			mentions = append(mentions, postMentionResponse{
+				UserID: mention.UserID,
+				Handle: mention.User.Handle,
+				Start:  mention.Start,
+				End:    mention.End,
+			})
+		}
+
+		postResponses = append(postResponses, postCreateUpdateResponse{
+			ID: post.ID,
+			Author: postCreateUpdateResponseAuthor{
+				ID:        post.Author.ID,
+				FirstName: post.Author.FirstName,
+				LastName:  post.Author.LastName,
+				Email:     post.Author.Email,
+			},
+			Title:         post.Title,
+			Content:       post.Content,
+			ContentFormat: post.ContentFormat,
+			ContentHTML:   utils.RenderedContent(post.ContentFormat, post.Content, post.ContentHTML, post.ContentRenderVersion),
+			Tags:          postTags[post.ID],
+			Mentions:      mentions,
+			Edited:        post.EditCount > 0,
+			EditCount:     post.EditCount,
+			Status:        post.Status,
+			Visibility:    post.Visibility,
+			PublishAt:     post.PublishAt,
+			PublishedAt:   post.PublishedAt,
+			Likes:         post.LikesCount,
+			Dislikes:      post.DislikesCount,
+			Comments:      post.CommentsCount,
+			Reactions:     utils.ReactionCounts(post.ReactionCounts),
+			Viewer:        viewer,
+			CreatedAt:     post.CreatedAt,
+			UpdatedAt:     post.UpdatedAt,
+		})
+	}
 
-	return &postCreateUpdateResponse{
-		ID: post.ID,
-		Author: postCreateUpdateResponseAuthor{
-			ID:        post.Author.ID,
-			FirstName: post.Author.FirstName,
-			LastName:  post.Author.LastName,
-			Email:     post.Author.Email,
-		},
-		Title:     post.Title,
-		Content:   post.Content,
-		Likes:     likes,
-		Dislikes:  dislikes,
-		CreatedAt: post.CreatedAt,
-		UpdatedAt: post.UpdatedAt,
-	}, nil
+	return postResponses, nil
 }
 
-func (h *PostsHandler) verifyOwnership(postID uuid.UUID, authUserID string) (*Post, error) {
+func (h *PostsHandler) fetchPostDetails(postID, viewerID uuid.UUID) (*postCreateUpdateResponse, error) {
 	post, err := h.PostsStore.GetPostByID(postID)
 	if err != nil {
 		return nil, err
 	}
 
-	postAuthor, err := h.AuthenticationStore.GetUserByID(post.AuthorID.String())
+	postResponses, err := h.buildPostResponses([]Post{*post}, viewerID)
 	if err != nil {
 		return nil, err
 	}
 
-	post.Author = *postAuthor
+	return &postResponses[0], nil
+}
 
-	if err := utils.VerifyOwnership(postAuthor.ID.String(), authUserID); err != nil {
+func (h *PostsHandler) verifyOwnership(postID uuid.UUID, authUserID string) (*Post, error) {
+	post, err := h.PostsStore.GetPostByID(postID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := utils.VerifyOwnership(post.AuthorID.String(), authUserID); err != nil {
 		return nil, err
 	}
 
 	return post, nil
 }
 
-func (h *PostsHandler) handleLikeDislike(userUUID, postID uuid.UUID, action string) error {
-	switch action {
-	case "like":
-		if liked, _ := h.PostsStore.HasLiked(userUUID, postID); liked {
-			return errors.New("you have already liked this post")
-		}
-		if disliked, _ := h.PostsStore.HasDisliked(userUUID, postID); disliked {
-			h.PostsStore.RemoveDislike(userUUID, postID)
+func applyPostStatus(post *Post, status constants.PostStatus, publishAt *int64) error {
+	if status == "" {
+		return nil
+	}
+
+	if err := utils.ValidatePostStatus(post.Status, status, publishAt); err != nil {
+		return err
+	}
+
+	switch status {
+	case constants.PostStatusDraft:
+		post.PublishAt = nil
+	case constants.PostStatusScheduled:
+		post.PublishAt = publishAt
+	case constants.PostStatusPublished:
+		if post.Status != constants.PostStatusPublished && post.Status != constants.PostStatusArchived {
+			now := time.Now().Unix()
+			post.PublishAt = &now
+			post.PublishedAt = &now
 		}
-		return h.PostsStore.LikePost(userUUID, postID)
+	}
 
-	case "unlike":
-		if liked, _ := h.PostsStore.HasLiked(userUUID, postID); !liked {
-			return errors.New("you haven't liked this post")
+	post.Status = status
+	return nil
+}
+
+func (h *PostsHandler) verifyVisibility(w http.ResponseWriter, postID, viewerID uuid.UUID) (*Post, bool) {
+	post, err := h.PostsStore.GetVisiblePostByID(postID, viewerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.WriteError(w, http.StatusNotFound, "post not found")
+			return nil, false
 		}
-		return h.PostsStore.RemoveLike(userUUID, postID)
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+	return post, true
+}
 
-	case "dislike":
-		if disliked, _ := h.PostsStore.HasDisliked(userUUID, postID); disliked {
-			return errors.New("you have already disliked this post")
+func (h *PostsHandler) resolveMentions(post *Post) ([]PostMention, error) {
+	matches := utils.ExtractMentions(post.Content)
+
+	mentionedUsers, err := h.UsersStore.ResolveMentions(post.AuthorID, utils.MentionHandles(matches))
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make(map[string]uuid.UUID, len(mentionedUsers))
+	for _, user := range mentionedUsers {
+		userIDs[*user.Handle] = user.ID
+	}
+
+	mentions := make([]PostMention, 0, len(matches))
+	for _, match := range matches {
+		if userID, ok := userIDs[match.Handle]; ok {
+			mentions = append(mentions, PostMention{UserID: userID, Start: match.Start, End: match.End})
 		}
-		if liked, _ := h.PostsStore.HasLiked(userUUID, postID); liked {
-			h.PostsStore.RemoveLike(userUUID, postID)
+	}
+
+	return mentions, nil
+}
+
+func validateTags(explicitTags *[]string) error {
+	if explicitTags == nil {
+		return nil
+	}
+
+	for _, tag := range *explicitTags {
+		if !utils.IsValidTag(utils.NormalizeTag(tag)) {
+			return errInvalidTag
 		}
-		return h.PostsStore.DislikePost(userUUID, postID)
+	}
+	return nil
+}
+
+var likeDislikeConflicts = map[string]string{
+	"like":      "you have already liked this post",
+	"unlike":    "you haven't liked this post",
+	"dislike":   "you have already disliked this post",
+	"undislike": "you haven't disliked this post",
+}
 
+func (h *PostsHandler) handleLikeDislike(userUUID, postID uuid.UUID, action string) error {
+	switch action {
+	case "like":
+		return h.PostsStore.ReactToPost(userUUID, postID, constants.ReactionLike)
+	case "unlike":
+		return h.PostsStore.RemovePostReaction(userUUID, postID, constants.ReactionLike)
+	case "dislike":
+		return h.PostsStore.ReactToPost(userUUID, postID, constants.ReactionDislike)
 	case "undislike":
-		if disliked, _ := h.PostsStore.HasDisliked(userUUID, postID); !disliked {
-			return errors.New("you haven't disliked this post")
-		}
-		return h.PostsStore.RemoveDislike(userUUID, postID)
+		return h.PostsStore.RemovePostReaction(userUUID, postID, constants.ReactionDislike)
 	}
 	return nil
 }
@@ -113,38 +242,134 @@ func (h *PostsHandler) GetPostByIDHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	postResponse, err := h.fetchPostDetails(postID)
+	viewerID := utils.ViewerID(r)
+
+	post, ok := h.verifyVisibility(w, postID, viewerID)
+	if !ok {
+		return
+	}
+
+	postResponses, err := h.buildPostResponses([]Post{*post}, viewerID)
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	utils.WriteJSON(w, http.StatusOK, postResponse)
+	utils.WriteJSON(w, http.StatusOK, postResponses[0])
+}
+
+func (h *PostsHandler) writePostsPage(w http.ResponseWriter, r *http.Request, posts []Post, count func() (int64, error)) {
+	posts, pageInfo, err := utils.PaginateResults(r, posts)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if utils.IncludeTotal(r) {
+		total, err := count()
+		if err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		pageInfo.Total = &total
+	}
+
+	postResponses, err := h.buildPostResponses(posts, utils.ViewerID(r))
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WritePage(w, r, http.StatusOK, postResponses, pageInfo)
 }
 
 func (h *PostsHandler) GetPostsHandler(w http.ResponseWriter, r *http.Request) {
 	limit := r.Context().Value(constants.LimitKey).(int)
 	offset := r.Context().Value(constants.OffsetKey).(int)
+	cursor := r.Context().Value(constants.CursorKey).(*utils.Cursor)
 	orderby := r.Context().Value(constants.OrderByKey).(string)
 	desc := r.Context().Value(constants.DescKey).(string) == "true"
 
-	posts, err := h.PostsStore.GetPosts(limit, offset, orderby, desc)
+	if err := utils.ValidateCursor(cursor, orderby, desc); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	viewerID := utils.ViewerID(r)
+
+	posts, err := h.PostsStore.GetPosts(viewerID, limit, offset, cursor, orderby, desc)
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	var postResponses []postCreateUpdateResponse
-	for _, post := range posts {
-		response, err := h.fetchPostDetails(post.ID)
-		if err != nil {
-			utils.WriteError(w, http.StatusInternalServerError, err.Error())
-			return
-		}
-		postResponses = append(postResponses, *response)
+	h.writePostsPage(w, r, posts, func() (int64, error) {
+		return h.PostsStore.CountPosts(viewerID)
+	})
+}
+
+func (h *PostsHandler) GetPostsByTagHandler(w http.ResponseWriter, r *http.Request) {
+	tag := utils.NormalizeTag(chi.URLParam(r, "tag"))
+	if !utils.IsValidTag(tag) {
+		utils.WriteError(w, http.StatusBadRequest, errInvalidTag.Error())
+		return
+	}
+
+	limit := r.Context().Value(constants.LimitKey).(int)
+	offset := r.Context().Value(constants.OffsetKey).(int)
+	cursor := r.Context().Value(constants.CursorKey).(*utils.Cursor)
+	orderby := r.Context().Value(constants.OrderByKey).(string)
+	desc := r.Context().Value(constants.DescKey).(string) == "true"
+
+	if err := utils.ValidateCursor(cursor, orderby, desc); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	viewerID := utils.ViewerID(r)
+
+	posts, err := h.PostsStore.GetPostsByTag(tag, viewerID, limit, offset, cursor, orderby, desc)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
 	}
 
-	utils.WriteJSON(w, http.StatusOK, postResponses)
+	h.writePostsPage(w, r, posts, func() (int64, error) {
+		return h.PostsStore.CountPostsByTag(tag, viewerID)
+	})
+}
+
+func (h *PostsHandler) GetDraftsHandler(w http.ResponseWriter, r *http.Request) {
+	statuses, ok := constants.AuthorPostStatuses[r.URL.Query().Get("status")]
+	if !ok {
+		utils.WriteError(w, http.StatusBadRequest, "invalid status: must be draft, scheduled or archived")
+		return
+	}
+
+	authUserID, ok := r.Context().Value(constants.UserIDKey).(string)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "user ID not found in context")
+		return
+	}
+
+	authorID, err := uuid.Parse(authUserID)
+	if err != nil {
+		utils.WriteError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+
+	limit := r.Context().Value(constants.LimitKey).(int)
+	offset := r.Context().Value(constants.OffsetKey).(int)
+
+	posts, err := h.PostsStore.GetPostsByAuthor(authorID, statuses, limit+1, offset)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.writePostsPage(w, r, posts, func() (int64, error) {
+		return h.PostsStore.CountPostsByAuthor(authorID, statuses)
+	})
 }
 
 func (h *PostsHandler) CreatePostHandler(w http.ResponseWriter, r *http.Request) {
@@ -159,6 +384,11 @@ func (h *PostsHandler) CreatePostHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if err := validateTags(payload.Tags); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	userID, ok := r.Context().Value(constants.UserIDKey).(string)
 	if !ok {
 		utils.WriteError(w, http.StatusUnauthorized, "user ID not found in context")
@@ -172,18 +402,49 @@ func (h *PostsHandler) CreatePostHandler(w http.ResponseWriter, r *http.Request)
 	}
 
 	post := Post{
-		AuthorID: user.ID,
-		Author:   *user,
-		Title:    payload.Title,
-		Content:  payload.Content,
+		AuthorID:      user.ID,
+		Author:        *user,
+		Title:         payload.Title,
+		Content:       payload.Content,
+		ContentFormat: constants.ContentFormatPlain,
+		Status:        constants.PostStatusDraft,
+		Visibility:    constants.PostVisibilityPublic,
+	}
+
+	if payload.ContentFormat != "" {
+		post.ContentFormat = payload.ContentFormat
+	}
+
+	if payload.Visibility != "" {
+		post.Visibility = payload.Visibility
+	}
+
+	status := payload.Status
+	if status == "" {
+		status = constants.PostStatusPublished
+	}
+
+	if err := applyPostStatus(&post, status, payload.PublishAt); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
 	}
 
-	if err := h.PostsStore.CreatePost(&post); err != nil {
+	mentions, err := h.resolveMentions(&post)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to resolve mentions")
+		return
+	}
+
+	if err := h.PostsStore.CreatePost(&post, payload.Tags, mentions); err != nil {
+		if errors.Is(err, tags.ErrTooManyTags) {
+			utils.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		utils.WriteError(w, http.StatusInternalServerError, "failed to create post")
 		return
 	}
 
-	postResponse, _ := h.fetchPostDetails(post.ID)
+	postResponse, _ := h.fetchPostDetails(post.ID, utils.ViewerID(r))
 	utils.WriteJSON(w, http.StatusCreated, postResponse)
 }
 
@@ -206,6 +467,11 @@ func (h *PostsHandler) UpdatePostByIDHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	if POST_EDIT_WINDOW > 0 && existingPost.PublishedAt != nil && time.Since(time.Unix(*existingPost.PublishedAt, 0)) > POST_EDIT_WINDOW {
+		utils.WriteError(w, http.StatusForbidden, "the edit window for this post has expired")
+		return
+	}
+
 	var payload postCreateUpdatePayload
 	if err := utils.ParseJSON(r, &payload); err != nil {
 		utils.WriteError(w, http.StatusBadRequest, err.Error())
@@ -217,15 +483,41 @@ func (h *PostsHandler) UpdatePostByIDHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	if err := validateTags(payload.Tags); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	existingPost.Title = payload.Title
 	existingPost.Content = payload.Content
+	if payload.ContentFormat != "" {
+		existingPost.ContentFormat = payload.ContentFormat
+	}
+	if payload.Visibility != "" {
+		existingPost.Visibility = payload.Visibility
+	}
+
+	if err := applyPostStatus(existingPost, payload.Status, payload.PublishAt); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
-	if err := h.PostsStore.UpdatePost(existingPost); err != nil {
+	mentions, err := h.resolveMentions(existingPost)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to resolve mentions")
+		return
+	}
+
+	if err := h.PostsStore.UpdatePost(existingPost, existingPost.AuthorID, payload.Tags, mentions); err != nil {
+		if errors.Is(err, tags.ErrTooManyTags) {
+			utils.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		utils.WriteError(w, http.StatusInternalServerError, "failed to update post")
 		return
 	}
 
-	postResponse, _ := h.fetchPostDetails(existingPost.ID)
+	postResponse, _ := h.fetchPostDetails(existingPost.ID, utils.ViewerID(r))
 	utils.WriteJSON(w, http.StatusOK, postResponse)
 }
 
@@ -236,19 +528,36 @@ func (h *PostsHandler) DeletePostByIDHandler(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	reason, err := utils.ParseDeleteReason(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	authUserID, ok := r.Context().Value(constants.UserIDKey).(string)
 	if !ok {
 		utils.WriteError(w, http.StatusUnauthorized, "user ID not found in context")
 		return
 	}
 
-	_, err = h.verifyOwnership(postID, authUserID)
+	post, err := h.PostsStore.GetPostByID(postID)
 	if err != nil {
-		utils.WriteError(w, http.StatusForbidden, err.Error())
+		utils.WriteError(w, http.StatusNotFound, "post not found")
 		return
 	}
 
-	if err := h.PostsStore.DeletePost(postID); err != nil {
+	user, err := h.AuthenticationStore.GetUserByID(authUserID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if post.AuthorID != user.ID && !utils.IsStaff(user.Role) {
+		utils.WriteError(w, http.StatusForbidden, "user does not own the resource")
+		return
+	}
+
+	if err := h.PostsStore.DeletePost(postID, user.ID, reason); err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, "failed to delete post")
 		return
 	}
@@ -256,6 +565,45 @@ func (h *PostsHandler) DeletePostByIDHandler(w http.ResponseWriter, r *http.Requ
 	utils.WriteJSON(w, http.StatusNoContent, nil)
 }
 
+func (h *PostsHandler) RestorePostHandler(w http.ResponseWriter, r *http.Request) {
+	postID, err := uuid.Parse(chi.URLParam(r, "postID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	authUserID, ok := r.Context().Value(constants.UserIDKey).(string)
+	if !ok {
+		utils.WriteError(w, http.StatusUnauthorized, "user ID not found in context")
+		return
+	}
+
+	post, err := h.PostsStore.GetDeletedPostByID(postID)
+	if err != nil {
+		utils.WriteError(w, http.StatusNotFound, "deleted post not found")
+		return
+	}
+
+	user, err := h.AuthenticationStore.GetUserByID(authUserID)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err := utils.CanRestore(user.ID, user.Role, post.AuthorID, post.DeletedByID, post.DeletedAt.Time); err != nil {
+		utils.WriteError(w, http.StatusForbidden, err.Error())
+		return
+	}
+
+	if err := h.PostsStore.RestorePost(postID); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to restore post")
+		return
+	}
+
+	postResponse, _ := h.fetchPostDetails(postID, user.ID)
+	utils.WriteJSON(w, http.StatusOK, postResponse)
+}
+
 func (h *PostsHandler) LikePostHandler(w http.ResponseWriter, r *http.Request) {
 	h.handleLikeDislikeRequest(w, r, "like")
 }
@@ -291,16 +639,302 @@ func (h *PostsHandler) handleLikeDislikeRequest(w http.ResponseWriter, r *http.R
 		return
 	}
 
+	if _, ok := h.verifyVisibility(w, postID, userUUID); !ok {
+		return
+	}
+
 	if err := h.handleLikeDislike(userUUID, postID, action); err != nil {
-		status := http.StatusInternalServerError
-		if err == errors.New("you have already liked this post") || err == errors.New("you haven't liked this post") ||
-			err == errors.New("you have already disliked this post") || err == errors.New("you haven't disliked this post") {
-			status = http.StatusConflict
+		switch {
+		case errors.Is(err, ErrAlreadyReacted), errors.Is(err, ErrReactionNotFound):
+			utils.WriteError(w, http.StatusConflict, likeDislikeConflicts[action])
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.WriteError(w, http.StatusNotFound, "post not found")
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	postResponse, _ := h.fetchPostDetails(postID, utils.ViewerID(r))
+	utils.WriteJSON(w, http.StatusOK, postResponse)
+}
+
+func (h *PostsHandler) parseReactionRequest(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, constants.ReactionType, bool) {
+	postID, err := uuid.Parse(chi.URLParam(r, "postID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return uuid.Nil, uuid.Nil, "", false
+	}
+
+	reactionType := constants.ReactionType(chi.URLParam(r, "reactionType"))
+	if !utils.IsValidReactionType(reactionType) {
+		utils.WriteError(w, http.StatusBadRequest, "invalid reaction type")
+		return uuid.Nil, uuid.Nil, "", false
+	}
+
+	userUUID := utils.ViewerID(r)
+	if userUUID == uuid.Nil {
+		utils.WriteError(w, http.StatusUnauthorized, "user ID not found in context")
+		return uuid.Nil, uuid.Nil, "", false
+	}
+
+	if _, ok := h.verifyVisibility(w, postID, userUUID); !ok {
+		return uuid.Nil, uuid.Nil, "", false
+	}
+
+	return postID, userUUID, reactionType, true
+}
+
+func (h *PostsHandler) PutPostReactionHandler(w http.ResponseWriter, r *http.Request) {
+	postID, userUUID, reactionType, ok := h.parseReactionRequest(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.PostsStore.ReactToPost(userUUID, postID, reactionType)
+	if err != nil && !errors.Is(err, ErrAlreadyReacted) {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.WriteError(w, http.StatusNotFound, "post not found")
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	postResponse, _ := h.fetchPostDetails(postID, userUUID)
+	utils.WriteJSON(w, http.StatusOK, postResponse)
+}
+
+func (h *PostsHandler) DeletePostReactionHandler(w http.ResponseWriter, r *http.Request) {
+	postID, userUUID, reactionType, ok := h.parseReactionRequest(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.PostsStore.RemovePostReaction(userUUID, postID, reactionType); err != nil {
+		switch {
+		case errors.Is(err, ErrReactionNotFound):
+			utils.WriteError(w, http.StatusNotFound, "reaction not found")
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			utils.WriteError(w, http.StatusNotFound, "post not found")
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, err.Error())
 		}
-		utils.WriteError(w, status, err.Error())
 		return
 	}
 
-	postResponse, _ := h.fetchPostDetails(postID)
+	postResponse, _ := h.fetchPostDetails(postID, userUUID)
 	utils.WriteJSON(w, http.StatusOK, postResponse)
 }
+
+func (h *PostsHandler) GetPostReactionsHandler(w http.ResponseWriter, r *http.Request) {
+	postID, err := uuid.Parse(chi.URLParam(r, "postID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	reactionType := constants.ReactionType(r.URL.Query().Get("type"))
+	if reactionType != "" && !utils.IsValidReactionType(reactionType) {
+		utils.WriteError(w, http.StatusBadRequest, "invalid reaction type")
+		return
+	}
+
+	if _, ok := h.verifyVisibility(w, postID, utils.ViewerID(r)); !ok {
+		return
+	}
+
+	limit := r.Context().Value(constants.LimitKey).(int)
+	offset := r.Context().Value(constants.OffsetKey).(int)
+
+	reactions, err := h.PostsStore.GetPostReactions(postID, reactionType, limit+1, offset)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	reactions, pageInfo, err := utils.PaginateResults(r, reactions)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if utils.IncludeTotal(r) {
+		total, err := h.PostsStore.CountPostReactions(postID, reactionType)
+		if err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		pageInfo.Total = &total
+	}
+
+	reactionResponses := make([]postReactionResponse, 0, len(reactions))
+	for _, reaction := range reactions {
+		definition, _ := utils.LookupReaction(reaction.Type)
+		reactionResponses = append(reactionResponses, postReactionResponse{
+			User: postCreateUpdateResponseAuthor{
+				ID:        reaction.User.ID,
+				FirstName: reaction.User.FirstName,
+				LastName:  reaction.User.LastName,
+				Email:     reaction.User.Email,
+			},
+			Type:      reaction.Type,
+			Emoji:     definition.Emoji,
+			CreatedAt: reaction.CreatedAt,
+		})
+	}
+
+	utils.WritePage(w, r, http.StatusOK, reactionResponses, pageInfo)
+}
+
+func (h *PostsHandler) handleBookmarkRequest(w http.ResponseWriter, r *http.Request, bookmark bool) {
+	postID, err := uuid.Parse(chi.URLParam(r, "postID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	userUUID := utils.ViewerID(r)
+	if userUUID == uuid.Nil {
+		utils.WriteError(w, http.StatusUnauthorized, "user ID not found in context")
+		return
+	}
+
+	if _, ok := h.verifyVisibility(w, postID, userUUID); !ok {
+		return
+	}
+
+	if bookmark {
+		err = h.PostsStore.BookmarkPost(userUUID, postID)
+	} else {
+		err = h.PostsStore.RemovePostBookmark(userUUID, postID)
+	}
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.WriteError(w, http.StatusNotFound, "post not found")
+			return
+		}
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	postResponse, err := h.fetchPostDetails(postID, userUUID)
+	if err != nil {
+		utils.WriteError(w, http.StatusNotFound, "post not found")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, postResponse)
+}
+
+func (h *PostsHandler) BookmarkPostHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleBookmarkRequest(w, r, true)
+}
+
+func (h *PostsHandler) UnbookmarkPostHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleBookmarkRequest(w, r, false)
+}
+
+func (h *PostsHandler) GetPostRevisionsHandler(w http.ResponseWriter, r *http.Request) {
+	postID, err := uuid.Parse(chi.URLParam(r, "postID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if _, ok := h.verifyVisibility(w, postID, utils.ViewerID(r)); !ok {
+		return
+	}
+
+	limit := r.Context().Value(constants.LimitKey).(int)
+	offset := r.Context().Value(constants.OffsetKey).(int)
+
+	revisions, err := h.PostsStore.GetPostRevisions(postID, limit+1, offset)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	revisions, pageInfo, err := utils.PaginateResults(r, revisions)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if utils.IncludeTotal(r) {
+		total, err := h.PostsStore.CountPostRevisions(postID)
+		if err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		pageInfo.Total = &total
+	}
+
+	revisionResponses := make([]postRevisionResponse, 0, len(revisions))
+	for _, revision := range revisions {
+		revisionResponses = append(revisionResponses, postRevisionResponse{
+			Version: revision.Version,
+			Editor: postCreateUpdateResponseAuthor{
+				ID:        revision.Editor.ID,
+				FirstName: revision.Editor.FirstName,
+				LastName:  revision.Editor.LastName,
+				Email:     revision.Editor.Email,
+			},
+			Title:     revision.Title,
+			Content:   revision.Content,
+			CreatedAt: revision.CreatedAt,
+		})
+	}
+
+	utils.WritePage(w, r, http.StatusOK, revisionResponses, pageInfo)
+}
+
+func (h *PostsHandler) GetPostRevisionsDiffHandler(w http.ResponseWriter, r *http.Request) {
+	postID, err := uuid.Parse(chi.URLParam(r, "postID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	post, ok := h.verifyVisibility(w, postID, utils.ViewerID(r))
+	if !ok {
+		return
+	}
+
+	if post.EditCount == 0 {
+		utils.WriteError(w, http.StatusNotFound, "post has no revisions")
+		return
+	}
+
+	to, err := utils.ParseRevisionVersion(r, "to", post.EditCount+1)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	from, err := utils.ParseRevisionVersion(r, "from", max(to-1, 1))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	fromRevision, err := h.PostsStore.GetPostRevision(postID, from)
+	if err != nil {
+		utils.WriteError(w, http.StatusNotFound, "revision not found")
+		return
+	}
+
+	toRevision, err := h.PostsStore.GetPostRevision(postID, to)
+	if err != nil {
+		utils.WriteError(w, http.StatusNotFound, "revision not found")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, postRevisionDiffResponse{
+		From:    from,
+		To:      to,
+		Title:   utils.DiffLines(fromRevision.Title, toRevision.Title),
+		Content: utils.DiffLines(fromRevision.Content, toRevision.Content),
+	})
+}
@@ -0,0 +1,56 @@
+package posts
+
+import "gopher-social-backend-server/internal/database"
+
+const RecomputeCountersSQL = `
+	UPDATE posts SET
+		likes_count = (SELECT COUNT(*) FROM post_reactions WHERE post_reactions.post_id = posts.id AND post_reactions.type = 'like'),
+		dislikes_count = (SELECT COUNT(*) FROM post_reactions WHERE post_reactions.post_id = posts.id AND post_reactions.type = 'dislike'),
+		comments_count = (SELECT COUNT(*) FROM comments WHERE comments.post_id = posts.id AND comments.deleted_at IS NULL),
+		reaction_counts = COALESCE((
+			SELECT jsonb_object_agg(type, total) FROM (
+				SELECT type, COUNT(*) AS total FROM post_reactions WHERE post_reactions.post_id = posts.id GROUP BY type
+			) counts
+		), '{}'::jsonb);
+`
+
+var Migrations = []database.Migration{
+	{
+		ID:  "0006_backfill_post_counters",
+		SQL: RecomputeCountersSQL,
+	},
+	{
+		ID: "0008_post_likes_dislikes_to_reactions",
+		SQL: `
+			DO $$
+			BEGIN
+				IF to_regclass('post_likes') IS NOT NULL THEN
+					INSERT INTO post_reactions (user_id, post_id, type, created_at, updated_at)
+					SELECT DISTINCT ON (user_id, post_id) user_id, post_id, 'like', EXTRACT(EPOCH FROM now())::bigint, EXTRACT(EPOCH FROM now())::bigint
+					FROM post_likes
+					ON CONFLICT (user_id, post_id) DO NOTHING;
+					DROP TABLE post_likes;
+				END IF;
+
+				IF to_regclass('post_dislikes') IS NOT NULL THEN
+					INSERT INTO post_reactions (user_id, post_id, type, created_at, updated_at)
+					SELECT DISTINCT ON (user_id, post_id) user_id, post_id, 'dislike', EXTRACT(EPOCH FROM now())::bigint, EXTRACT(EPOCH FROM now())::bigint
+					FROM post_dislikes
+					ON CONFLICT (user_id, post_id) DO NOTHING;
+					DROP TABLE post_dislikes;
+				END IF;
+			END $$;
+		` + RecomputeCountersSQL,
+	},
+	{
+		ID:  "0010_backfill_post_reaction_counts",
+		SQL: RecomputeCountersSQL,
+	},
+	{
+		ID: "0013_backfill_post_published_at",
+		SQL: `
+			UPDATE posts SET published_at = created_at
+			WHERE status = 'published' AND published_at IS NULL;
+		`,
+	},
+}
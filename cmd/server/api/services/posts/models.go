@@ -2,32 +2,76 @@ package posts
 
 import (
 	"gopher-social-backend-server/cmd/server/api/services/authentication"
+	"gopher-social-backend-server/pkg/constants"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Post struct {
+	ID                   uuid.UUID                        `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	AuthorID             uuid.UUID                        `json:"author_id" gorm:"type:uuid;not null;index"`
+	Author               authentication.User              `json:"author" gorm:"foreignKey:AuthorID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Title                string                           `json:"title" gorm:"type:varchar(255);not null"`
+	Content              string                           `json:"content" gorm:"type:text;not null"`
+	ContentFormat        constants.ContentFormat          `json:"content_format" gorm:"type:varchar(16);not null;default:'plain'"`
+	ContentHTML          string                           `json:"content_html" gorm:"type:text;not null;default:''"`
+	ContentRenderVersion int                              `json:"-" gorm:"not null;default:0"`
+	LikesCount           int64                            `json:"likes_count" gorm:"not null;default:0;index"`
+	DislikesCount        int64                            `json:"dislikes_count" gorm:"not null;default:0;index"`
+	CommentsCount        int64                            `json:"comments_count" gorm:"not null;default:0;index"`
+	EditCount            int64                            `json:"edit_count" gorm:"not null;default:0"`
+	Status               constants.PostStatus             `json:"status" gorm:"type:varchar(16);not null;default:'published';index"`
+	Visibility           constants.PostVisibility         `json:"visibility" gorm:"type:varchar(16);not null;default:'public';index"`
+	PublishAt            *int64                           `json:"publish_at" gorm:"index"`
+	PublishedAt          *int64                           `json:"published_at" gorm:"index"`
+	ReactionCounts       map[constants.ReactionType]int64 `json:"reaction_counts" gorm:"type:jsonb;not null;default:'{}';serializer:json"`
+	CreatedAt            int64                            `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt            int64                            `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt            gorm.DeletedAt                   `json:"-" gorm:"index"`
+	DeletedByID          *uuid.UUID                       `json:"-" gorm:"type:uuid"`
+	DeleteReason         string                           `json:"-" gorm:"type:varchar(255)"`
+}
+
+type PostReaction struct {
+	ID        uuid.UUID              `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	UserID    uuid.UUID              `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_post_reactions_user_post"`
+	User      authentication.User    `json:"user" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	PostID    uuid.UUID              `json:"post_id" gorm:"type:uuid;not null;uniqueIndex:idx_post_reactions_user_post;index;index:idx_post_reactions_post_type"`
+	Post      Post                   `json:"post" gorm:"foreignKey:PostID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Type      constants.ReactionType `json:"type" gorm:"type:varchar(32);not null;index:idx_post_reactions_post_type"`
+	CreatedAt int64                  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt int64                  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+type PostBookmark struct {
 	ID        uuid.UUID           `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	AuthorID  uuid.UUID           `json:"author_id" gorm:"type:uuid;not null;index"`
-	Author    authentication.User `json:"author" gorm:"foreignKey:AuthorID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
-	Title     string              `json:"title" gorm:"type:varchar(255);not null"`
-	Content   string              `json:"content" gorm:"type:text;not null"`
+	UserID    uuid.UUID           `json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_post_bookmarks_user_post"`
+	User      authentication.User `json:"user" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	PostID    uuid.UUID           `json:"post_id" gorm:"type:uuid;not null;uniqueIndex:idx_post_bookmarks_user_post;index"`
+	Post      Post                `json:"post" gorm:"foreignKey:PostID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
 	CreatedAt int64               `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt int64               `json:"updated_at" gorm:"autoUpdateTime"`
 }
 
-type PostLike struct {
-	ID     uuid.UUID           `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	UserID uuid.UUID           `json:"user_id" gorm:"type:uuid;not null"`
-	User   authentication.User `json:"user" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
-	PostID uuid.UUID           `json:"post_id" gorm:"type:uuid;not null"`
-	Post   Post                `json:"post" gorm:"foreignKey:PostID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+type PostRevision struct {
+	ID        uuid.UUID           `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	PostID    uuid.UUID           `json:"post_id" gorm:"type:uuid;not null;uniqueIndex:idx_post_revisions_post_version"`
+	Post      Post                `json:"post" gorm:"foreignKey:PostID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Version   int64               `json:"version" gorm:"not null;uniqueIndex:idx_post_revisions_post_version"`
+	EditorID  uuid.UUID           `json:"editor_id" gorm:"type:uuid;not null;index"`
+	Editor    authentication.User `json:"editor" gorm:"foreignKey:EditorID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Title     string              `json:"title" gorm:"type:varchar(255);not null"`
+	Content   string              `json:"content" gorm:"type:text;not null"`
+	CreatedAt int64               `json:"created_at" gorm:"autoCreateTime"`
 }
 
-type PostDislike struct {
-	ID     uuid.UUID           `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
-	UserID uuid.UUID           `json:"user_id" gorm:"type:uuid;not null"`
-	User   authentication.User `json:"user" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
-	PostID uuid.UUID           `json:"post_id" gorm:"type:uuid;not null"`
-	Post   Post                `json:"post" gorm:"foreignKey:PostID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+type PostMention struct {
+	ID        uuid.UUID           `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	PostID    uuid.UUID           `json:"post_id" gorm:"type:uuid;not null;uniqueIndex:idx_post_mentions_post_start"`
+	Post      Post                `json:"post" gorm:"foreignKey:PostID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	UserID    uuid.UUID           `json:"user_id" gorm:"type:uuid;not null;index"`
+	User      authentication.User `json:"user" gorm:"foreignKey:UserID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Start     int                 `json:"start" gorm:"not null;uniqueIndex:idx_post_mentions_post_start"`
+	End       int                 `json:"end" gorm:"not null"`
+	CreatedAt int64               `json:"created_at" gorm:"autoCreateTime"`
 }
@@ -2,19 +2,32 @@ package posts
 
 import (
 	"gopher-social-backend-server/internal/middlewares"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/ratelimiter"
 
 	"github.com/go-chi/chi/v5"
 )
 
 func RegisterPostsRoutes(router chi.Router, handler *PostsHandler) {
-	router.Get("/posts/{postID}", handler.GetPostByIDHandler)
-	router.With(middlewares.PaginationMiddleware, middlewares.OrderingMiddleware).Get("/posts", handler.GetPostsHandler)
+	diffRateLimiter := ratelimiter.NewRateLimiter(DIFF_RATE_LIMIT)
+
+	router.With(middlewares.OptionalAuthMiddleware).Get("/posts/{postID}", handler.GetPostByIDHandler)
+	router.With(middlewares.OptionalAuthMiddleware, middlewares.CursorPaginationMiddleware, middlewares.OrderingMiddleware(constants.PostSortColumns)).Get("/posts", handler.GetPostsHandler)
+	router.With(middlewares.OptionalAuthMiddleware, middlewares.CursorPaginationMiddleware, middlewares.OrderingMiddleware(constants.PostSortColumns)).Get("/tags/{tag}/posts", handler.GetPostsByTagHandler)
+	router.With(middlewares.OptionalAuthMiddleware, middlewares.PaginationMiddleware).Get("/posts/{postID}/reactions", handler.GetPostReactionsHandler)
+	router.With(middlewares.OptionalAuthMiddleware, middlewares.PaginationMiddleware).Get("/posts/{postID}/revisions", handler.GetPostRevisionsHandler)
+	router.With(middlewares.OptionalAuthMiddleware, middlewares.RateLimiterMiddleware(diffRateLimiter)).Get("/posts/{postID}/revisions/diff", handler.GetPostRevisionsDiffHandler)
+	router.With(middlewares.AuthMiddleware, middlewares.PaginationMiddleware).Get("/posts/drafts", handler.GetDraftsHandler)
 	router.With(middlewares.AuthMiddleware).Post("/posts", handler.CreatePostHandler)
 	router.With(middlewares.AuthMiddleware).Patch("/posts/{postID}", handler.UpdatePostByIDHandler)
 	router.With(middlewares.AuthMiddleware).Delete("/posts/{postID}", handler.DeletePostByIDHandler)
+	router.With(middlewares.AuthMiddleware).Post("/posts/{postID}/restore", handler.RestorePostHandler)
 	router.With(middlewares.AuthMiddleware).Post("/posts/{postID}/like", handler.LikePostHandler)
 	router.With(middlewares.AuthMiddleware).Delete("/posts/{postID}/like", handler.UnlikePostHandler)
 	router.With(middlewares.AuthMiddleware).Post("/posts/{postID}/dislike", handler.DislikePostHandler)
 	router.With(middlewares.AuthMiddleware).Delete("/posts/{postID}/dislike", handler.UndislikePostHandler)
-
+	router.With(middlewares.AuthMiddleware).Put("/posts/{postID}/reactions/{reactionType}", handler.PutPostReactionHandler)
+	router.With(middlewares.AuthMiddleware).Delete("/posts/{postID}/reactions/{reactionType}", handler.DeletePostReactionHandler)
+	router.With(middlewares.AuthMiddleware).Put("/posts/{postID}/bookmark", handler.BookmarkPostHandler)
+	router.With(middlewares.AuthMiddleware).Delete("/posts/{postID}/bookmark", handler.UnbookmarkPostHandler)
 }
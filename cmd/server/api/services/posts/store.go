@@ -1,24 +1,55 @@
 package posts
 
 import (
+	"errors"
+	"gopher-social-backend-server/cmd/server/api/services/tags"
+	"gopher-social-backend-server/internal/database"
+	"gopher-social-backend-server/internal/events"
+	"gopher-social-backend-server/internal/pubsub"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/utils"
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrAlreadyReacted   = errors.New("reaction already exists")
+	ErrReactionNotFound = errors.New("reaction not found")
 )
 
 type PostsStore interface {
-	CreatePost(post *Post) error
+	CreatePost(post *Post, explicitTags *[]string, mentions []PostMention) error
 	GetPostByID(postID uuid.UUID) (*Post, error)
-	GetPosts(limit, offset int, orderby string, desc bool) ([]Post, error)
-	UpdatePost(post *Post) error
-	DeletePost(postID uuid.UUID) error
-	LikePost(userID, postID uuid.UUID) error
-	DislikePost(userID, postID uuid.UUID) error
-	RemoveLike(userID, postID uuid.UUID) error
-	RemoveDislike(userID, postID uuid.UUID) error
-	HasLiked(userID, postID uuid.UUID) (bool, error)
-	HasDisliked(userID, postID uuid.UUID) (bool, error)
-	GetLikesCount(postID uuid.UUID) (int64, error)
-	GetDislikesCount(postID uuid.UUID) (int64, error)
+	GetVisiblePostByID(postID, viewerID uuid.UUID) (*Post, error)
+	GetPosts(viewerID uuid.UUID, limit, offset int, cursor *utils.Cursor, orderby string, desc bool) ([]Post, error)
+	GetPostsByTag(tag string, viewerID uuid.UUID, limit, offset int, cursor *utils.Cursor, orderby string, desc bool) ([]Post, error)
+	CountPosts(viewerID uuid.UUID) (int64, error)
+	CountPostsByTag(tag string, viewerID uuid.UUID) (int64, error)
+	GetPostsByAuthor(authorID uuid.UUID, statuses []constants.PostStatus, limit, offset int) ([]Post, error)
+	CountPostsByAuthor(authorID uuid.UUID, statuses []constants.PostStatus) (int64, error)
+	PublishDuePosts(now time.Time, limit int) (int64, error)
+	UpdatePost(post *Post, editorID uuid.UUID, explicitTags *[]string, mentions []PostMention) error
+	GetPostRevisions(postID uuid.UUID, limit, offset int) ([]PostRevision, error)
+	CountPostRevisions(postID uuid.UUID) (int64, error)
+	GetPostRevision(postID uuid.UUID, version int64) (*PostRevision, error)
+	DeletePost(postID, deletedByID uuid.UUID, reason string) error
+	GetDeletedPostByID(postID uuid.UUID) (*Post, error)
+	RestorePost(postID uuid.UUID) error
+	PurgeDeletedPosts(before time.Time) (int64, error)
+	ReactToPost(userID, postID uuid.UUID, reactionType constants.ReactionType) error
+	RemovePostReaction(userID, postID uuid.UUID, reactionType constants.ReactionType) error
+	GetUserReactionsForPosts(userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]constants.ReactionType, error)
+	GetPostReactions(postID uuid.UUID, reactionType constants.ReactionType, limit, offset int) ([]PostReaction, error)
+	CountPostReactions(postID uuid.UUID, reactionType constants.ReactionType) (int64, error)
+	BookmarkPost(userID, postID uuid.UUID) error
+	RemovePostBookmark(userID, postID uuid.UUID) error
+	GetUserBookmarksForPosts(userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+	GetMentionsForPosts(postIDs []uuid.UUID) (map[uuid.UUID][]PostMention, error)
+	RecomputeCounters() error
+	GetDigestPosts(userID uuid.UUID, since time.Time, limit int) ([]Post, error)
 }
 
 type postsStore struct {
@@ -31,91 +62,636 @@ func NewPostsStore(postgresDB *gorm.DB) PostsStore {
 	}
 }
 
-func (s *postsStore) CreatePost(post *Post) error {
-	return s.postgresDB.Create(post).Error
+const followsAuthorSQL = "EXISTS (SELECT 1 FROM follows WHERE follows.follower_id = ? AND follows.followee_id = posts.author_id)"
+
+func listedPosts(viewerID uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Where("posts.status = ?", constants.PostStatusPublished).
+			Where("(posts.visibility = ? OR (posts.visibility = ? AND (posts.author_id = ? OR "+followsAuthorSQL+")))",
+				constants.PostVisibilityPublic, constants.PostVisibilityFollowers, viewerID, viewerID)
+	}
+}
+
+func visiblePosts(viewerID uuid.UUID) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Where("(posts.status IN ? OR posts.author_id = ?)",
+				[]constants.PostStatus{constants.PostStatusPublished, constants.PostStatusArchived}, viewerID).
+			Where("(posts.visibility IN ? OR posts.author_id = ? OR (posts.visibility = ? AND "+followsAuthorSQL+"))",
+				[]constants.PostVisibility{constants.PostVisibilityPublic, constants.PostVisibilityUnlisted}, viewerID, constants.PostVisibilityFollowers, viewerID)
+	}
+}
+
+func renderPostContent(post *Post) error {
+	if post.ContentFormat == "" {
+		post.ContentFormat = constants.ContentFormatPlain
+	}
+
+	rendered, err := utils.RenderContent(post.ContentFormat, post.Content)
+	if err != nil {
+		return err
+	}
+
+	post.ContentHTML = rendered
+	post.ContentRenderVersion = constants.ContentRenderVersion
+	return nil
+}
+
+func postHashtags(post *Post) []string {
+	return append(utils.ExtractHashtags(post.Title), utils.ExtractHashtags(post.Content)...)
+}
+
+func (s *postsStore) CreatePost(post *Post, explicitTags *[]string, mentions []PostMention) error {
+	if post.ReactionCounts == nil {
+		post.ReactionCounts = make(map[constants.ReactionType]int64)
+	}
+
+	if err := renderPostContent(post); err != nil {
+		return err
+	}
+
+	return s.postgresDB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(post).Error; err != nil {
+			return err
+		}
+
+		if err := tags.SetPostTags(tx, post.ID, postHashtags(post), explicitTags); err != nil {
+			return err
+		}
+
+		mentioned, _, err := setPostMentions(tx, post.ID, mentions)
+		if err != nil {
+			return err
+		}
+
+		if err := recordPostMentions(tx, post, mentioned); err != nil {
+			return err
+		}
+
+		return recordPostCreated(tx, post)
+	})
+}
+
+func recordPostCreated(tx *gorm.DB, post *Post) error {
+	if post.PublishedAt == nil {
+		return nil
+	}
+
+	return events.Record(tx, constants.DomainEventPostCreated, events.PostCreated{PostID: post.ID, AuthorID: post.AuthorID})
+}
+
+func recordPostMentions(tx *gorm.DB, post *Post, userIDs []uuid.UUID) error {
+	if post.PublishedAt == nil || len(userIDs) == 0 {
+		return nil
+	}
+
+	return events.Record(tx, constants.DomainEventUsersMentioned, events.UsersMentioned{ActorID: post.AuthorID, PostID: post.ID, UserIDs: userIDs})
 }
 
 func (s *postsStore) GetPostByID(postID uuid.UUID) (*Post, error) {
 	var post Post
-	if err := s.postgresDB.Where("id = ?", postID).First(&post).Error; err != nil {
+	if err := s.postgresDB.Preload("Author").Where("id = ?", postID).First(&post).Error; err != nil {
 		return nil, err
 	}
 	return &post, nil
 }
 
-func (s *postsStore) GetPosts(limit, offset int, orderby string, desc bool) ([]Post, error) {
+func (s *postsStore) GetVisiblePostByID(postID, viewerID uuid.UUID) (*Post, error) {
+	var post Post
+	if err := s.postgresDB.Preload("Author").Scopes(visiblePosts(viewerID)).Where("posts.id = ?", postID).First(&post).Error; err != nil {
+		return nil, err
+	}
+	return &post, nil
+}
+
+func (s *postsStore) GetPosts(viewerID uuid.UUID, limit, offset int, cursor *utils.Cursor, orderby string, desc bool) ([]Post, error) {
+	var posts []Post
+
+	if err := s.postgresDB.Preload("Author").Scopes(listedPosts(viewerID), database.Paginate("posts", limit, offset, cursor, orderby, desc)).Find(&posts).Error; err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+func (s *postsStore) GetPostsByTag(tag string, viewerID uuid.UUID, limit, offset int, cursor *utils.Cursor, orderby string, desc bool) ([]Post, error) {
 	var posts []Post
 
-	order := orderby
-	if desc {
-		order += " DESC"
-	} else {
-		order += " ASC"
+	if err := s.postgresDB.Preload("Author").
+		Joins("JOIN post_tags ON post_tags.post_id = posts.id").
+		Joins("JOIN tags ON tags.id = post_tags.tag_id").
+		Where("tags.name = ?", tag).
+		Scopes(listedPosts(viewerID), database.Paginate("posts", limit, offset, cursor, orderby, desc)).
+		Find(&posts).Error; err != nil {
+		return nil, err
 	}
 
-	if err := s.postgresDB.Limit(limit).Offset(offset).Order(order).Find(&posts).Error; err != nil {
+	return posts, nil
+}
+
+func (s *postsStore) CountPosts(viewerID uuid.UUID) (int64, error) {
+	var count int64
+	err := s.postgresDB.Model(&Post{}).Scopes(listedPosts(viewerID)).Count(&count).Error
+	return count, err
+}
+
+func (s *postsStore) CountPostsByTag(tag string, viewerID uuid.UUID) (int64, error) {
+	var count int64
+	err := s.postgresDB.Model(&Post{}).
+		Joins("JOIN post_tags ON post_tags.post_id = posts.id").
+		Joins("JOIN tags ON tags.id = post_tags.tag_id").
+		Where("tags.name = ?", tag).
+		Scopes(listedPosts(viewerID)).
+		Count(&count).Error
+	return count, err
+}
+
+func (s *postsStore) GetPostsByAuthor(authorID uuid.UUID, statuses []constants.PostStatus, limit, offset int) ([]Post, error) {
+	var posts []Post
+
+	if err := s.postgresDB.Preload("Author").
+		Where("author_id = ? AND status IN ?", authorID, statuses).
+		Order("updated_at DESC").Order("id").
+		Limit(limit).Offset(offset).
+		Find(&posts).Error; err != nil {
 		return nil, err
 	}
 
 	return posts, nil
 }
 
-func (s *postsStore) UpdatePost(post *Post) error {
-	return s.postgresDB.Save(post).Error
+func (s *postsStore) CountPostsByAuthor(authorID uuid.UUID, statuses []constants.PostStatus) (int64, error) {
+	var count int64
+	err := s.postgresDB.Model(&Post{}).Where("author_id = ? AND status IN ?", authorID, statuses).Count(&count).Error
+	return count, err
+}
+
+func (s *postsStore) UpdatePost(post *Post, editorID uuid.UUID, explicitTags *[]string, mentions []PostMention) error {
+	return s.postgresDB.Transaction(func(tx *gorm.DB) error {
+		var current Post
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "author_id", "title", "content", "content_format", "content_render_version", "edit_count", "status", "publish_at", "published_at", "created_at").First(&current, "id = ?", post.ID).Error; err != nil {
+			return err
+		}
+
+		if err := tags.SetPostTags(tx, post.ID, postHashtags(post), explicitTags); err != nil {
+			return err
+		}
+
+		post.EditCount = current.EditCount
+		textChanged := current.Title != post.Title || current.Content != post.Content
+
+		if textChanged && current.PublishedAt != nil {
+			if current.EditCount == 0 {
+				if err := tx.Create(&PostRevision{
+					PostID:    current.ID,
+					Version:   1,
+					EditorID:  current.AuthorID,
+					Title:     current.Title,
+					Content:   current.Content,
+					CreatedAt: *current.PublishedAt,
+				}).Error; err != nil {
+					return err
+				}
+			}
+
+			post.EditCount = current.EditCount + 1
+			if err := tx.Create(&PostRevision{
+				PostID:   post.ID,
+				Version:  post.EditCount + 1,
+				EditorID: editorID,
+				Title:    post.Title,
+				Content:  post.Content,
+			}).Error; err != nil {
+				return err
+			}
+		}
+
+		formatChanged := current.ContentFormat != post.ContentFormat
+		if !textChanged && !formatChanged && current.Status == post.Status && equalTimestamps(current.PublishAt, post.PublishAt) {
+			return nil
+		}
+
+		if err := renderPostContent(post); err != nil {
+			return err
+		}
+
+		if err := tx.Model(post).Select("title", "content", "content_format", "content_html", "content_render_version", "edit_count", "status", "publish_at", "published_at", "updated_at").Updates(post).Error; err != nil {
+			return err
+		}
+
+		var (
+			mentioned, added []uuid.UUID
+			err              error
+		)
+		if textChanged {
+			mentioned, added, err = setPostMentions(tx, post.ID, mentions)
+		} else {
+			err = tx.Model(&PostMention{}).Where("post_id = ?", post.ID).Distinct().Pluck("user_id", &mentioned).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		if current.PublishedAt != nil {
+			return recordPostMentions(tx, post, added)
+		}
+
+		if err := recordPostMentions(tx, post, mentioned); err != nil {
+			return err
+		}
+		return recordPostCreated(tx, post)
+	})
+}
+
+func equalTimestamps(a, b *int64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
 }
 
-func (s *postsStore) DeletePost(postID uuid.UUID) error {
-	return s.postgresDB.Delete(&Post{}, postID).Error
+func (s *postsStore) GetPostRevisions(postID uuid.UUID, limit, offset int) ([]PostRevision, error) {
+	var revisions []PostRevision
+
+	if err := s.postgresDB.Preload("Editor").Where("post_id = ?", postID).
+		Order("version DESC").Limit(limit).Offset(offset).
+		Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
 }
 
-func (s *postsStore) LikePost(userID, postID uuid.UUID) error {
-	return s.postgresDB.Create(&PostLike{UserID: userID, PostID: postID}).Error
+func (s *postsStore) CountPostRevisions(postID uuid.UUID) (int64, error) {
+	var count int64
+	err := s.postgresDB.Model(&PostRevision{}).Where("post_id = ?", postID).Count(&count).Error
+	return count, err
 }
 
-func (s *postsStore) DislikePost(userID, postID uuid.UUID) error {
-	return s.postgresDB.Create(&PostDislike{UserID: userID, PostID: postID}).Error
+func (s *postsStore) GetPostRevision(postID uuid.UUID, version int64) (*PostRevision, error) {
+	var revision PostRevision
+	err := s.postgresDB.Preload("Editor").First(&revision, "post_id = ? AND version = ?", postID, version).Error
+	return &revision, err
 }
 
-func (s *postsStore) RemoveLike(userID, postID uuid.UUID) error {
-	return s.postgresDB.Where("user_id = ? AND post_id = ?", userID, postID).Delete(&PostLike{}).Error
+func (s *postsStore) DeletePost(postID, deletedByID uuid.UUID, reason string) error {
+	result := s.postgresDB.Model(&Post{}).Where("id = ?", postID).UpdateColumns(map[string]interface{}{
+		"deleted_at":    time.Now(),
+		"deleted_by_id": deletedByID,
+		"delete_reason": reason,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
 }
 
-func (s *postsStore) RemoveDislike(userID, postID uuid.UUID) error {
-	return s.postgresDB.Where("user_id = ? AND post_id = ?", userID, postID).Delete(&PostDislike{}).Error
+func (s *postsStore) GetDeletedPostByID(postID uuid.UUID) (*Post, error) {
+	var post Post
+	err := s.postgresDB.Unscoped().Preload("Author").Where("deleted_at IS NOT NULL").First(&post, "id = ?", postID).Error
+	return &post, err
 }
 
-func (s *postsStore) HasLiked(userID, postID uuid.UUID) (bool, error) {
-	var like PostLike
-	err := s.postgresDB.Where("user_id = ? AND post_id = ?", userID, postID).First(&like).Error
-	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return false, nil
+func (s *postsStore) RestorePost(postID uuid.UUID) error {
+	result := s.postgresDB.Unscoped().Model(&Post{}).Where("id = ? AND deleted_at IS NOT NULL", postID).UpdateColumns(map[string]interface{}{
+		"deleted_at":    nil,
+		"deleted_by_id": nil,
+		"delete_reason": "",
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (s *postsStore) PublishDuePosts(now time.Time, limit int) (int64, error) {
+	var published int64
+
+	err := s.postgresDB.Transaction(func(tx *gorm.DB) error {
+		var postIDs []uuid.UUID
+		if err := tx.Model(&Post{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND publish_at <= ?", constants.PostStatusScheduled, now.Unix()).
+			Order("publish_at").Limit(limit).
+			Pluck("id", &postIDs).Error; err != nil {
+			return err
 		}
-		return false, err
+
+		if len(postIDs) == 0 {
+			return nil
+		}
+
+		result := tx.Model(&Post{}).Where("id IN ?", postIDs).UpdateColumns(map[string]interface{}{
+			"status":       constants.PostStatusPublished,
+			"published_at": gorm.Expr("publish_at"),
+			"updated_at":   now.Unix(),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		published = result.RowsAffected
+
+		var publishedPosts []Post
+		if err := tx.Where("id IN ?", postIDs).Find(&publishedPosts).Error; err != nil {
+			return err
+		}
+
+		for i := range publishedPosts {
+			var mentioned []uuid.UUID
+			if err := tx.Model(&PostMention{}).Where("post_id = ?", publishedPosts[i].ID).Distinct().Pluck("user_id", &mentioned).Error; err != nil {
+				return err
+			}
+
+			if err := recordPostMentions(tx, &publishedPosts[i], mentioned); err != nil {
+				return err
+			}
+
+			if err := recordPostCreated(tx, &publishedPosts[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return published, err
+}
+
+func (s *postsStore) PurgeDeletedPosts(before time.Time) (int64, error) {
+	result := s.postgresDB.Unscoped().Where("deleted_at < ?", before).Delete(&Post{})
+	return result.RowsAffected, result.Error
+}
+
+func adjustReactionCount(tx *gorm.DB, postID uuid.UUID, reactionType constants.ReactionType, delta int64) error {
+	updates := map[string]interface{}{
+		"reaction_counts": gorm.Expr(
+			"jsonb_set(reaction_counts, ARRAY[?::text], to_jsonb(COALESCE((reaction_counts->>?::text)::bigint, 0) + ?))",
+			reactionType, reactionType, delta,
+		),
 	}
-	return true, nil
+	if column, ok := constants.ReactionCounterColumns[reactionType]; ok {
+		updates[column] = gorm.Expr(column+" + ?", delta)
+	}
+
+	return tx.Model(&Post{}).Where("id = ?", postID).UpdateColumns(updates).Error
+}
+
+func publishReactions(tx *gorm.DB, postID uuid.UUID) error {
+	var post Post
+	if err := tx.Select("id", "likes_count", "dislikes_count", "reaction_counts").First(&post, "id = ?", postID).Error; err != nil {
+		return err
+	}
+
+	return pubsub.Publish(tx, pubsub.PostTopic(post.ID), constants.StreamEventReactionsUpdated, reactionsEvent{
+		PostID:    post.ID,
+		Likes:     post.LikesCount,
+		Dislikes:  post.DislikesCount,
+		Reactions: utils.ReactionCounts(post.ReactionCounts),
+	})
 }
 
-func (s *postsStore) HasDisliked(userID, postID uuid.UUID) (bool, error) {
-	var dislike PostDislike
-	err := s.postgresDB.Where("user_id = ? AND post_id = ?", userID, postID).First(&dislike).Error
+func lockReaction(tx *gorm.DB, userID, postID uuid.UUID) (*PostReaction, error) {
+	var post Post
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&post, "id = ?", postID).Error; err != nil {
+		return nil, err
+	}
+
+	var reaction PostReaction
+	err := tx.Where("user_id = ? AND post_id = ?", userID, postID).First(&reaction).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return false, nil
+		return nil, err
+	}
+
+	return &reaction, nil
+}
+
+func (s *postsStore) ReactToPost(userID, postID uuid.UUID, reactionType constants.ReactionType) error {
+	return s.postgresDB.Transaction(func(tx *gorm.DB) error {
+		existing, err := lockReaction(tx, userID, postID)
+		if err != nil {
+			return err
+		}
+
+		if existing != nil && existing.Type == reactionType {
+			return ErrAlreadyReacted
+		}
+
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "post_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"type", "updated_at"}),
+		}).Create(&PostReaction{UserID: userID, PostID: postID, Type: reactionType}).Error; err != nil {
+			return err
 		}
-		return false, err
+
+		if existing != nil {
+			if err := adjustReactionCount(tx, postID, existing.Type, -1); err != nil {
+				return err
+			}
+		}
+
+		if err := adjustReactionCount(tx, postID, reactionType, 1); err != nil {
+			return err
+		}
+
+		previous := constants.ReactionType("")
+		if existing != nil {
+			previous = existing.Type
+		}
+		if err := events.Record(tx, constants.DomainEventReactionChanged, events.ReactionChanged{UserID: userID, PostID: postID, Type: reactionType, Previous: previous}); err != nil {
+			return err
+		}
+
+		return publishReactions(tx, postID)
+	})
+}
+
+func (s *postsStore) RemovePostReaction(userID, postID uuid.UUID, reactionType constants.ReactionType) error {
+	return s.postgresDB.Transaction(func(tx *gorm.DB) error {
+		existing, err := lockReaction(tx, userID, postID)
+		if err != nil {
+			return err
+		}
+
+		if existing == nil || existing.Type != reactionType {
+			return ErrReactionNotFound
+		}
+
+		if err := tx.Delete(existing).Error; err != nil {
+			return err
+		}
+
+		if err := adjustReactionCount(tx, postID, reactionType, -1); err != nil {
+			return err
+		}
+
+		if err := events.Record(tx, constants.DomainEventReactionChanged, events.ReactionChanged{UserID: userID, PostID: postID, Previous: reactionType}); err != nil {
+			return err
+		}
+
+		return publishReactions(tx, postID)
+	})
+}
+
+func (s *postsStore) GetUserReactionsForPosts(userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]constants.ReactionType, error) {
+	reactionsByPost := make(map[uuid.UUID]constants.ReactionType, len(postIDs))
+	if userID == uuid.Nil || len(postIDs) == 0 {
+		return reactionsByPost, nil
 	}
-	return true, nil
+
+	var reactions []PostReaction
+	if err := s.postgresDB.Select("post_id", "type").Where("user_id = ? AND post_id IN ?", userID, postIDs).Find(&reactions).Error; err != nil {
+		return nil, err
+	}
+
+	for _, reaction := range reactions {
+		reactionsByPost[reaction.PostID] = reaction.Type
+	}
+
+	return reactionsByPost, nil
 }
 
-func (s *postsStore) GetLikesCount(postID uuid.UUID) (int64, error) {
-	var count int64
-	err := s.postgresDB.Model(&PostLike{}).Where("post_id = ?", postID).Count(&count).Error
-	return count, err
+func filterReactions(postID uuid.UUID, reactionType constants.ReactionType) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("post_id = ?", postID)
+		if reactionType != "" {
+			db = db.Where("type = ?", reactionType)
+		}
+		return db
+	}
 }
 
-func (s *postsStore) GetDislikesCount(postID uuid.UUID) (int64, error) {
+func (s *postsStore) GetPostReactions(postID uuid.UUID, reactionType constants.ReactionType, limit, offset int) ([]PostReaction, error) {
+	var reactions []PostReaction
+
+	if err := s.postgresDB.Preload("User").Scopes(filterReactions(postID, reactionType)).
+		Order("created_at DESC, id").Limit(limit).Offset(offset).
+		Find(&reactions).Error; err != nil {
+		return nil, err
+	}
+
+	return reactions, nil
+}
+
+func (s *postsStore) CountPostReactions(postID uuid.UUID, reactionType constants.ReactionType) (int64, error) {
 	var count int64
-	err := s.postgresDB.Model(&PostDislike{}).Where("post_id = ?", postID).Count(&count).Error
+	err := s.postgresDB.Model(&PostReaction{}).Scopes(filterReactions(postID, reactionType)).Count(&count).Error
 	return count, err
 }
+
+func (s *postsStore) BookmarkPost(userID, postID uuid.UUID) error {
+	var post Post
+	if err := s.postgresDB.Select("id").First(&post, "id = ?", postID).Error; err != nil {
+		return err
+	}
+
+	return s.postgresDB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "post_id"}},
+		DoNothing: true,
+	}).Create(&PostBookmark{UserID: userID, PostID: postID}).Error
+}
+
+func (s *postsStore) RemovePostBookmark(userID, postID uuid.UUID) error {
+	return s.postgresDB.Where("user_id = ? AND post_id = ?", userID, postID).Delete(&PostBookmark{}).Error
+}
+
+func (s *postsStore) GetUserBookmarksForPosts(userID uuid.UUID, postIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	bookmarksByPost := make(map[uuid.UUID]bool, len(postIDs))
+	if userID == uuid.Nil || len(postIDs) == 0 {
+		return bookmarksByPost, nil
+	}
+
+	var bookmarkedIDs []uuid.UUID
+	if err := s.postgresDB.Model(&PostBookmark{}).Where("user_id = ? AND post_id IN ?", userID, postIDs).Pluck("post_id", &bookmarkedIDs).Error; err != nil {
+		return nil, err
+	}
+
+	for _, postID := range bookmarkedIDs {
+		bookmarksByPost[postID] = true
+	}
+
+	return bookmarksByPost, nil
+}
+
+func setPostMentions(tx *gorm.DB, postID uuid.UUID, mentions []PostMention) ([]uuid.UUID, []uuid.UUID, error) {
+	var existing []uuid.UUID
+	if err := tx.Model(&PostMention{}).Where("post_id = ?", postID).Distinct().Pluck("user_id", &existing).Error; err != nil {
+		return nil, nil, err
+	}
+
+	if err := tx.Where("post_id = ?", postID).Delete(&PostMention{}).Error; err != nil {
+		return nil, nil, err
+	}
+
+	for i := range mentions {
+		mentions[i].PostID = postID
+	}
+
+	if len(mentions) > 0 {
+		if err := tx.Create(&mentions).Error; err != nil {
+			return nil, nil, err
+		}
+	}
+
+	previous := make(map[uuid.UUID]struct{}, len(existing))
+	for _, userID := range existing {
+		previous[userID] = struct{}{}
+	}
+
+	var mentioned, added []uuid.UUID
+	seen := make(map[uuid.UUID]struct{}, len(mentions))
+	for _, mention := range mentions {
+		if _, exists := seen[mention.UserID]; exists {
+			continue
+		}
+		seen[mention.UserID] = struct{}{}
+
+		mentioned = append(mentioned, mention.UserID)
+		if _, exists := previous[mention.UserID]; !exists {
+			added = append(added, mention.UserID)
+		}
+	}
+
+	return mentioned, added, nil
+}
+
+func (s *postsStore) GetMentionsForPosts(postIDs []uuid.UUID) (map[uuid.UUID][]PostMention, error) {
+	mentions := make(map[uuid.UUID][]PostMention)
+	if len(postIDs) == 0 {
+		return mentions, nil
+	}
+
+	var rows []PostMention
+	if err := s.postgresDB.Preload("User").Where("post_id IN ?", postIDs).Order("start").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		mentions[row.PostID] = append(mentions[row.PostID], row)
+	}
+
+	return mentions, nil
+}
+
+func (s *postsStore) RecomputeCounters() error {
+	return s.postgresDB.Exec(RecomputeCountersSQL).Error
+}
+
+func (s *postsStore) GetDigestPosts(userID uuid.UUID, since time.Time, limit int) ([]Post, error) {
+	var posts []Post
+	err := s.postgresDB.Preload("Author").Scopes(listedPosts(userID)).
+		Where(followsAuthorSQL, userID).
+		Where("posts.published_at >= ?", since.Unix()).
+		Order("posts.likes_count + posts.comments_count DESC").Order("posts.published_at DESC").
+		Limit(limit).
+		Find(&posts).Error
+	return posts, err
+}
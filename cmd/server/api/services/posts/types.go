@@ -1,10 +1,20 @@
 package posts
 
-import "github.com/google/uuid"
+import (
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/utils"
+
+	"github.com/google/uuid"
+)
 
 type postCreateUpdatePayload struct {
-	Title   string `json:"title" validate:"required"`
-	Content string `json:"content" validate:"required"`
+	Title         string                   `json:"title" validate:"required"`
+	Content       string                   `json:"content" validate:"required"`
+	ContentFormat constants.ContentFormat  `json:"content_format" validate:"omitempty,oneof=plain markdown"`
+	Tags          *[]string                `json:"tags" validate:"omitempty,max=20,dive,required,max=65"`
+	Status        constants.PostStatus     `json:"status" validate:"omitempty,oneof=draft scheduled published archived"`
+	Visibility    constants.PostVisibility `json:"visibility" validate:"omitempty,oneof=public followers unlisted private"`
+	PublishAt     *int64                   `json:"publish_at"`
 }
 
 type postCreateUpdateResponseAuthor struct {
@@ -14,13 +24,70 @@ type postCreateUpdateResponseAuthor struct {
 	Email     string    `json:"email"`
 }
 
+type postMentionResponse struct {
+	UserID uuid.UUID `json:"user_id"`
+	Handle *string   `json:"handle"`
+	Start  int       `json:"start"`
+	End    int       `json:"end"`
+}
+
+type reactionsEvent struct {
+	PostID    uuid.UUID             `json:"post_id"`
+	Likes     int64                 `json:"likes"`
+	Dislikes  int64                 `json:"dislikes"`
+	Reactions []utils.ReactionCount `json:"reactions"`
+}
+
+type postViewerResponse struct {
+	Liked      bool                   `json:"liked"`
+	Disliked   bool                   `json:"disliked"`
+	Reaction   constants.ReactionType `json:"reaction,omitempty"`
+	Bookmarked bool                   `json:"bookmarked"`
+	Authored   bool                   `json:"authored"`
+}
+
 type postCreateUpdateResponse struct {
-	ID        uuid.UUID                      `json:"id"`
-	Author    postCreateUpdateResponseAuthor `json:"author"`
+	ID            uuid.UUID                      `json:"id"`
+	Author        postCreateUpdateResponseAuthor `json:"author"`
+	Title         string                         `json:"title"`
+	Content       string                         `json:"content"`
+	ContentFormat constants.ContentFormat        `json:"content_format"`
+	ContentHTML   string                         `json:"content_html"`
+	Tags          []string                       `json:"tags"`
+	Mentions      []postMentionResponse          `json:"mentions"`
+	Edited        bool                           `json:"edited"`
+	EditCount     int64                          `json:"edit_count"`
+	Status        constants.PostStatus           `json:"status"`
+	Visibility    constants.PostVisibility       `json:"visibility"`
+	PublishAt     *int64                         `json:"publish_at"`
+	PublishedAt   *int64                         `json:"published_at"`
+	Likes         int64                          `json:"likes"`
+	Dislikes      int64                          `json:"dislikes"`
+	Comments      int64                          `json:"comments"`
+	Reactions     []utils.ReactionCount          `json:"reactions"`
+	Viewer        *postViewerResponse            `json:"viewer,omitempty"`
+	CreatedAt     int64                          `json:"created_at"`
+	UpdatedAt     int64                          `json:"updated_at"`
+}
+
+type postReactionResponse struct {
+	User      postCreateUpdateResponseAuthor `json:"user"`
+	Type      constants.ReactionType         `json:"type"`
+	Emoji     string                         `json:"emoji"`
+	CreatedAt int64                          `json:"created_at"`
+}
+
+type postRevisionResponse struct {
+	Version   int64                          `json:"version"`
+	Editor    postCreateUpdateResponseAuthor `json:"editor"`
 	Title     string                         `json:"title"`
 	Content   string                         `json:"content"`
-	Likes     int64                          `json:"likes"`
-	Dislikes  int64                          `json:"dislikes"`
 	CreatedAt int64                          `json:"created_at"`
-	UpdatedAt int64                          `json:"updated_at"`
+}
+
+type postRevisionDiffResponse struct {
+	From    int64            `json:"from"`
+	To      int64            `json:"to"`
+	Title   []utils.DiffLine `json:"title"`
+	Content []utils.DiffLine `json:"content"`
 }
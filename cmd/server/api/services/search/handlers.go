@@ -0,0 +1,81 @@
+package search
+
+import (
+	"fmt"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/utils"
+	"net/http"
+	"strings"
+)
+
+type SearchHandler struct {
+	SearchStore SearchStore
+}
+
+func parseSearchTypes(r *http.Request) ([]constants.SearchType, error) {
+	param := r.URL.Query().Get("type")
+	if param == "" {
+		return []constants.SearchType{constants.SearchTypePost, constants.SearchTypeComment, constants.SearchTypeUser}, nil
+	}
+
+	seen := make(map[constants.SearchType]struct{})
+	var types []constants.SearchType
+	for _, part := range strings.Split(param, ",") {
+		searchType, ok := constants.SearchTypes[strings.ToLower(strings.TrimSpace(part))]
+		if !ok {
+			return nil, fmt.Errorf("invalid type: %s", part)
+		}
+		if _, exists := seen[searchType]; exists {
+			continue
+		}
+		seen[searchType] = struct{}{}
+		types = append(types, searchType)
+	}
+
+	return types, nil
+}
+
+func (h *SearchHandler) SearchHandler(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		utils.WriteError(w, http.StatusBadRequest, "query parameter q is required")
+		return
+	}
+
+	if len(query) > constants.MaxSearchQueryLength {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Sprintf("invalid q: must be at most %d characters", constants.MaxSearchQueryLength))
+		return
+	}
+
+	types, err := parseSearchTypes(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit := r.Context().Value(constants.LimitKey).(int)
+	offset := r.Context().Value(constants.OffsetKey).(int)
+
+	results, err := h.SearchStore.Search(query, types, limit+1, offset)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	results, pageInfo, err := utils.PaginateResults(r, results)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if utils.IncludeTotal(r) {
+		total, err := h.SearchStore.Count(query, types)
+		if err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		pageInfo.Total = &total
+	}
+
+	utils.WritePage(w, r, http.StatusOK, results, pageInfo)
+}
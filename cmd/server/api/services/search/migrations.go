@@ -0,0 +1,89 @@
+package search
+
+import (
+	"fmt"
+	"gopher-social-backend-server/internal/database"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/utils"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var SEARCH_LANGUAGE = utils.GetEnvAsString("SEARCH_LANGUAGE", constants.DefaultSearchLanguage)
+
+var languagePattern = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+var searchDocuments = map[string]string{
+	"posts":    `setweight(to_tsvector('%[1]s', coalesce(title, '')), 'A') || setweight(to_tsvector('%[1]s', coalesce(content, '')), 'B')`,
+	"comments": `to_tsvector('%[1]s', coalesce(content, ''))`,
+}
+
+func searchVectorSQL(table, language string) string {
+	return fmt.Sprintf(`
+		ALTER TABLE %[1]s ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (%[2]s) STORED;
+		CREATE INDEX IF NOT EXISTS idx_%[1]s_search_vector ON %[1]s USING GIN (search_vector);
+	`, table, fmt.Sprintf(searchDocuments[table], language))
+}
+
+var Migrations = []database.Migration{
+	{
+		ID:  "0001_search_posts_tsvector",
+		SQL: searchVectorSQL("posts", SEARCH_LANGUAGE),
+	},
+	{
+		ID:  "0002_search_comments_tsvector",
+		SQL: searchVectorSQL("comments", SEARCH_LANGUAGE),
+	},
+	{
+		ID: "0003_search_users_tsvector",
+		SQL: fmt.Sprintf(`
+			ALTER TABLE users ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+				to_tsvector('%[1]s', coalesce(first_name, '') || ' ' || coalesce(last_name, ''))
+			) STORED;
+			CREATE INDEX IF NOT EXISTS idx_users_search_vector ON users USING GIN (search_vector);
+		`, constants.UserSearchLanguage),
+	},
+}
+
+func ValidateLanguage(postgresDB *gorm.DB, language string) error {
+	if !languagePattern.MatchString(language) {
+		return fmt.Errorf("invalid text search configuration %q", language)
+	}
+
+	var exists bool
+	if err := postgresDB.Raw("SELECT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = ?)", language).Scan(&exists).Error; err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("unknown text search configuration %q", language)
+	}
+	return nil
+}
+
+func SyncLanguage(postgresDB *gorm.DB) error {
+	for _, table := range []string{"posts", "comments"} {
+		var definition string
+		if err := postgresDB.Raw(`
+			SELECT pg_get_expr(d.adbin, d.adrelid) FROM pg_attrdef d
+			JOIN pg_attribute a ON a.attrelid = d.adrelid AND a.attnum = d.adnum
+			WHERE d.adrelid = ?::regclass AND a.attname = 'search_vector'`, table).Scan(&definition).Error; err != nil {
+			return err
+		}
+		if strings.Contains(definition, "'"+SEARCH_LANGUAGE+"'::regconfig") {
+			continue
+		}
+
+		err := postgresDB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(fmt.Sprintf("ALTER TABLE %s DROP COLUMN IF EXISTS search_vector", table)).Error; err != nil {
+				return err
+			}
+			return tx.Exec(searchVectorSQL(table, SEARCH_LANGUAGE)).Error
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
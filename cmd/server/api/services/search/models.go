@@ -0,0 +1,17 @@
+package search
+
+import (
+	"gopher-social-backend-server/pkg/constants"
+
+	"github.com/google/uuid"
+)
+
+type SearchResult struct {
+	Type      constants.SearchType `json:"type"`
+	ID        uuid.UUID            `json:"id"`
+	PostID    *uuid.UUID           `json:"post_id,omitempty"`
+	Title     string               `json:"title,omitempty"`
+	Snippet   string               `json:"snippet"`
+	Rank      float64              `json:"rank"`
+	CreatedAt int64                `json:"created_at"`
+}
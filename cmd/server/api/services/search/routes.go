@@ -0,0 +1,11 @@
+package search
+
+import (
+	"gopher-social-backend-server/internal/middlewares"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterSearchRoutes(router chi.Router, handler *SearchHandler) {
+	router.With(middlewares.PaginationMiddleware).Get("/search", handler.SearchHandler)
+}
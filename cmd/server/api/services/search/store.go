@@ -0,0 +1,110 @@
+package search
+
+import (
+	"fmt"
+	"gopher-social-backend-server/pkg/constants"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=35, MinWords=15, MaxFragments=2, FragmentDelimiter=\" ... \""
+
+type SearchStore interface {
+	Search(query string, types []constants.SearchType, limit, offset int) ([]SearchResult, error)
+	Count(query string, types []constants.SearchType) (int64, error)
+}
+
+type searchStore struct {
+	postgresDB *gorm.DB
+}
+
+func NewSearchStore(postgresDB *gorm.DB) SearchStore {
+	return &searchStore{
+		postgresDB: postgresDB,
+	}
+}
+
+func escapeHTML(column string) string {
+	return fmt.Sprintf("replace(replace(replace(%s, '&', '&amp;'), '<', '&lt;'), '>', '&gt;')", column)
+}
+
+func headline(language string) string {
+	return fmt.Sprintf("ts_headline('%[1]s', %[2]s, websearch_to_tsquery('%[1]s', ?), '%[3]s')", language, escapeHTML("page.document"), headlineOptions)
+}
+
+func searchQuery(query string, types []constants.SearchType) (string, []interface{}) {
+	var selects []string
+	var args []interface{}
+
+	for _, searchType := range types {
+		switch searchType {
+		case constants.SearchTypePost:
+			selects = append(selects, fmt.Sprintf(`
+				SELECT 'post' AS type, p.id, NULL::uuid AS post_id, p.title,
+					p.content AS document, ts_rank(p.search_vector, query) AS rank, p.created_at
+				FROM posts p, websearch_to_tsquery('%s', ?) query
+				WHERE p.search_vector @@ query AND p.deleted_at IS NULL AND p.status = 'published' AND p.visibility = 'public'`,
+				SEARCH_LANGUAGE))
+
+		case constants.SearchTypeComment:
+			selects = append(selects, fmt.Sprintf(`
+				SELECT 'comment' AS type, c.id, c.post_id, '' AS title,
+					c.content AS document, ts_rank(c.search_vector, query) AS rank, c.created_at
+				FROM comments c, websearch_to_tsquery('%s', ?) query
+				WHERE c.search_vector @@ query AND c.deleted_at IS NULL
+					AND EXISTS (SELECT 1 FROM posts WHERE posts.id = c.post_id AND posts.deleted_at IS NULL AND posts.status = 'published' AND posts.visibility = 'public')`,
+				SEARCH_LANGUAGE))
+
+		case constants.SearchTypeUser:
+			selects = append(selects, fmt.Sprintf(`
+				SELECT 'user' AS type, u.id, NULL::uuid AS post_id, '' AS title,
+					u.first_name || ' ' || u.last_name AS document, ts_rank(u.search_vector, query) AS rank, u.created_at
+				FROM users u, websearch_to_tsquery('%s', ?) query
+				WHERE u.search_vector @@ query AND u.is_activated`,
+				constants.UserSearchLanguage))
+
+		default:
+			continue
+		}
+
+		args = append(args, query)
+	}
+
+	return strings.Join(selects, " UNION ALL "), args
+}
+
+func (s *searchStore) Search(query string, types []constants.SearchType, limit, offset int) ([]SearchResult, error) {
+	results := make([]SearchResult, 0)
+
+	union, args := searchQuery(query, types)
+	if union == "" {
+		return results, nil
+	}
+
+	sql := fmt.Sprintf(`
+		SELECT page.type, page.id, page.post_id, page.title, page.rank, page.created_at,
+			CASE page.type WHEN 'user' THEN %s ELSE %s END AS snippet
+		FROM (SELECT * FROM (%s) results ORDER BY rank DESC, created_at DESC, id LIMIT ? OFFSET ?) page
+		ORDER BY page.rank DESC, page.created_at DESC, page.id`,
+		headline(constants.UserSearchLanguage), headline(SEARCH_LANGUAGE), union)
+	args = append([]interface{}{query, query}, append(args, limit, offset)...)
+
+	if err := s.postgresDB.Raw(sql, args...).Scan(&results).Error; err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (s *searchStore) Count(query string, types []constants.SearchType) (int64, error) {
+	var count int64
+
+	union, args := searchQuery(query, types)
+	if union == "" {
+		return count, nil
+	}
+
+	err := s.postgresDB.Raw(fmt.Sprintf("SELECT COUNT(*) FROM (%s) results", union), args...).Scan(&count).Error
+	return count, err
+}
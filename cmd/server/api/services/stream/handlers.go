@@ -0,0 +1,348 @@
+package stream
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gopher-social-backend-server/cmd/server/api/services/authentication"
+	"gopher-social-backend-server/cmd/server/api/services/posts"
+	"gopher-social-backend-server/internal/pubsub"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/ratelimiter"
+	"gopher-social-backend-server/pkg/utils"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+type StreamHandler struct {
+	StreamStore         StreamStore
+	PostsStore          posts.PostsStore
+	AuthenticationStore authentication.AuthenticationStore
+	Hub                 *pubsub.Hub
+}
+
+var STREAM_HEARTBEAT = utils.GetEnvAsDuration("STREAM_HEARTBEAT", constants.DefaultStreamHeartbeat)
+var STREAM_WRITE_TIMEOUT = utils.GetEnvAsDuration("STREAM_WRITE_TIMEOUT", constants.DefaultStreamWriteTimeout)
+var STREAM_RETRY = utils.GetEnvAsDuration("STREAM_RETRY", constants.DefaultStreamRetry)
+var STREAM_BUFFER_SIZE = utils.GetEnvAsInt("STREAM_BUFFER_SIZE", constants.DefaultStreamBufferSize)
+
+var WEBSOCKET_PING_INTERVAL = utils.GetEnvAsDuration("WEBSOCKET_PING_INTERVAL", constants.DefaultWebSocketPingInterval)
+var WEBSOCKET_FRAME_INTERVAL = utils.GetEnvAsDuration("WEBSOCKET_FRAME_INTERVAL", constants.DefaultWebSocketFrameInterval)
+var WEBSOCKET_TYPING_INTERVAL = utils.GetEnvAsDuration("WEBSOCKET_TYPING_INTERVAL", constants.DefaultWebSocketTypingInterval)
+var WEBSOCKET_ALLOWED_ORIGINS = strings.FieldsFunc(utils.GetEnvAsString("WEBSOCKET_ALLOWED_ORIGINS", ""), func(r rune) bool { return r == ',' })
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     checkOrigin,
+}
+
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || slices.Contains(WEBSOCKET_ALLOWED_ORIGINS, origin) {
+		return true
+	}
+
+	originURL, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(originURL.Host, r.Host)
+}
+
+func formatEvent(event pubsub.Event) string {
+	return fmt.Sprintf("id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, event.Data)
+}
+
+func parseLastEventID(r *http.Request) (int64, error) {
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = r.URL.Query().Get("last_event_id")
+	}
+	if lastEventID == "" {
+		return 0, nil
+	}
+
+	eventID, err := strconv.ParseInt(lastEventID, 10, 64)
+	if err != nil || eventID < 0 {
+		return 0, errors.New("invalid Last-Event-ID: must be a non-negative integer")
+	}
+	return eventID, nil
+}
+
+func (h *StreamHandler) parseTopics(w http.ResponseWriter, r *http.Request, viewerID uuid.UUID) ([]string, bool) {
+	topics := make([]string, 0)
+	if viewerID != uuid.Nil {
+		topics = append(topics, pubsub.UserTopic(viewerID))
+	}
+
+	postsParam := r.URL.Query().Get("posts")
+	if postsParam == "" {
+		return topics, true
+	}
+
+	postIDs := strings.Split(postsParam, ",")
+	if len(postIDs) > constants.MaxStreamPosts {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Sprintf("invalid posts: at most %d posts can be subscribed to", constants.MaxStreamPosts))
+		return nil, false
+	}
+
+	for _, postIDParam := range postIDs {
+		postID, err := uuid.Parse(strings.TrimSpace(postIDParam))
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, fmt.Sprintf("invalid post ID: %s", postIDParam))
+			return nil, false
+		}
+
+		if _, err := h.PostsStore.GetVisiblePostByID(postID, viewerID); err != nil {
+			utils.WriteError(w, http.StatusNotFound, "post not found")
+			return nil, false
+		}
+
+		topics = append(topics, pubsub.PostTopic(postID))
+	}
+
+	return topics, true
+}
+
+func (h *StreamHandler) StreamHandler(w http.ResponseWriter, r *http.Request) {
+	topics, ok := h.parseTopics(w, r, utils.ViewerID(r))
+	if !ok {
+		return
+	}
+
+	if len(topics) == 0 {
+		utils.WriteError(w, http.StatusBadRequest, "nothing to stream: sign in or subscribe to posts")
+		return
+	}
+
+	lastEventID, err := parseLastEventID(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	subscriber := h.Hub.Subscribe(topics, STREAM_BUFFER_SIZE)
+	defer h.Hub.Unsubscribe(subscriber)
+
+	controller := http.NewResponseController(w)
+	write := func(chunk string) error {
+		if err := controller.SetWriteDeadline(time.Now().Add(STREAM_WRITE_TIMEOUT)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		if _, err := io.WriteString(w, chunk); err != nil {
+			return err
+		}
+		return controller.Flush()
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if err := write(fmt.Sprintf("retry: %d\n\n", STREAM_RETRY.Milliseconds())); err != nil {
+		return
+	}
+
+	replayed := make(map[int64]struct{})
+	if lastEventID > 0 {
+		events, err := h.StreamStore.GetEventsSince(topics, lastEventID, constants.StreamReplayLimit)
+		if err != nil {
+			return
+		}
+
+		for _, event := range events {
+			replayed[event.ID] = struct{}{}
+			if err := write(formatEvent(event)); err != nil {
+				return
+			}
+		}
+	}
+
+	heartbeat := time.NewTicker(STREAM_HEARTBEAT)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-subscriber.Done():
+			return
+		case <-heartbeat.C:
+			if err := write(": heartbeat\n\n"); err != nil {
+				return
+			}
+		case event := <-subscriber.Events():
+			if _, ok := replayed[event.ID]; ok {
+				continue
+			}
+			if err := write(formatEvent(event)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func errorMessage(message string) serverMessage {
+	return serverMessage{Type: string(constants.WebSocketError), Message: message}
+}
+
+func sendReply(replies chan<- serverMessage, reply serverMessage) {
+	select {
+	case replies <- reply:
+	default:
+	}
+}
+
+func (h *StreamHandler) handleClientMessage(user *authentication.User, subscriber *pubsub.Subscriber, message clientMessage, typingLimiter *ratelimiter.RateLimiter) serverMessage {
+	postTopic := pubsub.PostTopic(message.PostID)
+
+	switch message.Type {
+	case constants.WebSocketPing:
+		return serverMessage{Type: string(constants.WebSocketPong)}
+
+	case constants.WebSocketSubscribe:
+		if h.Hub.TopicCount(subscriber) >= 1+2*constants.MaxStreamPosts {
+			return errorMessage(fmt.Sprintf("at most %d posts can be subscribed to", constants.MaxStreamPosts))
+		}
+		if _, err := h.PostsStore.GetVisiblePostByID(message.PostID, user.ID); err != nil {
+			return errorMessage("post not found")
+		}
+		h.Hub.AddTopics(subscriber, postTopic, pubsub.TypingTopic(message.PostID))
+		return serverMessage{Type: string(constants.WebSocketSubscribed), PostID: &message.PostID}
+
+	case constants.WebSocketUnsubscribe:
+		h.Hub.RemoveTopics(subscriber, postTopic, pubsub.TypingTopic(message.PostID))
+		return serverMessage{Type: string(constants.WebSocketUnsubscribed), PostID: &message.PostID}
+
+	case constants.WebSocketTyping:
+		if !h.Hub.HasTopic(subscriber, postTopic) {
+			return errorMessage("not subscribed to this post")
+		}
+		if allow, _ := typingLimiter.Allow(message.PostID.String()); !allow {
+			return serverMessage{}
+		}
+		if err := h.StreamStore.AnnounceEvent(pubsub.TypingTopic(message.PostID), constants.StreamEventTyping, typingEvent{
+			PostID:    message.PostID,
+			UserID:    user.ID,
+			Handle:    user.Handle,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+		}); err != nil {
+			return errorMessage("failed to send typing indicator")
+		}
+		return serverMessage{}
+	}
+
+	return errorMessage(fmt.Sprintf("unknown message type: %s", message.Type))
+}
+
+func (h *StreamHandler) readMessages(conn *websocket.Conn, user *authentication.User, subscriber *pubsub.Subscriber, replies chan<- serverMessage, done chan<- struct{}) {
+	defer close(done)
+
+	pongWait := 2 * WEBSOCKET_PING_INTERVAL
+	conn.SetReadLimit(constants.MaxWebSocketMessageSize)
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	frameLimiter := ratelimiter.NewRateLimiter(WEBSOCKET_FRAME_INTERVAL)
+	typingLimiter := ratelimiter.NewRateLimiter(WEBSOCKET_TYPING_INTERVAL)
+
+	for {
+		_, payload, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(pongWait))
+
+		if allow, _ := frameLimiter.Allow(user.ID.String()); !allow {
+			sendReply(replies, errorMessage("rate limit exceeded"))
+			continue
+		}
+
+		var message clientMessage
+		if err := json.Unmarshal(payload, &message); err != nil {
+			sendReply(replies, errorMessage("invalid message: "+err.Error()))
+			continue
+		}
+
+		if reply := h.handleClientMessage(user, subscriber, message, typingLimiter); reply.Type != "" {
+			sendReply(replies, reply)
+		}
+	}
+}
+
+func (h *StreamHandler) WebSocketHandler(w http.ResponseWriter, r *http.Request) {
+	user, err := h.AuthenticationStore.GetUserByID(utils.ViewerID(r).String())
+	if err != nil {
+		utils.WriteError(w, http.StatusUnauthorized, "user not found")
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	subscriber := h.Hub.Subscribe([]string{pubsub.UserTopic(user.ID)}, STREAM_BUFFER_SIZE)
+	defer h.Hub.Unsubscribe(subscriber)
+
+	replies := make(chan serverMessage, constants.WebSocketReplyBuffer)
+	done := make(chan struct{})
+	go h.readMessages(conn, user, subscriber, replies, done)
+
+	write := func(messageType int, data []byte) error {
+		if err := conn.SetWriteDeadline(time.Now().Add(STREAM_WRITE_TIMEOUT)); err != nil {
+			return err
+		}
+		return conn.WriteMessage(messageType, data)
+	}
+	writeJSON := func(message serverMessage) error {
+		data, err := json.Marshal(message)
+		if err != nil {
+			return err
+		}
+		return write(websocket.TextMessage, data)
+	}
+
+	ping := time.NewTicker(WEBSOCKET_PING_INTERVAL)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-subscriber.Done():
+			write(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "client is too slow"))
+			return
+		case <-ping.C:
+			if err := write(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case reply := <-replies:
+			if err := writeJSON(reply); err != nil {
+				return
+			}
+		case event := <-subscriber.Events():
+			if event.Type == constants.StreamEventTyping {
+				var typing typingEvent
+				if err := json.Unmarshal([]byte(event.Data), &typing); err == nil && typing.UserID == user.ID {
+					continue
+				}
+			}
+			if err := writeJSON(serverMessage{Type: string(event.Type), ID: event.ID, Data: json.RawMessage(event.Data)}); err != nil {
+				return
+			}
+		}
+	}
+}
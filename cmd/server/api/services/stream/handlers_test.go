@@ -0,0 +1,306 @@
+package stream
+
+import (
+	"encoding/json"
+	"gopher-social-backend-server/cmd/server/api/services/authentication"
+	"gopher-social-backend-server/cmd/server/api/services/posts"
+	"gopher-social-backend-server/internal/pubsub"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/utils"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"gorm.io/gorm"
+)
+
+type fakeStreamStore struct {
+	StreamStore
+	hub    *pubsub.Hub
+	mu     sync.Mutex
+	nextID int64
+}
+
+// AnnounceEvent broadcasts straight to the hub instead of going through
+// Postgres NOTIFY.
+func (s *fakeStreamStore) AnnounceEvent(topic string, eventType constants.StreamEventType, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.nextID++
+	event := pubsub.Event{ID: s.nextID, Topic: topic, Type: eventType, Data: string(payload)}
+	s.mu.Unlock()
+
+	s.hub.Broadcast(event)
+	return nil
+}
+
+type fakePostsStore struct {
+	posts.PostsStore
+	visible map[uuid.UUID]bool
+}
+
+func (s *fakePostsStore) GetVisiblePostByID(postID, viewerID uuid.UUID) (*posts.Post, error) {
+	if !s.visible[postID] {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &posts.Post{ID: postID}, nil
+}
+
+type fakeAuthenticationStore struct {
+	authentication.AuthenticationStore
+	users map[string]*authentication.User
+}
+
+func (s *fakeAuthenticationStore) GetUserByID(id string) (*authentication.User, error) {
+	user, ok := s.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return user, nil
+}
+
+type wsTest struct {
+	server      *httptest.Server
+	streamStore *fakeStreamStore
+	users       map[string]*authentication.User
+	postID      uuid.UUID
+}
+
+func newWSTest(t *testing.T, frameInterval time.Duration) *wsTest {
+	t.Helper()
+
+	previousFrameInterval, previousTypingInterval := WEBSOCKET_FRAME_INTERVAL, WEBSOCKET_TYPING_INTERVAL
+	WEBSOCKET_FRAME_INTERVAL, WEBSOCKET_TYPING_INTERVAL = frameInterval, 0
+	t.Cleanup(func() {
+		WEBSOCKET_FRAME_INTERVAL, WEBSOCKET_TYPING_INTERVAL = previousFrameInterval, previousTypingInterval
+	})
+
+	hub := pubsub.NewHub()
+	test := &wsTest{
+		streamStore: &fakeStreamStore{hub: hub},
+		users:       make(map[string]*authentication.User),
+		postID:      uuid.New(),
+	}
+
+	authenticationStore := &fakeAuthenticationStore{users: make(map[string]*authentication.User)}
+	for _, name := range []string{"alice", "bob", "carol"} {
+		handle := name
+		user := &authentication.User{ID: uuid.New(), FirstName: name, Handle: &handle}
+		test.users[name] = user
+		authenticationStore.users[user.ID.String()] = user
+	}
+
+	handler := &StreamHandler{
+		StreamStore:         test.streamStore,
+		PostsStore:          &fakePostsStore{visible: map[uuid.UUID]bool{test.postID: true}},
+		AuthenticationStore: authenticationStore,
+		Hub:                 hub,
+	}
+
+	router := chi.NewRouter()
+	RegisterStreamRoutes(router, handler)
+	test.server = httptest.NewServer(router)
+	t.Cleanup(test.server.Close)
+
+	return test
+}
+
+func (test *wsTest) dial(t *testing.T, userID string) (*websocket.Conn, *http.Response, error) {
+	t.Helper()
+
+	header := http.Header{}
+	if userID != "" {
+		token, _ := utils.GenerateAccessToken(userID)
+		header.Set("Cookie", "AuthToken="+token)
+	}
+
+	conn, resp, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(test.server.URL, "http")+"/ws", header)
+	if err == nil {
+		t.Cleanup(func() { conn.Close() })
+	}
+	return conn, resp, err
+}
+
+func (test *wsTest) connect(t *testing.T, name string) *websocket.Conn {
+	t.Helper()
+
+	conn, _, err := test.dial(t, test.users[name].ID.String())
+	if err != nil {
+		t.Fatalf("%s could not connect: %v", name, err)
+	}
+	return conn
+}
+
+func send(t *testing.T, conn *websocket.Conn, message clientMessage) {
+	t.Helper()
+
+	if err := conn.WriteJSON(message); err != nil {
+		t.Fatalf("could not send %s: %v", message.Type, err)
+	}
+}
+
+func receive(t *testing.T, conn *websocket.Conn) serverMessage {
+	t.Helper()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var message serverMessage
+	if err := conn.ReadJSON(&message); err != nil {
+		t.Fatalf("could not read message: %v", err)
+	}
+	return message
+}
+
+func expectType(t *testing.T, message serverMessage, want string) {
+	t.Helper()
+
+	if message.Type != want {
+		t.Fatalf("expected a %q message, got %+v", want, message)
+	}
+}
+
+func (test *wsTest) subscribe(t *testing.T, conn *websocket.Conn) {
+	t.Helper()
+
+	send(t, conn, clientMessage{Type: constants.WebSocketSubscribe, PostID: test.postID})
+	reply := receive(t, conn)
+	expectType(t, reply, string(constants.WebSocketSubscribed))
+	if reply.PostID == nil || *reply.PostID != test.postID {
+		t.Fatalf("subscribed to the wrong post: %+v", reply)
+	}
+}
+
+// expectQuiet checks that nothing is queued for conn ahead of a pong.
+func expectQuiet(t *testing.T, conn *websocket.Conn) {
+	t.Helper()
+
+	send(t, conn, clientMessage{Type: constants.WebSocketPing})
+	expectType(t, receive(t, conn), string(constants.WebSocketPong))
+}
+
+func TestWebSocketRequiresAuthentication(t *testing.T) {
+	test := newWSTest(t, 0)
+
+	tests := []struct {
+		name   string
+		userID string
+	}{
+		{name: "no cookie", userID: ""},
+		{name: "unknown user", userID: uuid.NewString()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, resp, err := test.dial(t, tt.userID)
+			if err == nil {
+				t.Fatal("expected the handshake to fail")
+			}
+			if resp == nil || resp.StatusCode != http.StatusUnauthorized {
+				t.Fatalf("expected 401, got %v", resp)
+			}
+		})
+	}
+
+	conn := test.connect(t, "alice")
+	expectQuiet(t, conn)
+}
+
+func TestWebSocketSubscribeRejectsHiddenPosts(t *testing.T) {
+	test := newWSTest(t, 0)
+	conn := test.connect(t, "alice")
+
+	send(t, conn, clientMessage{Type: constants.WebSocketSubscribe, PostID: uuid.New()})
+	reply := receive(t, conn)
+	expectType(t, reply, string(constants.WebSocketError))
+	if reply.Message != "post not found" {
+		t.Fatalf("unexpected error message: %q", reply.Message)
+	}
+}
+
+func TestWebSocketFansOutCommentsToSubscribers(t *testing.T) {
+	test := newWSTest(t, 0)
+
+	alice := test.connect(t, "alice")
+	bob := test.connect(t, "bob")
+	carol := test.connect(t, "carol")
+	test.subscribe(t, alice)
+	test.subscribe(t, bob)
+
+	commentID := uuid.New()
+	if err := test.streamStore.AnnounceEvent(pubsub.PostTopic(test.postID), constants.StreamEventCommentCreated, map[string]uuid.UUID{"comment_id": commentID}); err != nil {
+		t.Fatalf("could not announce comment: %v", err)
+	}
+
+	for name, conn := range map[string]*websocket.Conn{"alice": alice, "bob": bob} {
+		event := receive(t, conn)
+		expectType(t, event, string(constants.StreamEventCommentCreated))
+
+		var data map[string]uuid.UUID
+		if err := json.Unmarshal(event.Data, &data); err != nil || data["comment_id"] != commentID {
+			t.Fatalf("%s received the wrong comment: %s", name, event.Data)
+		}
+	}
+
+	expectQuiet(t, carol)
+
+	send(t, bob, clientMessage{Type: constants.WebSocketUnsubscribe, PostID: test.postID})
+	expectType(t, receive(t, bob), string(constants.WebSocketUnsubscribed))
+
+	if err := test.streamStore.AnnounceEvent(pubsub.PostTopic(test.postID), constants.StreamEventCommentCreated, map[string]uuid.UUID{"comment_id": uuid.New()}); err != nil {
+		t.Fatalf("could not announce comment: %v", err)
+	}
+	expectType(t, receive(t, alice), string(constants.StreamEventCommentCreated))
+	expectQuiet(t, bob)
+}
+
+func TestWebSocketTypingPresence(t *testing.T) {
+	test := newWSTest(t, 0)
+
+	alice := test.connect(t, "alice")
+	bob := test.connect(t, "bob")
+
+	send(t, alice, clientMessage{Type: constants.WebSocketTyping, PostID: test.postID})
+	expectType(t, receive(t, alice), string(constants.WebSocketError))
+
+	test.subscribe(t, alice)
+	test.subscribe(t, bob)
+
+	send(t, alice, clientMessage{Type: constants.WebSocketTyping, PostID: test.postID})
+
+	event := receive(t, bob)
+	expectType(t, event, string(constants.StreamEventTyping))
+
+	var typing typingEvent
+	if err := json.Unmarshal(event.Data, &typing); err != nil {
+		t.Fatalf("could not decode typing event: %v", err)
+	}
+	if typing.UserID != test.users["alice"].ID || typing.PostID != test.postID {
+		t.Fatalf("unexpected typing event: %+v", typing)
+	}
+
+	expectQuiet(t, alice)
+}
+
+func TestWebSocketRateLimitsFrames(t *testing.T) {
+	test := newWSTest(t, time.Hour)
+	conn := test.connect(t, "alice")
+
+	send(t, conn, clientMessage{Type: constants.WebSocketPing})
+	expectType(t, receive(t, conn), string(constants.WebSocketPong))
+
+	send(t, conn, clientMessage{Type: constants.WebSocketPing})
+	reply := receive(t, conn)
+	expectType(t, reply, string(constants.WebSocketError))
+	if reply.Message != "rate limit exceeded" {
+		t.Fatalf("unexpected error message: %q", reply.Message)
+	}
+}
@@ -0,0 +1,12 @@
+package stream
+
+import (
+	"gopher-social-backend-server/internal/middlewares"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterStreamRoutes(router chi.Router, handler *StreamHandler) {
+	router.With(middlewares.OptionalAuthMiddleware).Get("/stream", handler.StreamHandler)
+	router.With(middlewares.AuthMiddleware).Get("/ws", handler.WebSocketHandler)
+}
@@ -0,0 +1,71 @@
+package stream
+
+import (
+	"gopher-social-backend-server/internal/pubsub"
+	"gopher-social-backend-server/pkg/constants"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type StreamStore interface {
+	GetEvent(eventID int64) (*pubsub.Event, error)
+	GetEventsAfter(afterID int64, limit int) ([]pubsub.Event, error)
+	GetEventsSince(topics []string, afterID int64, limit int) ([]pubsub.Event, error)
+	GetLatestEventID() (int64, error)
+	PurgeEvents(before time.Time) (int64, error)
+	AnnounceEvent(topic string, eventType constants.StreamEventType, data any) error
+}
+
+type streamStore struct {
+	postgresDB *gorm.DB
+}
+
+func NewStreamStore(postgresDB *gorm.DB) StreamStore {
+	return &streamStore{
+		postgresDB: postgresDB,
+	}
+}
+
+func (s *streamStore) GetEvent(eventID int64) (*pubsub.Event, error) {
+	var event pubsub.Event
+	if err := s.postgresDB.First(&event, "id = ?", eventID).Error; err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (s *streamStore) GetEventsAfter(afterID int64, limit int) ([]pubsub.Event, error) {
+	var events []pubsub.Event
+
+	if err := s.postgresDB.Where("id > ?", afterID).Order("id").Limit(limit).Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (s *streamStore) GetEventsSince(topics []string, afterID int64, limit int) ([]pubsub.Event, error) {
+	var events []pubsub.Event
+
+	if err := s.postgresDB.Where("topic IN ? AND id > ?", topics, afterID).Order("id").Limit(limit).Find(&events).Error; err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (s *streamStore) GetLatestEventID() (int64, error) {
+	var eventID int64
+	err := s.postgresDB.Model(&pubsub.Event{}).Select("COALESCE(MAX(id), 0)").Scan(&eventID).Error
+	return eventID, err
+}
+
+func (s *streamStore) PurgeEvents(before time.Time) (int64, error) {
+	result := s.postgresDB.Where("created_at < ?", before.Unix()).Delete(&pubsub.Event{})
+	return result.RowsAffected, result.Error
+}
+
+func (s *streamStore) AnnounceEvent(topic string, eventType constants.StreamEventType, data any) error {
+	return pubsub.Announce(s.postgresDB, topic, eventType, data)
+}
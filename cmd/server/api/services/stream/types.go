@@ -0,0 +1,29 @@
+package stream
+
+import (
+	"encoding/json"
+	"gopher-social-backend-server/pkg/constants"
+
+	"github.com/google/uuid"
+)
+
+type clientMessage struct {
+	Type   constants.WebSocketMessageType `json:"type"`
+	PostID uuid.UUID                      `json:"post_id"`
+}
+
+type serverMessage struct {
+	Type    string          `json:"type"`
+	ID      int64           `json:"id,omitempty"`
+	PostID  *uuid.UUID      `json:"post_id,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+	Message string          `json:"message,omitempty"`
+}
+
+type typingEvent struct {
+	PostID    uuid.UUID `json:"post_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Handle    *string   `json:"handle"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+}
@@ -0,0 +1,82 @@
+package tags
+
+import (
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/utils"
+	"net/http"
+	"time"
+)
+
+var TRENDING_TAGS_WINDOW = utils.GetEnvAsDuration("TRENDING_TAGS_WINDOW", constants.DefaultTrendingWindow)
+
+type TagsHandler struct {
+	TagsStore TagsStore
+}
+
+func (h *TagsHandler) writeTagsPage(w http.ResponseWriter, r *http.Request, tags []TagStats, count func() (int64, error)) {
+	tags, pageInfo, err := utils.PaginateResults(r, tags)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if utils.IncludeTotal(r) {
+		total, err := count()
+		if err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		pageInfo.Total = &total
+	}
+
+	utils.WritePage(w, r, http.StatusOK, tags, pageInfo)
+}
+
+func (h *TagsHandler) AutocompleteTagsHandler(w http.ResponseWriter, r *http.Request) {
+	prefix := utils.NormalizeTag(r.URL.Query().Get("q"))
+	if prefix != "" && !utils.IsValidTag(prefix) {
+		utils.WriteError(w, http.StatusBadRequest, "invalid q: tags may only contain letters, numbers and underscores")
+		return
+	}
+
+	limit := r.Context().Value(constants.LimitKey).(int)
+	offset := r.Context().Value(constants.OffsetKey).(int)
+
+	tags, err := h.TagsStore.SearchTags(prefix, limit+1, offset)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.writeTagsPage(w, r, tags, func() (int64, error) {
+		return h.TagsStore.CountTags(prefix)
+	})
+}
+
+func (h *TagsHandler) GetTrendingTagsHandler(w http.ResponseWriter, r *http.Request) {
+	window := TRENDING_TAGS_WINDOW
+	if param := r.URL.Query().Get("window"); param != "" {
+		duration, err := time.ParseDuration(param)
+		maxWindow, _ := time.ParseDuration(constants.MaxTrendingWindow)
+		if err != nil || duration <= 0 || duration > maxWindow {
+			utils.WriteError(w, http.StatusBadRequest, "invalid window: must be a positive duration of at most "+constants.MaxTrendingWindow)
+			return
+		}
+		window = duration
+	}
+
+	limit := r.Context().Value(constants.LimitKey).(int)
+	offset := r.Context().Value(constants.OffsetKey).(int)
+
+	since := time.Now().Add(-window).Unix()
+
+	tags, err := h.TagsStore.GetTrendingTags(since, limit+1, offset)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.writeTagsPage(w, r, tags, func() (int64, error) {
+		return h.TagsStore.CountTrendingTags(since)
+	})
+}
@@ -0,0 +1,18 @@
+package tags
+
+import "gopher-social-backend-server/internal/database"
+
+var Migrations = []database.Migration{
+	{
+		ID: "0004_post_tags_post_fk",
+		SQL: `
+			DELETE FROM post_tags WHERE post_id NOT IN (SELECT id FROM posts);
+			ALTER TABLE post_tags ADD CONSTRAINT fk_post_tags_post
+				FOREIGN KEY (post_id) REFERENCES posts(id) ON UPDATE CASCADE ON DELETE CASCADE;
+		`,
+	},
+	{
+		ID:  "0005_tags_name_prefix_index",
+		SQL: `CREATE INDEX IF NOT EXISTS idx_tags_name_prefix ON tags (name varchar_pattern_ops);`,
+	},
+}
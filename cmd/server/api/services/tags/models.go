@@ -0,0 +1,22 @@
+package tags
+
+import "github.com/google/uuid"
+
+type Tag struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Name      string    `json:"name" gorm:"type:varchar(64);uniqueIndex;not null"`
+	CreatedAt int64     `json:"created_at" gorm:"autoCreateTime"`
+}
+
+type PostTag struct {
+	PostID    uuid.UUID `json:"post_id" gorm:"type:uuid;primaryKey"`
+	TagID     uuid.UUID `json:"tag_id" gorm:"type:uuid;primaryKey;index"`
+	Tag       Tag       `json:"tag" gorm:"foreignKey:TagID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	Explicit  bool      `json:"explicit" gorm:"not null;default:false"`
+	CreatedAt int64     `json:"created_at" gorm:"autoCreateTime;index"`
+}
+
+type TagStats struct {
+	Name       string `json:"name"`
+	PostsCount int64  `json:"posts_count"`
+}
@@ -0,0 +1,12 @@
+package tags
+
+import (
+	"gopher-social-backend-server/internal/middlewares"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterTagsRoutes(router chi.Router, handler *TagsHandler) {
+	router.With(middlewares.PaginationMiddleware).Get("/tags", handler.AutocompleteTagsHandler)
+	router.With(middlewares.PaginationMiddleware).Get("/tags/trending", handler.GetTrendingTagsHandler)
+}
@@ -0,0 +1,180 @@
+package tags
+
+import (
+	"fmt"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/utils"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrTooManyTags = fmt.Errorf("too many tags: a post may have at most %d tags", constants.MaxTagsPerPost)
+
+type TagsStore interface {
+	GetTagsForPosts(postIDs []uuid.UUID) (map[uuid.UUID][]string, error)
+	SearchTags(prefix string, limit, offset int) ([]TagStats, error)
+	CountTags(prefix string) (int64, error)
+	GetTrendingTags(since int64, limit, offset int) ([]TagStats, error)
+	CountTrendingTags(since int64) (int64, error)
+}
+
+type tagsStore struct {
+	postgresDB *gorm.DB
+}
+
+func NewTagsStore(postgresDB *gorm.DB) TagsStore {
+	return &tagsStore{
+		postgresDB: postgresDB,
+	}
+}
+
+func SetPostTags(tx *gorm.DB, postID uuid.UUID, hashtags []string, explicitTags *[]string) error {
+	var explicit []string
+	if explicitTags != nil {
+		explicit = utils.MergeTags(*explicitTags)
+	} else if err := tx.Table("post_tags").
+		Joins("JOIN tags ON tags.id = post_tags.tag_id").
+		Where("post_tags.post_id = ? AND post_tags.explicit", postID).
+		Pluck("tags.name", &explicit).Error; err != nil {
+		return err
+	}
+
+	names := utils.MergeTags(hashtags, explicit)
+	if len(names) > constants.MaxTagsPerPost {
+		return ErrTooManyTags
+	}
+
+	var tags []Tag
+	if len(names) > 0 {
+		tags = make([]Tag, 0, len(names))
+		for _, name := range names {
+			tags = append(tags, Tag{Name: name})
+		}
+
+		if err := tx.Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).Create(&tags).Error; err != nil {
+			return err
+		}
+
+		tags = nil
+		if err := tx.Select("id", "name").Where("name IN ?", names).Find(&tags).Error; err != nil {
+			return err
+		}
+	}
+
+	deleteQuery := tx.Where("post_id = ?", postID)
+	if len(tags) > 0 {
+		tagIDs := make([]uuid.UUID, 0, len(tags))
+		for _, tag := range tags {
+			tagIDs = append(tagIDs, tag.ID)
+		}
+		deleteQuery = deleteQuery.Where("tag_id NOT IN ?", tagIDs)
+	}
+	if err := deleteQuery.Delete(&PostTag{}).Error; err != nil {
+		return err
+	}
+
+	if len(tags) == 0 {
+		return nil
+	}
+
+	postTags := make([]PostTag, 0, len(tags))
+	for _, tag := range tags {
+		postTags = append(postTags, PostTag{PostID: postID, TagID: tag.ID, Explicit: slices.Contains(explicit, tag.Name)})
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "post_id"}, {Name: "tag_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"explicit"}),
+	}).Create(&postTags).Error
+}
+
+func (s *tagsStore) GetTagsForPosts(postIDs []uuid.UUID) (map[uuid.UUID][]string, error) {
+	tagsByPost := make(map[uuid.UUID][]string, len(postIDs))
+	for _, postID := range postIDs {
+		tagsByPost[postID] = make([]string, 0)
+	}
+
+	if len(postIDs) == 0 {
+		return tagsByPost, nil
+	}
+
+	var rows []struct {
+		PostID uuid.UUID
+		Name   string
+	}
+
+	if err := s.postgresDB.Table("post_tags").
+		Select("post_tags.post_id, tags.name").
+		Joins("JOIN tags ON tags.id = post_tags.tag_id").
+		Where("post_tags.post_id IN ?", postIDs).
+		Order("tags.name ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		tagsByPost[row.PostID] = append(tagsByPost[row.PostID], row.Name)
+	}
+
+	return tagsByPost, nil
+}
+
+func likePrefix(prefix string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(prefix) + "%"
+}
+
+func (s *tagsStore) SearchTags(prefix string, limit, offset int) ([]TagStats, error) {
+	stats := make([]TagStats, 0)
+
+	if err := s.postgresDB.Table("tags").
+		Select("tags.name, COUNT(posts.id) AS posts_count").
+		Joins("LEFT JOIN post_tags ON post_tags.tag_id = tags.id").
+		Joins("LEFT JOIN posts ON posts.id = post_tags.post_id AND posts.deleted_at IS NULL AND posts.status = 'published' AND posts.visibility = 'public'").
+		Where("tags.name LIKE ?", likePrefix(prefix)).
+		Group("tags.id, tags.name").
+		Order("posts_count DESC, tags.name ASC").
+		Limit(limit).Offset(offset).
+		Scan(&stats).Error; err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (s *tagsStore) CountTags(prefix string) (int64, error) {
+	var count int64
+	err := s.postgresDB.Model(&Tag{}).Where("name LIKE ?", likePrefix(prefix)).Count(&count).Error
+	return count, err
+}
+
+func (s *tagsStore) GetTrendingTags(since int64, limit, offset int) ([]TagStats, error) {
+	stats := make([]TagStats, 0)
+
+	if err := s.postgresDB.Table("post_tags").
+		Select("tags.name, COUNT(post_tags.post_id) AS posts_count").
+		Joins("JOIN tags ON tags.id = post_tags.tag_id").
+		Joins("JOIN posts ON posts.id = post_tags.post_id AND posts.deleted_at IS NULL AND posts.status = 'published' AND posts.visibility = 'public'").
+		Where("post_tags.created_at >= ?", since).
+		Group("tags.id, tags.name").
+		Order("posts_count DESC, MAX(post_tags.created_at) DESC, tags.name ASC").
+		Limit(limit).Offset(offset).
+		Scan(&stats).Error; err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+func (s *tagsStore) CountTrendingTags(since int64) (int64, error) {
+	var count int64
+	err := s.postgresDB.Model(&PostTag{}).
+		Joins("JOIN posts ON posts.id = post_tags.post_id AND posts.deleted_at IS NULL AND posts.status = 'published' AND posts.visibility = 'public'").
+		Where("post_tags.created_at >= ?", since).
+		Distinct("post_tags.tag_id").
+		Count(&count).Error
+	return count, err
+}
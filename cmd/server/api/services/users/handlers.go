@@ -0,0 +1,395 @@
+package users
+
+import (
+	"errors"
+	"gopher-social-backend-server/cmd/server/api/services/authentication"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/utils"
+	"html/template"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+type UsersHandler struct {
+	UsersStore          UsersStore
+	AuthenticationStore authentication.AuthenticationStore
+}
+
+var validate = validator.New()
+
+func newUserResponse(user authentication.User) userResponse {
+	return userResponse{
+		ID:        user.ID,
+		Handle:    user.Handle,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Email:     user.Email,
+	}
+}
+
+func authUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userID := utils.ViewerID(r)
+	if userID == uuid.Nil {
+		utils.WriteError(w, http.StatusUnauthorized, "user ID not found in context")
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+func (h *UsersHandler) parseUserID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(chi.URLParam(r, "userID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return uuid.Nil, false
+	}
+
+	if _, err := h.AuthenticationStore.GetUserByID(userID.String()); err != nil {
+		utils.WriteError(w, http.StatusNotFound, "user not found")
+		return uuid.Nil, false
+	}
+
+	return userID, true
+}
+
+func (h *UsersHandler) handleFollowRequest(w http.ResponseWriter, r *http.Request, follow bool) {
+	followeeID, ok := h.parseUserID(w, r)
+	if !ok {
+		return
+	}
+
+	followerID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if followerID == followeeID {
+		utils.WriteError(w, http.StatusBadRequest, "you cannot follow yourself")
+		return
+	}
+
+	var err error
+	if follow {
+		blocked, blockErr := h.UsersStore.IsBlocked(followerID, followeeID)
+		if blockErr != nil {
+			utils.WriteError(w, http.StatusInternalServerError, blockErr.Error())
+			return
+		}
+		if blocked {
+			utils.WriteError(w, http.StatusForbidden, "you cannot follow this user")
+			return
+		}
+
+		err = h.UsersStore.FollowUser(followerID, followeeID)
+	} else {
+		err = h.UsersStore.UnfollowUser(followerID, followeeID)
+	}
+
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrAlreadyFollowing), errors.Is(err, ErrNotFollowing):
+			utils.WriteError(w, http.StatusConflict, err.Error())
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusNoContent, nil)
+}
+
+func (h *UsersHandler) FollowUserHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleFollowRequest(w, r, true)
+}
+
+func (h *UsersHandler) UnfollowUserHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleFollowRequest(w, r, false)
+}
+
+func (h *UsersHandler) writeFollowsPage(w http.ResponseWriter, r *http.Request, follows []Follow, user func(Follow) authentication.User, count func() (int64, error)) {
+	follows, pageInfo, err := utils.PaginateResults(r, follows)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if utils.IncludeTotal(r) {
+		total, err := count()
+		if err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		pageInfo.Total = &total
+	}
+
+	followResponses := make([]followResponse, 0, len(follows))
+	for _, follow := range follows {
+		followResponses = append(followResponses, followResponse{
+			User:      newUserResponse(user(follow)),
+			CreatedAt: follow.CreatedAt,
+		})
+	}
+
+	utils.WritePage(w, r, http.StatusOK, followResponses, pageInfo)
+}
+
+func (h *UsersHandler) GetFollowersHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.parseUserID(w, r)
+	if !ok {
+		return
+	}
+
+	limit := r.Context().Value(constants.LimitKey).(int)
+	offset := r.Context().Value(constants.OffsetKey).(int)
+
+	follows, err := h.UsersStore.GetFollowers(userID, limit+1, offset)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.writeFollowsPage(w, r, follows, func(follow Follow) authentication.User { return follow.Follower }, func() (int64, error) {
+		return h.UsersStore.CountFollowers(userID)
+	})
+}
+
+func (h *UsersHandler) GetFollowingHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.parseUserID(w, r)
+	if !ok {
+		return
+	}
+
+	limit := r.Context().Value(constants.LimitKey).(int)
+	offset := r.Context().Value(constants.OffsetKey).(int)
+
+	follows, err := h.UsersStore.GetFollowing(userID, limit+1, offset)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	h.writeFollowsPage(w, r, follows, func(follow Follow) authentication.User { return follow.Followee }, func() (int64, error) {
+		return h.UsersStore.CountFollowing(userID)
+	})
+}
+
+func (h *UsersHandler) handleBlockRequest(w http.ResponseWriter, r *http.Request, block bool) {
+	blockedID, ok := h.parseUserID(w, r)
+	if !ok {
+		return
+	}
+
+	blockerID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	if blockerID == blockedID {
+		utils.WriteError(w, http.StatusBadRequest, "you cannot block yourself")
+		return
+	}
+
+	var err error
+	if block {
+		err = h.UsersStore.BlockUser(blockerID, blockedID)
+	} else {
+		err = h.UsersStore.UnblockUser(blockerID, blockedID)
+	}
+
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrAlreadyBlocked), errors.Is(err, ErrNotBlocked):
+			utils.WriteError(w, http.StatusConflict, err.Error())
+		default:
+			utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusNoContent, nil)
+}
+
+func (h *UsersHandler) BlockUserHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleBlockRequest(w, r, true)
+}
+
+func (h *UsersHandler) UnblockUserHandler(w http.ResponseWriter, r *http.Request) {
+	h.handleBlockRequest(w, r, false)
+}
+
+func (h *UsersHandler) GetBlockedHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	limit := r.Context().Value(constants.LimitKey).(int)
+	offset := r.Context().Value(constants.OffsetKey).(int)
+
+	blocks, err := h.UsersStore.GetBlocked(userID, limit+1, offset)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	blocks, pageInfo, err := utils.PaginateResults(r, blocks)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if utils.IncludeTotal(r) {
+		total, err := h.UsersStore.CountBlocked(userID)
+		if err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		pageInfo.Total = &total
+	}
+
+	blockResponses := make([]followResponse, 0, len(blocks))
+	for _, block := range blocks {
+		blockResponses = append(blockResponses, followResponse{
+			User:      newUserResponse(block.Blocked),
+			CreatedAt: block.CreatedAt,
+		})
+	}
+
+	utils.WritePage(w, r, http.StatusOK, blockResponses, pageInfo)
+}
+
+func (h *UsersHandler) GetSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.AuthenticationStore.GetUserByID(userID.String())
+	if err != nil {
+		utils.WriteError(w, http.StatusNotFound, "user not found")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, userSettingsResponse{
+		userResponse:    newUserResponse(*user),
+		MentionPolicy:   user.MentionPolicy,
+		Locale:          user.Locale,
+		DigestFrequency: user.DigestFrequency,
+	})
+}
+
+func (h *UsersHandler) UpdateSettingsHandler(w http.ResponseWriter, r *http.Request) {
+	userID, ok := authUserID(w, r)
+	if !ok {
+		return
+	}
+
+	var payload userSettingsPayload
+	if err := utils.ParseJSON(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := validate.Struct(payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := h.AuthenticationStore.GetUserByID(userID.String())
+	if err != nil {
+		utils.WriteError(w, http.StatusNotFound, "user not found")
+		return
+	}
+
+	if payload.Handle != nil {
+		handle := utils.NormalizeHandle(*payload.Handle)
+		if !utils.IsValidHandle(handle) {
+			utils.WriteError(w, http.StatusBadRequest, "invalid handle: handles may only contain letters, numbers and underscores")
+			return
+		}
+
+		if existing, err := h.AuthenticationStore.GetUserByHandle(handle); err == nil && existing.ID != user.ID {
+			utils.WriteError(w, http.StatusConflict, "handle is already taken")
+			return
+		}
+
+		user.Handle = &handle
+	}
+
+	if payload.MentionPolicy != "" {
+		user.MentionPolicy = payload.MentionPolicy
+	}
+
+	if payload.Locale != "" {
+		if !slices.Contains(constants.SupportedLocales, payload.Locale) {
+			utils.WriteError(w, http.StatusBadRequest, "invalid locale: must be one of "+strings.Join(constants.SupportedLocales, ", "))
+			return
+		}
+		user.Locale = payload.Locale
+	}
+
+	if payload.DigestFrequency != "" {
+		user.DigestFrequency = payload.DigestFrequency
+	}
+
+	if err := h.AuthenticationStore.UpdateUser(user); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to update settings")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, userSettingsResponse{
+		userResponse:    newUserResponse(*user),
+		MentionPolicy:   user.MentionPolicy,
+		Locale:          user.Locale,
+		DigestFrequency: user.DigestFrequency,
+	})
+}
+
+var unsubscribeConfirmation = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Unsubscribe from digests</title></head>
+<body>
+<p>Stop receiving digest emails?</p>
+<form method="post" action="?token={{.}}"><button type="submit">Unsubscribe</button></form>
+</body>
+</html>
+`))
+
+func (h *UsersHandler) UnsubscribeConfirmationHandler(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("token")
+	if _, err := utils.VerifyUnsubscribeToken(token); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "invalid unsubscribe token")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Referrer-Policy", "no-referrer")
+	w.WriteHeader(http.StatusOK)
+	unsubscribeConfirmation.Execute(w, token)
+}
+
+func (h *UsersHandler) UnsubscribeHandler(w http.ResponseWriter, r *http.Request) {
+	userID, err := utils.VerifyUnsubscribeToken(r.URL.Query().Get("token"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "invalid unsubscribe token")
+		return
+	}
+
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, "invalid unsubscribe token")
+		return
+	}
+
+	if err := h.UsersStore.DisableDigest(parsedUserID); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, "failed to unsubscribe")
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, unsubscribeResponse{DigestFrequency: constants.DigestOff})
+}
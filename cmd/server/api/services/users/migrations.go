@@ -0,0 +1,13 @@
+package users
+
+import "gopher-social-backend-server/internal/database"
+
+var Migrations = []database.Migration{
+	{
+		ID: "0014_backfill_user_handles",
+		SQL: `
+			UPDATE users SET handle = left(trim(both '_' from regexp_replace(lower(split_part(email, '@', 1)), '[^a-z0-9_]+', '_', 'g')), 20) || '_' || left(replace(id::text, '-', ''), 8)
+			WHERE handle IS NULL;
+		`,
+	},
+}
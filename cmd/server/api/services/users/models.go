@@ -0,0 +1,25 @@
+package users
+
+import (
+	"gopher-social-backend-server/cmd/server/api/services/authentication"
+
+	"github.com/google/uuid"
+)
+
+type Follow struct {
+	ID         uuid.UUID           `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	FollowerID uuid.UUID           `json:"follower_id" gorm:"type:uuid;not null;uniqueIndex:idx_follows_follower_followee"`
+	Follower   authentication.User `json:"follower" gorm:"foreignKey:FollowerID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	FolloweeID uuid.UUID           `json:"followee_id" gorm:"type:uuid;not null;uniqueIndex:idx_follows_follower_followee;index"`
+	Followee   authentication.User `json:"followee" gorm:"foreignKey:FolloweeID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	CreatedAt  int64               `json:"created_at" gorm:"autoCreateTime"`
+}
+
+type Block struct {
+	ID        uuid.UUID           `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	BlockerID uuid.UUID           `json:"blocker_id" gorm:"type:uuid;not null;uniqueIndex:idx_blocks_blocker_blocked"`
+	Blocker   authentication.User `json:"blocker" gorm:"foreignKey:BlockerID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	BlockedID uuid.UUID           `json:"blocked_id" gorm:"type:uuid;not null;uniqueIndex:idx_blocks_blocker_blocked;index"`
+	Blocked   authentication.User `json:"blocked" gorm:"foreignKey:BlockedID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	CreatedAt int64               `json:"created_at" gorm:"autoCreateTime"`
+}
@@ -0,0 +1,21 @@
+package users
+
+import (
+	"gopher-social-backend-server/internal/middlewares"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterUsersRoutes(router chi.Router, handler *UsersHandler) {
+	router.With(middlewares.PaginationMiddleware).Get("/users/{userID}/followers", handler.GetFollowersHandler)
+	router.With(middlewares.PaginationMiddleware).Get("/users/{userID}/following", handler.GetFollowingHandler)
+	router.Get("/users/unsubscribe", handler.UnsubscribeConfirmationHandler)
+	router.Post("/users/unsubscribe", handler.UnsubscribeHandler)
+	router.With(middlewares.AuthMiddleware).Get("/users/me", handler.GetSettingsHandler)
+	router.With(middlewares.AuthMiddleware).Patch("/users/me", handler.UpdateSettingsHandler)
+	router.With(middlewares.AuthMiddleware, middlewares.PaginationMiddleware).Get("/users/me/blocked", handler.GetBlockedHandler)
+	router.With(middlewares.AuthMiddleware).Put("/users/{userID}/follow", handler.FollowUserHandler)
+	router.With(middlewares.AuthMiddleware).Delete("/users/{userID}/follow", handler.UnfollowUserHandler)
+	router.With(middlewares.AuthMiddleware).Put("/users/{userID}/block", handler.BlockUserHandler)
+	router.With(middlewares.AuthMiddleware).Delete("/users/{userID}/block", handler.UnblockUserHandler)
+}
@@ -0,0 +1,215 @@
+package users
+
+import (
+	"errors"
+	"gopher-social-backend-server/cmd/server/api/services/authentication"
+	"gopher-social-backend-server/internal/events"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrAlreadyFollowing = errors.New("already following this user")
+	ErrNotFollowing     = errors.New("not following this user")
+	ErrAlreadyBlocked   = errors.New("already blocking this user")
+	ErrNotBlocked       = errors.New("not blocking this user")
+)
+
+type UsersStore interface {
+	FollowUser(followerID, followeeID uuid.UUID) error
+	UnfollowUser(followerID, followeeID uuid.UUID) error
+	IsFollowing(followerID, followeeID uuid.UUID) (bool, error)
+	GetFollowers(userID uuid.UUID, limit, offset int) ([]Follow, error)
+	CountFollowers(userID uuid.UUID) (int64, error)
+	GetFollowing(userID uuid.UUID, limit, offset int) ([]Follow, error)
+	CountFollowing(userID uuid.UUID) (int64, error)
+	BlockUser(blockerID, blockedID uuid.UUID) error
+	UnblockUser(blockerID, blockedID uuid.UUID) error
+	IsBlocked(userID, otherID uuid.UUID) (bool, error)
+	GetBlocked(userID uuid.UUID, limit, offset int) ([]Block, error)
+	CountBlocked(userID uuid.UUID) (int64, error)
+	ResolveMentions(authorID uuid.UUID, handles []string) ([]authentication.User, error)
+	GetDigestRecipients(now time.Time, afterID uuid.UUID, limit int) ([]authentication.User, error)
+	MarkDigestSent(userID uuid.UUID, sentAt time.Time) error
+	DisableDigest(userID uuid.UUID) error
+}
+
+type usersStore struct {
+	postgresDB *gorm.DB
+}
+
+func NewUsersStore(postgresDB *gorm.DB) UsersStore {
+	return &usersStore{
+		postgresDB: postgresDB,
+	}
+}
+
+func (s *usersStore) FollowUser(followerID, followeeID uuid.UUID) error {
+	return s.postgresDB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&Follow{
+			FollowerID: followerID,
+			FolloweeID: followeeID,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAlreadyFollowing
+		}
+
+		return events.Record(tx, constants.DomainEventUserFollowed, events.UserFollowed{FollowerID: followerID, FolloweeID: followeeID})
+	})
+}
+
+func (s *usersStore) UnfollowUser(followerID, followeeID uuid.UUID) error {
+	result := s.postgresDB.Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Delete(&Follow{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFollowing
+	}
+	return nil
+}
+
+func (s *usersStore) IsFollowing(followerID, followeeID uuid.UUID) (bool, error) {
+	var count int64
+	err := s.postgresDB.Model(&Follow{}).Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Count(&count).Error
+	return count > 0, err
+}
+
+func (s *usersStore) GetFollowers(userID uuid.UUID, limit, offset int) ([]Follow, error) {
+	var follows []Follow
+
+	if err := s.postgresDB.Preload("Follower").Where("followee_id = ?", userID).
+		Order("created_at DESC").Order("id").Limit(limit).Offset(offset).
+		Find(&follows).Error; err != nil {
+		return nil, err
+	}
+
+	return follows, nil
+}
+
+func (s *usersStore) CountFollowers(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := s.postgresDB.Model(&Follow{}).Where("followee_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func (s *usersStore) GetFollowing(userID uuid.UUID, limit, offset int) ([]Follow, error) {
+	var follows []Follow
+
+	if err := s.postgresDB.Preload("Followee").Where("follower_id = ?", userID).
+		Order("created_at DESC").Order("id").Limit(limit).Offset(offset).
+		Find(&follows).Error; err != nil {
+		return nil, err
+	}
+
+	return follows, nil
+}
+
+func (s *usersStore) CountFollowing(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := s.postgresDB.Model(&Follow{}).Where("follower_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func (s *usersStore) BlockUser(blockerID, blockedID uuid.UUID) error {
+	return s.postgresDB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&Block{
+			BlockerID: blockerID,
+			BlockedID: blockedID,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAlreadyBlocked
+		}
+
+		return tx.Where("(follower_id = ? AND followee_id = ?) OR (follower_id = ? AND followee_id = ?)",
+			blockerID, blockedID, blockedID, blockerID).Delete(&Follow{}).Error
+	})
+}
+
+func (s *usersStore) UnblockUser(blockerID, blockedID uuid.UUID) error {
+	result := s.postgresDB.Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).Delete(&Block{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotBlocked
+	}
+	return nil
+}
+
+func (s *usersStore) IsBlocked(userID, otherID uuid.UUID) (bool, error) {
+	var count int64
+	err := s.postgresDB.Model(&Block{}).
+		Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", userID, otherID, otherID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (s *usersStore) GetBlocked(userID uuid.UUID, limit, offset int) ([]Block, error) {
+	var blocks []Block
+
+	if err := s.postgresDB.Preload("Blocked").Where("blocker_id = ?", userID).
+		Order("created_at DESC").Order("id").Limit(limit).Offset(offset).
+		Find(&blocks).Error; err != nil {
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+func (s *usersStore) CountBlocked(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := s.postgresDB.Model(&Block{}).Where("blocker_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func (s *usersStore) ResolveMentions(authorID uuid.UUID, handles []string) ([]authentication.User, error) {
+	users := make([]authentication.User, 0)
+	if len(handles) == 0 {
+		return users, nil
+	}
+
+	if err := s.postgresDB.
+		Where("handle IN ? AND id <> ?", handles, authorID).
+		Where("NOT EXISTS (SELECT 1 FROM blocks WHERE (blocks.blocker_id = users.id AND blocks.blocked_id = ?) OR (blocks.blocker_id = ? AND blocks.blocked_id = users.id))", authorID, authorID).
+		Where("(mention_policy = ? OR (mention_policy = ? AND EXISTS (SELECT 1 FROM follows WHERE follows.follower_id = users.id AND follows.followee_id = ?)))",
+			constants.MentionPolicyEveryone, constants.MentionPolicyFollowing, authorID).
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+func (s *usersStore) GetDigestRecipients(now time.Time, afterID uuid.UUID, limit int) ([]authentication.User, error) {
+	var users []authentication.User
+	err := s.postgresDB.
+		Where("is_activated AND id > ?", afterID).
+		Where("((digest_frequency = ? AND digest_sent_at <= ?) OR (digest_frequency = ? AND digest_sent_at <= ?))",
+			constants.DigestDaily, utils.DigestCutoff(constants.DigestDaily, now),
+			constants.DigestWeekly, utils.DigestCutoff(constants.DigestWeekly, now)).
+		Order("id").Limit(limit).
+		Find(&users).Error
+	return users, err
+}
+
+func (s *usersStore) MarkDigestSent(userID uuid.UUID, sentAt time.Time) error {
+	return s.postgresDB.Model(&authentication.User{}).Where("id = ?", userID).
+		UpdateColumn("digest_sent_at", sentAt.Unix()).Error
+}
+
+func (s *usersStore) DisableDigest(userID uuid.UUID) error {
+	return s.postgresDB.Model(&authentication.User{}).Where("id = ?", userID).
+		Update("digest_frequency", constants.DigestOff).Error
+}
@@ -0,0 +1,38 @@
+package users
+
+import (
+	"gopher-social-backend-server/pkg/constants"
+
+	"github.com/google/uuid"
+)
+
+type userSettingsPayload struct {
+	Handle          *string                   `json:"handle" validate:"omitempty,min=3,max=30"`
+	MentionPolicy   constants.MentionPolicy   `json:"mention_policy" validate:"omitempty,oneof=everyone following nobody"`
+	Locale          string                    `json:"locale"`
+	DigestFrequency constants.DigestFrequency `json:"digest_frequency" validate:"omitempty,oneof=off daily weekly"`
+}
+
+type userResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Handle    *string   `json:"handle"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Email     string    `json:"email"`
+}
+
+type userSettingsResponse struct {
+	userResponse
+	MentionPolicy   constants.MentionPolicy   `json:"mention_policy"`
+	Locale          string                    `json:"locale"`
+	DigestFrequency constants.DigestFrequency `json:"digest_frequency"`
+}
+
+type unsubscribeResponse struct {
+	DigestFrequency constants.DigestFrequency `json:"digest_frequency"`
+}
+
+type followResponse struct {
+	User      userResponse `json:"user"`
+	CreatedAt int64        `json:"created_at"`
+}
@@ -0,0 +1,437 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gopher-social-backend-server/cmd/server/api/services/authentication"
+	"gopher-social-backend-server/internal/hooks"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/utils"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WebhooksHandler struct {
+	WebhooksStore       WebhooksStore
+	AuthenticationStore authentication.AuthenticationStore
+}
+
+var validate = validator.New()
+
+func newWebhookResponse(webhook hooks.Webhook) webhookResponse {
+	return webhookResponse{
+		ID:        webhook.ID,
+		OwnerID:   webhook.OwnerID,
+		Global:    webhook.OwnerID == nil,
+		URL:       webhook.URL,
+		Events:    webhook.Events,
+		Active:    webhook.Active,
+		CreatedAt: webhook.CreatedAt,
+		UpdatedAt: webhook.UpdatedAt,
+	}
+}
+
+func newDeliveryResponse(webhook *hooks.Webhook, delivery hooks.Delivery) deliveryResponse {
+	response := deliveryResponse{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		EventID:        delivery.EventID,
+		Event:          delivery.Event,
+		Payload:        json.RawMessage(delivery.Payload),
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		LastAttemptAt:  delivery.LastAttemptAt,
+		ResponseStatus: delivery.ResponseStatus,
+		Error:          delivery.Error,
+		RedeliveryOf:   delivery.RedeliveryOf,
+		CreatedAt:      delivery.CreatedAt,
+	}
+	if delivery.Status == constants.WebhookDeliveryPending {
+		response.NextAttemptAt = &delivery.NextAttemptAt
+	}
+	if webhook.OwnerID == nil {
+		response.ResponseBody = delivery.ResponseBody
+	}
+	return response
+}
+
+func validateEvents(events []constants.WebhookEventType, global bool) ([]constants.WebhookEventType, error) {
+	validated := make([]constants.WebhookEventType, 0, len(events))
+	for _, event := range events {
+		if !slices.Contains(constants.WebhookEventTypes, event) {
+			return nil, fmt.Errorf("invalid event: %s", event)
+		}
+		if _, ok := constants.GlobalWebhookEventTypes[event]; ok && !global {
+			return nil, fmt.Errorf("invalid event: %s is only available to global webhooks", event)
+		}
+		if !slices.Contains(validated, event) {
+			validated = append(validated, event)
+		}
+	}
+	return validated, nil
+}
+
+func (h *WebhooksHandler) authUser(w http.ResponseWriter, r *http.Request) (*authentication.User, bool) {
+	user, err := h.AuthenticationStore.GetUserByID(utils.ViewerID(r).String())
+	if err != nil {
+		utils.WriteError(w, http.StatusUnauthorized, "user not found")
+		return nil, false
+	}
+	return user, true
+}
+
+func canManage(user *authentication.User, webhook *hooks.Webhook) bool {
+	if webhook.OwnerID == nil {
+		return user.Role == constants.RoleAdmin
+	}
+	return *webhook.OwnerID == user.ID
+}
+
+func (h *WebhooksHandler) authorizeWebhook(w http.ResponseWriter, r *http.Request) (*hooks.Webhook, bool) {
+	webhookID, err := uuid.Parse(chi.URLParam(r, "webhookID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+
+	user, ok := h.authUser(w, r)
+	if !ok {
+		return nil, false
+	}
+
+	webhook, err := h.WebhooksStore.GetWebhookByID(webhookID)
+	if err != nil || !canManage(user, webhook) {
+		utils.WriteError(w, http.StatusNotFound, "webhook not found")
+		return nil, false
+	}
+
+	return webhook, true
+}
+
+func (h *WebhooksHandler) authorizeDelivery(w http.ResponseWriter, r *http.Request) (*hooks.Webhook, *hooks.Delivery, bool) {
+	webhook, ok := h.authorizeWebhook(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+
+	deliveryID, err := uuid.Parse(chi.URLParam(r, "deliveryID"))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return nil, nil, false
+	}
+
+	delivery, err := h.WebhooksStore.GetDelivery(webhook.ID, deliveryID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.WriteError(w, http.StatusNotFound, "delivery not found")
+			return nil, nil, false
+		}
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return nil, nil, false
+	}
+
+	return webhook, delivery, true
+}
+
+func (h *WebhooksHandler) CreateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	var payload createWebhookPayload
+	if err := utils.ParseJSON(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := validate.Struct(payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := hooks.ValidateURL(payload.URL); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, ok := h.authUser(w, r)
+	if !ok {
+		return
+	}
+
+	if payload.Global && user.Role != constants.RoleAdmin {
+		utils.WriteError(w, http.StatusForbidden, "only admins can create global webhooks")
+		return
+	}
+
+	events, err := validateEvents(payload.Events, payload.Global)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	webhook := hooks.Webhook{URL: payload.URL, Events: events, Active: true}
+	if !payload.Global {
+		count, err := h.WebhooksStore.CountOwnedWebhooks(user.ID)
+		if err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		if count >= constants.MaxWebhooksPerUser {
+			utils.WriteError(w, http.StatusConflict, fmt.Sprintf("you can have at most %d webhooks", constants.MaxWebhooksPerUser))
+			return
+		}
+		webhook.OwnerID = &user.ID
+	}
+
+	if webhook.Secret, err = hooks.GenerateSecret(); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err := h.WebhooksStore.CreateWebhook(&webhook); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := newWebhookResponse(webhook)
+	response.Secret = webhook.Secret
+	utils.WriteJSON(w, http.StatusCreated, response)
+}
+
+func (h *WebhooksHandler) GetWebhooksHandler(w http.ResponseWriter, r *http.Request) {
+	user, ok := h.authUser(w, r)
+	if !ok {
+		return
+	}
+
+	includeGlobal := user.Role == constants.RoleAdmin
+	limit := r.Context().Value(constants.LimitKey).(int)
+	offset := r.Context().Value(constants.OffsetKey).(int)
+
+	webhooks, err := h.WebhooksStore.GetWebhooks(user.ID, includeGlobal, limit+1, offset)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	webhooks, pageInfo, err := utils.PaginateResults(r, webhooks)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if utils.IncludeTotal(r) {
+		total, err := h.WebhooksStore.CountWebhooks(user.ID, includeGlobal)
+		if err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		pageInfo.Total = &total
+	}
+
+	webhookResponses := make([]webhookResponse, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		webhookResponses = append(webhookResponses, newWebhookResponse(webhook))
+	}
+
+	utils.WritePage(w, r, http.StatusOK, webhookResponses, pageInfo)
+}
+
+func (h *WebhooksHandler) GetWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := h.authorizeWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, newWebhookResponse(*webhook))
+}
+
+func (h *WebhooksHandler) UpdateWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := h.authorizeWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	var payload updateWebhookPayload
+	if err := utils.ParseJSON(r, &payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err := validate.Struct(payload); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if payload.URL != nil {
+		if err := hooks.ValidateURL(*payload.URL); err != nil {
+			utils.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		webhook.URL = *payload.URL
+	}
+
+	if payload.Events != nil {
+		events, err := validateEvents(*payload.Events, webhook.OwnerID == nil)
+		if err != nil {
+			utils.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		webhook.Events = events
+	}
+
+	if payload.Active != nil {
+		webhook.Active = *payload.Active
+	}
+
+	if err := h.WebhooksStore.UpdateWebhook(webhook); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, newWebhookResponse(*webhook))
+}
+
+func (h *WebhooksHandler) DeleteWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := h.authorizeWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.WebhooksStore.DeleteWebhook(webhook.ID); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusNoContent, nil)
+}
+
+func (h *WebhooksHandler) RotateSecretHandler(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := h.authorizeWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	var err error
+	if webhook.Secret, err = hooks.GenerateSecret(); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err := h.WebhooksStore.UpdateWebhook(webhook); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := newWebhookResponse(*webhook)
+	response.Secret = webhook.Secret
+	utils.WriteJSON(w, http.StatusOK, response)
+}
+
+func (h *WebhooksHandler) PingWebhookHandler(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := h.authorizeWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	delivery, err := hooks.NewDelivery(webhook.ID, hooks.Payload{
+		ID:        uuid.New(),
+		Type:      constants.WebhookEventPing,
+		CreatedAt: time.Now().Unix(),
+		Data:      pingEvent{WebhookID: webhook.ID},
+	})
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	if err := h.WebhooksStore.CreateDelivery(&delivery); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusAccepted, newDeliveryResponse(webhook, delivery))
+}
+
+func (h *WebhooksHandler) GetDeliveriesHandler(w http.ResponseWriter, r *http.Request) {
+	webhook, ok := h.authorizeWebhook(w, r)
+	if !ok {
+		return
+	}
+
+	status := constants.WebhookDeliveryStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "", constants.WebhookDeliveryPending, constants.WebhookDeliverySucceeded, constants.WebhookDeliveryFailed:
+	default:
+		utils.WriteError(w, http.StatusBadRequest, "invalid status: must be pending, succeeded or failed")
+		return
+	}
+
+	limit := r.Context().Value(constants.LimitKey).(int)
+	offset := r.Context().Value(constants.OffsetKey).(int)
+
+	deliveries, err := h.WebhooksStore.GetDeliveries(webhook.ID, status, limit+1, offset)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	deliveries, pageInfo, err := utils.PaginateResults(r, deliveries)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if utils.IncludeTotal(r) {
+		total, err := h.WebhooksStore.CountDeliveries(webhook.ID, status)
+		if err != nil {
+			utils.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		pageInfo.Total = &total
+	}
+
+	deliveryResponses := make([]deliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		deliveryResponses = append(deliveryResponses, newDeliveryResponse(webhook, delivery))
+	}
+
+	utils.WritePage(w, r, http.StatusOK, deliveryResponses, pageInfo)
+}
+
+func (h *WebhooksHandler) GetDeliveryHandler(w http.ResponseWriter, r *http.Request) {
+	webhook, delivery, ok := h.authorizeDelivery(w, r)
+	if !ok {
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, newDeliveryResponse(webhook, *delivery))
+}
+
+func (h *WebhooksHandler) RedeliverHandler(w http.ResponseWriter, r *http.Request) {
+	webhook, delivery, ok := h.authorizeDelivery(w, r)
+	if !ok {
+		return
+	}
+
+	redelivery := hooks.Delivery{
+		WebhookID:     webhook.ID,
+		EventID:       delivery.EventID,
+		Event:         delivery.Event,
+		Payload:       delivery.Payload,
+		Status:        constants.WebhookDeliveryPending,
+		NextAttemptAt: time.Now().Unix(),
+		RedeliveryOf:  &delivery.ID,
+	}
+
+	if err := h.WebhooksStore.CreateDelivery(&redelivery); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusAccepted, newDeliveryResponse(webhook, redelivery))
+}
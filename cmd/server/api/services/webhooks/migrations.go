@@ -0,0 +1,13 @@
+package webhooks
+
+import "gopher-social-backend-server/internal/database"
+
+var Migrations = []database.Migration{
+	{
+		ID: "0017_webhook_deliveries_event_unique",
+		SQL: `
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_event
+			ON webhook_deliveries (webhook_id, event_id) WHERE redelivery_of IS NULL;
+		`,
+	},
+}
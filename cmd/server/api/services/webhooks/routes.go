@@ -0,0 +1,20 @@
+package webhooks
+
+import (
+	"gopher-social-backend-server/internal/middlewares"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterWebhooksRoutes(router chi.Router, handler *WebhooksHandler) {
+	router.With(middlewares.AuthMiddleware).Post("/webhooks", handler.CreateWebhookHandler)
+	router.With(middlewares.AuthMiddleware, middlewares.PaginationMiddleware).Get("/webhooks", handler.GetWebhooksHandler)
+	router.With(middlewares.AuthMiddleware).Get("/webhooks/{webhookID}", handler.GetWebhookHandler)
+	router.With(middlewares.AuthMiddleware).Patch("/webhooks/{webhookID}", handler.UpdateWebhookHandler)
+	router.With(middlewares.AuthMiddleware).Delete("/webhooks/{webhookID}", handler.DeleteWebhookHandler)
+	router.With(middlewares.AuthMiddleware).Post("/webhooks/{webhookID}/secret", handler.RotateSecretHandler)
+	router.With(middlewares.AuthMiddleware).Post("/webhooks/{webhookID}/ping", handler.PingWebhookHandler)
+	router.With(middlewares.AuthMiddleware, middlewares.PaginationMiddleware).Get("/webhooks/{webhookID}/deliveries", handler.GetDeliveriesHandler)
+	router.With(middlewares.AuthMiddleware).Get("/webhooks/{webhookID}/deliveries/{deliveryID}", handler.GetDeliveryHandler)
+	router.With(middlewares.AuthMiddleware).Post("/webhooks/{webhookID}/deliveries/{deliveryID}/redeliver", handler.RedeliverHandler)
+}
@@ -0,0 +1,172 @@
+package webhooks
+
+import (
+	"gopher-social-backend-server/internal/hooks"
+	"gopher-social-backend-server/pkg/constants"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhooksStore interface {
+	CreateWebhook(webhook *hooks.Webhook) error
+	CountOwnedWebhooks(ownerID uuid.UUID) (int64, error)
+	GetWebhooks(ownerID uuid.UUID, includeGlobal bool, limit, offset int) ([]hooks.Webhook, error)
+	CountWebhooks(ownerID uuid.UUID, includeGlobal bool) (int64, error)
+	GetWebhookByID(webhookID uuid.UUID) (*hooks.Webhook, error)
+	UpdateWebhook(webhook *hooks.Webhook) error
+	DeleteWebhook(webhookID uuid.UUID) error
+	EnqueueEvent(eventID uuid.UUID, eventType constants.WebhookEventType, ownerIDs []uuid.UUID, data any) error
+	CreateDelivery(delivery *hooks.Delivery) error
+	GetDeliveries(webhookID uuid.UUID, status constants.WebhookDeliveryStatus, limit, offset int) ([]hooks.Delivery, error)
+	CountDeliveries(webhookID uuid.UUID, status constants.WebhookDeliveryStatus) (int64, error)
+	GetDelivery(webhookID, deliveryID uuid.UUID) (*hooks.Delivery, error)
+	ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]hooks.Delivery, error)
+	RecordDeliveryAttempt(delivery *hooks.Delivery) error
+	PurgeDeliveries(before time.Time) (int64, error)
+}
+
+type webhooksStore struct {
+	postgresDB *gorm.DB
+}
+
+func NewWebhooksStore(postgresDB *gorm.DB) WebhooksStore {
+	return &webhooksStore{
+		postgresDB: postgresDB,
+	}
+}
+
+func ownedWebhooks(ownerID uuid.UUID, includeGlobal bool) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if includeGlobal {
+			return db.Where("owner_id = ? OR owner_id IS NULL", ownerID)
+		}
+		return db.Where("owner_id = ?", ownerID)
+	}
+}
+
+func deliveryStatus(webhookID uuid.UUID, status constants.WebhookDeliveryStatus) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("webhook_id = ?", webhookID)
+		if status != "" {
+			db = db.Where("status = ?", status)
+		}
+		return db
+	}
+}
+
+func (s *webhooksStore) CreateWebhook(webhook *hooks.Webhook) error {
+	return s.postgresDB.Create(webhook).Error
+}
+
+func (s *webhooksStore) CountOwnedWebhooks(ownerID uuid.UUID) (int64, error) {
+	var count int64
+	err := s.postgresDB.Model(&hooks.Webhook{}).Where("owner_id = ?", ownerID).Count(&count).Error
+	return count, err
+}
+
+func (s *webhooksStore) GetWebhooks(ownerID uuid.UUID, includeGlobal bool, limit, offset int) ([]hooks.Webhook, error) {
+	var webhooks []hooks.Webhook
+	err := s.postgresDB.Scopes(ownedWebhooks(ownerID, includeGlobal)).
+		Order("created_at DESC").Order("id DESC").
+		Limit(limit).Offset(offset).
+		Find(&webhooks).Error
+	return webhooks, err
+}
+
+func (s *webhooksStore) CountWebhooks(ownerID uuid.UUID, includeGlobal bool) (int64, error) {
+	var count int64
+	err := s.postgresDB.Model(&hooks.Webhook{}).Scopes(ownedWebhooks(ownerID, includeGlobal)).Count(&count).Error
+	return count, err
+}
+
+func (s *webhooksStore) GetWebhookByID(webhookID uuid.UUID) (*hooks.Webhook, error) {
+	var webhook hooks.Webhook
+	if err := s.postgresDB.Where("id = ?", webhookID).First(&webhook).Error; err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (s *webhooksStore) UpdateWebhook(webhook *hooks.Webhook) error {
+	return s.postgresDB.Model(webhook).Select("url", "secret", "events", "active", "updated_at").Updates(webhook).Error
+}
+
+func (s *webhooksStore) DeleteWebhook(webhookID uuid.UUID) error {
+	return s.postgresDB.Where("id = ?", webhookID).Delete(&hooks.Webhook{}).Error
+}
+
+func (s *webhooksStore) EnqueueEvent(eventID uuid.UUID, eventType constants.WebhookEventType, ownerIDs []uuid.UUID, data any) error {
+	return hooks.Enqueue(s.postgresDB, eventID, eventType, ownerIDs, data)
+}
+
+func (s *webhooksStore) CreateDelivery(delivery *hooks.Delivery) error {
+	return s.postgresDB.Create(delivery).Error
+}
+
+func (s *webhooksStore) GetDeliveries(webhookID uuid.UUID, status constants.WebhookDeliveryStatus, limit, offset int) ([]hooks.Delivery, error) {
+	var deliveries []hooks.Delivery
+	err := s.postgresDB.Scopes(deliveryStatus(webhookID, status)).
+		Order("created_at DESC").Order("id DESC").
+		Limit(limit).Offset(offset).
+		Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (s *webhooksStore) CountDeliveries(webhookID uuid.UUID, status constants.WebhookDeliveryStatus) (int64, error) {
+	var count int64
+	err := s.postgresDB.Model(&hooks.Delivery{}).Scopes(deliveryStatus(webhookID, status)).Count(&count).Error
+	return count, err
+}
+
+func (s *webhooksStore) GetDelivery(webhookID, deliveryID uuid.UUID) (*hooks.Delivery, error) {
+	var delivery hooks.Delivery
+	if err := s.postgresDB.Where("id = ? AND webhook_id = ?", deliveryID, webhookID).First(&delivery).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (s *webhooksStore) ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]hooks.Delivery, error) {
+	var deliveries []hooks.Delivery
+
+	err := s.postgresDB.Transaction(func(tx *gorm.DB) error {
+		var deliveryIDs []uuid.UUID
+		if err := tx.Model(&hooks.Delivery{}).
+			Joins("JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id AND webhooks.active").
+			Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "webhook_deliveries"}, Options: "SKIP LOCKED"}).
+			Where("webhook_deliveries.status = ? AND webhook_deliveries.next_attempt_at <= ?", constants.WebhookDeliveryPending, now.Unix()).
+			Order("webhook_deliveries.next_attempt_at").Limit(limit).
+			Pluck("webhook_deliveries.id", &deliveryIDs).Error; err != nil {
+			return err
+		}
+
+		if len(deliveryIDs) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&hooks.Delivery{}).Where("id IN ?", deliveryIDs).
+			UpdateColumn("next_attempt_at", now.Add(lease).Unix()).Error; err != nil {
+			return err
+		}
+
+		return tx.Preload("Webhook").Where("id IN ?", deliveryIDs).Order("next_attempt_at").Find(&deliveries).Error
+	})
+
+	return deliveries, err
+}
+
+func (s *webhooksStore) RecordDeliveryAttempt(delivery *hooks.Delivery) error {
+	return s.postgresDB.Model(delivery).
+		Select("status", "attempts", "next_attempt_at", "last_attempt_at", "response_status", "response_body", "error", "updated_at").
+		Updates(delivery).Error
+}
+
+func (s *webhooksStore) PurgeDeliveries(before time.Time) (int64, error) {
+	result := s.postgresDB.
+		Where("status <> ? AND updated_at < ?", constants.WebhookDeliveryPending, before.Unix()).
+		Delete(&hooks.Delivery{})
+	return result.RowsAffected, result.Error
+}
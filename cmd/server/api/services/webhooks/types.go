@@ -0,0 +1,53 @@
+package webhooks
+
+import (
+	"encoding/json"
+	"gopher-social-backend-server/pkg/constants"
+
+	"github.com/google/uuid"
+)
+
+type createWebhookPayload struct {
+	URL    string                       `json:"url" validate:"required,http_url,max=2048"`
+	Events []constants.WebhookEventType `json:"events" validate:"required,min=1,dive,required"`
+	Global bool                         `json:"global"`
+}
+
+type updateWebhookPayload struct {
+	URL    *string                       `json:"url" validate:"omitempty,http_url,max=2048"`
+	Events *[]constants.WebhookEventType `json:"events" validate:"omitempty,min=1,dive,required"`
+	Active *bool                         `json:"active"`
+}
+
+type webhookResponse struct {
+	ID        uuid.UUID                    `json:"id"`
+	OwnerID   *uuid.UUID                   `json:"owner_id"`
+	Global    bool                         `json:"global"`
+	URL       string                       `json:"url"`
+	Events    []constants.WebhookEventType `json:"events"`
+	Active    bool                         `json:"active"`
+	Secret    string                       `json:"secret,omitempty"`
+	CreatedAt int64                        `json:"created_at"`
+	UpdatedAt int64                        `json:"updated_at"`
+}
+
+type deliveryResponse struct {
+	ID             uuid.UUID                       `json:"id"`
+	WebhookID      uuid.UUID                       `json:"webhook_id"`
+	EventID        uuid.UUID                       `json:"event_id"`
+	Event          constants.WebhookEventType      `json:"event"`
+	Payload        json.RawMessage                 `json:"payload"`
+	Status         constants.WebhookDeliveryStatus `json:"status"`
+	Attempts       int                             `json:"attempts"`
+	NextAttemptAt  *int64                          `json:"next_attempt_at"`
+	LastAttemptAt  *int64                          `json:"last_attempt_at"`
+	ResponseStatus *int                            `json:"response_status"`
+	ResponseBody   string                          `json:"response_body,omitempty"`
+	Error          string                          `json:"error"`
+	RedeliveryOf   *uuid.UUID                      `json:"redelivery_of"`
+	CreatedAt      int64                           `json:"created_at"`
+}
+
+type pingEvent struct {
+	WebhookID uuid.UUID `json:"webhook_id"`
+}
@@ -3,7 +3,15 @@ package api
 import (
 	"gopher-social-backend-server/cmd/server/api/services/authentication"
 	"gopher-social-backend-server/cmd/server/api/services/comments"
+	"gopher-social-backend-server/cmd/server/api/services/notifications"
 	"gopher-social-backend-server/cmd/server/api/services/posts"
+	"gopher-social-backend-server/cmd/server/api/services/search"
+	"gopher-social-backend-server/cmd/server/api/services/stream"
+	"gopher-social-backend-server/cmd/server/api/services/tags"
+	"gopher-social-backend-server/cmd/server/api/services/users"
+	"gopher-social-backend-server/cmd/server/api/services/webhooks"
+	"gopher-social-backend-server/internal/events"
+	"gopher-social-backend-server/internal/queue"
 
 	"gorm.io/gorm"
 )
@@ -12,6 +20,14 @@ type Store struct {
 	AuthenticationStore authentication.AuthenticationStore
 	PostsStore          posts.PostsStore
 	CommentsStore       comments.CommentsStore
+	SearchStore         search.SearchStore
+	TagsStore           tags.TagsStore
+	UsersStore          users.UsersStore
+	NotificationsStore  notifications.NotificationsStore
+	StreamStore         stream.StreamStore
+	WebhooksStore       webhooks.WebhooksStore
+	OutboxStore         events.OutboxStore
+	JobsStore           queue.JobsStore
 }
 
 func NewStore(postgresDB *gorm.DB) *Store {
@@ -19,5 +35,13 @@ func NewStore(postgresDB *gorm.DB) *Store {
 		AuthenticationStore: authentication.NewAuthenticationStore(postgresDB),
 		PostsStore:          posts.NewPostsStore(postgresDB),
 		CommentsStore:       comments.NewCommentStore(postgresDB),
+		SearchStore:         search.NewSearchStore(postgresDB),
+		TagsStore:           tags.NewTagsStore(postgresDB),
+		UsersStore:          users.NewUsersStore(postgresDB),
+		NotificationsStore:  notifications.NewNotificationsStore(postgresDB),
+		StreamStore:         stream.NewStreamStore(postgresDB),
+		WebhooksStore:       webhooks.NewWebhooksStore(postgresDB),
+		OutboxStore:         events.NewOutboxStore(postgresDB),
+		JobsStore:           queue.NewJobsStore(postgresDB),
 	}
 }
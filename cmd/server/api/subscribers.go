@@ -0,0 +1,271 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"gopher-social-backend-server/cmd/server/api/services/notifications"
+	"gopher-social-backend-server/internal/events"
+	"gopher-social-backend-server/internal/queue"
+	"gopher-social-backend-server/pkg/constants"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+func (app *Application) newEventBus() *events.Bus {
+	bus := events.NewBus()
+
+	bus.Subscribe(constants.DomainEventUserRegistered, "mailer", app.mailRegisteredUser)
+	bus.Subscribe(constants.DomainEventUserActivated, "mailer", app.mailActivatedUser)
+	bus.Subscribe(constants.DomainEventPasswordResetRequested, "mailer", app.mailPasswordReset)
+	bus.Subscribe(constants.DomainEventPasswordChanged, "mailer", app.mailPasswordChanged)
+
+	bus.Subscribe(constants.DomainEventUserFollowed, "notifications", app.notifyFollow)
+	bus.Subscribe(constants.DomainEventCommentCreated, "notifications", app.notifyComment)
+	bus.Subscribe(constants.DomainEventReactionChanged, "notifications", app.notifyLike)
+	bus.Subscribe(constants.DomainEventUsersMentioned, "notifications", app.notifyMentions)
+
+	bus.Subscribe(constants.DomainEventUserRegistered, "webhooks", app.webhookUserRegistered)
+	bus.Subscribe(constants.DomainEventPostCreated, "webhooks", app.webhookPostCreated)
+	bus.Subscribe(constants.DomainEventCommentCreated, "webhooks", app.webhookCommentCreated)
+
+	return bus
+}
+
+func skipMissing(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (app *Application) enqueueEmail(event events.OutboxEvent, payload sendEmailJob) error {
+	return queue.EnqueueUnique(app.PostgresDB, "email:"+event.ID.String(), constants.JobSendEmail, payload, time.Now())
+}
+
+func (app *Application) mailRegisteredUser(ctx context.Context, event events.OutboxEvent) error {
+	var payload events.UserRegistered
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+
+	kind := constants.EmailActivation
+	if payload.OAuth != constants.ProviderNone {
+		kind = constants.EmailOAuthWelcome
+	}
+
+	return app.enqueueEmail(event, sendEmailJob{Kind: kind, UserID: payload.UserID, Email: payload.Email, OAuth: payload.OAuth})
+}
+
+func (app *Application) mailActivatedUser(ctx context.Context, event events.OutboxEvent) error {
+	var payload events.UserActivated
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+
+	return app.enqueueEmail(event, sendEmailJob{Kind: constants.EmailAccountActivated, UserID: payload.UserID, Email: payload.Email})
+}
+
+func (app *Application) mailPasswordReset(ctx context.Context, event events.OutboxEvent) error {
+	var payload events.PasswordResetRequested
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+
+	return app.enqueueEmail(event, sendEmailJob{Kind: constants.EmailPasswordReset, UserID: payload.UserID, Email: payload.Email})
+}
+
+func (app *Application) mailPasswordChanged(ctx context.Context, event events.OutboxEvent) error {
+	var payload events.PasswordChanged
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+
+	return app.enqueueEmail(event, sendEmailJob{Kind: constants.EmailPasswordChanged, UserID: payload.UserID, Email: payload.Email})
+}
+
+func (app *Application) notify(notification notifications.Notification) error {
+	if notification.PostID != nil {
+		if _, err := app.Store.PostsStore.GetVisiblePostByID(*notification.PostID, notification.UserID); err != nil {
+			return skipMissing(err)
+		}
+	}
+
+	return app.Store.NotificationsStore.CreateNotifications([]notifications.Notification{notification})
+}
+
+func (app *Application) notifyFollow(ctx context.Context, event events.OutboxEvent) error {
+	var payload events.UserFollowed
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+
+	return app.notify(notifications.Notification{
+		UserID:  payload.FolloweeID,
+		ActorID: payload.FollowerID,
+		Type:    constants.NotificationFollow,
+	})
+}
+
+func (app *Application) notifyComment(ctx context.Context, event events.OutboxEvent) error {
+	var payload events.CommentCreated
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+
+	if payload.ParentID == nil {
+		post, err := app.Store.PostsStore.GetPostByID(payload.PostID)
+		if err != nil {
+			return skipMissing(err)
+		}
+
+		return app.notify(notifications.Notification{
+			UserID:  post.AuthorID,
+			ActorID: payload.AuthorID,
+			Type:    constants.NotificationComment,
+			PostID:  &post.ID,
+		})
+	}
+
+	parent, err := app.Store.CommentsStore.GetCommentByID(*payload.ParentID)
+	if err != nil {
+		return skipMissing(err)
+	}
+
+	return app.notify(notifications.Notification{
+		UserID:    parent.AuthorID,
+		ActorID:   payload.AuthorID,
+		Type:      constants.NotificationReply,
+		PostID:    &parent.PostID,
+		CommentID: &parent.ID,
+	})
+}
+
+func (app *Application) notifyLike(ctx context.Context, event events.OutboxEvent) error {
+	var payload events.ReactionChanged
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+
+	if payload.Type != constants.ReactionLike {
+		return nil
+	}
+
+	if payload.CommentID != nil {
+		comment, err := app.Store.CommentsStore.GetCommentByID(*payload.CommentID)
+		if err != nil {
+			return skipMissing(err)
+		}
+
+		return app.notify(notifications.Notification{
+			UserID:    comment.AuthorID,
+			ActorID:   payload.UserID,
+			Type:      constants.NotificationLike,
+			PostID:    &comment.PostID,
+			CommentID: &comment.ID,
+		})
+	}
+
+	post, err := app.Store.PostsStore.GetPostByID(payload.PostID)
+	if err != nil {
+		return skipMissing(err)
+	}
+
+	return app.notify(notifications.Notification{
+		UserID:  post.AuthorID,
+		ActorID: payload.UserID,
+		Type:    constants.NotificationLike,
+		PostID:  &post.ID,
+	})
+}
+
+func (app *Application) notifyMentions(ctx context.Context, event events.OutboxEvent) error {
+	var payload events.UsersMentioned
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, userID := range payload.UserIDs {
+		if err := app.notify(notifications.Notification{
+			UserID:    userID,
+			ActorID:   payload.ActorID,
+			Type:      constants.NotificationMention,
+			PostID:    &payload.PostID,
+			CommentID: payload.CommentID,
+		}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (app *Application) webhookUserRegistered(ctx context.Context, event events.OutboxEvent) error {
+	var payload events.UserRegistered
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+
+	user, err := app.Store.AuthenticationStore.GetUserByID(payload.UserID.String())
+	if err != nil {
+		return skipMissing(err)
+	}
+
+	return app.Store.WebhooksStore.EnqueueEvent(event.ID, constants.WebhookEventUserRegistered, nil, userWebhookData{
+		ID:        user.ID,
+		Handle:    user.Handle,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Email:     user.Email,
+		OAuth:     user.OAuth,
+		CreatedAt: user.CreatedAt,
+	})
+}
+
+func (app *Application) webhookPostCreated(ctx context.Context, event events.OutboxEvent) error {
+	var payload events.PostCreated
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+
+	post, err := app.Store.PostsStore.GetPostByID(payload.PostID)
+	if err != nil {
+		return skipMissing(err)
+	}
+
+	return app.Store.WebhooksStore.EnqueueEvent(event.ID, constants.WebhookEventPostCreated, []uuid.UUID{post.AuthorID}, postWebhookData{
+		ID:            post.ID,
+		AuthorID:      post.AuthorID,
+		Title:         post.Title,
+		Content:       post.Content,
+		ContentFormat: post.ContentFormat,
+		Visibility:    post.Visibility,
+		PublishedAt:   post.PublishedAt,
+	})
+}
+
+func (app *Application) webhookCommentCreated(ctx context.Context, event events.OutboxEvent) error {
+	var payload events.CommentCreated
+	if err := event.Decode(&payload); err != nil {
+		return err
+	}
+
+	comment, err := app.Store.CommentsStore.GetCommentByID(payload.CommentID)
+	if err != nil {
+		return skipMissing(err)
+	}
+	if comment.DeletedAt.Valid {
+		return nil
+	}
+
+	return app.Store.WebhooksStore.EnqueueEvent(event.ID, constants.WebhookEventCommentCreated, []uuid.UUID{comment.Post.AuthorID, comment.AuthorID}, commentWebhookData{
+		ID:            comment.ID,
+		PostID:        comment.PostID,
+		ParentID:      comment.ParentID,
+		AuthorID:      comment.AuthorID,
+		Content:       comment.Content,
+		ContentFormat: comment.ContentFormat,
+		CreatedAt:     comment.CreatedAt,
+	})
+}
@@ -1,14 +1,63 @@
 package api
 
-import "gorm.io/gorm"
+import (
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/mailer"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
 
 type Config struct {
 	Address string
 }
 
 type Application struct {
-	Config     Config
-	Handlers   *Handlers
-	Store      *Store
-	PostgresDB *gorm.DB
+	Config         Config
+	Handlers       *Handlers
+	Store          *Store
+	PostgresDB     *gorm.DB
+	Mailer         mailer.Mailer
+	EmailTemplates *mailer.Registry
+}
+
+type userWebhookData struct {
+	ID        uuid.UUID               `json:"id"`
+	Handle    *string                 `json:"handle"`
+	FirstName string                  `json:"first_name"`
+	LastName  string                  `json:"last_name"`
+	Email     string                  `json:"email"`
+	OAuth     constants.OAuthProvider `json:"oauth"`
+	CreatedAt int64                   `json:"created_at"`
+}
+
+type postWebhookData struct {
+	ID            uuid.UUID                `json:"id"`
+	AuthorID      uuid.UUID                `json:"author_id"`
+	Title         string                   `json:"title"`
+	Content       string                   `json:"content"`
+	ContentFormat constants.ContentFormat  `json:"content_format"`
+	Visibility    constants.PostVisibility `json:"visibility"`
+	PublishedAt   *int64                   `json:"published_at"`
+}
+
+type commentWebhookData struct {
+	ID            uuid.UUID               `json:"id"`
+	PostID        uuid.UUID               `json:"post_id"`
+	ParentID      *uuid.UUID              `json:"parent_id"`
+	AuthorID      uuid.UUID               `json:"author_id"`
+	Content       string                  `json:"content"`
+	ContentFormat constants.ContentFormat `json:"content_format"`
+	CreatedAt     int64                   `json:"created_at"`
+}
+
+type sendEmailJob struct {
+	Kind   constants.EmailKind     `json:"kind"`
+	UserID uuid.UUID               `json:"user_id"`
+	Email  string                  `json:"email"`
+	OAuth  constants.OAuthProvider `json:"oauth,omitempty"`
+}
+
+type sendDigestJob struct {
+	UserID uuid.UUID `json:"user_id"`
 }
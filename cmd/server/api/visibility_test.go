@@ -0,0 +1,168 @@
+package api
+
+import (
+	"gopher-social-backend-server/cmd/server/api/services/authentication"
+	"gopher-social-backend-server/cmd/server/api/services/comments"
+	"gopher-social-backend-server/cmd/server/api/services/posts"
+	"gopher-social-backend-server/pkg/constants"
+	"net/http"
+	"testing"
+)
+
+func TestPostVisibility(t *testing.T) {
+	app := newTestApp(t)
+	router := app.testRouter()
+
+	author := createTestUser(t, app, "author")
+	follower := createTestUser(t, app, "follower")
+	stranger := createTestUser(t, app, "stranger")
+
+	if err := app.Store.UsersStore.FollowUser(follower.ID, author.ID); err != nil {
+		t.Fatalf("could not follow author: %v", err)
+	}
+
+	visibilities := []constants.PostVisibility{
+		constants.PostVisibilityPublic,
+		constants.PostVisibilityFollowers,
+		constants.PostVisibilityUnlisted,
+		constants.PostVisibilityPrivate,
+	}
+
+	postIDs := make(map[constants.PostVisibility]string, len(visibilities))
+	commentIDs := make(map[constants.PostVisibility]string, len(visibilities))
+	for _, visibility := range visibilities {
+		post := &posts.Post{
+			AuthorID:   author.ID,
+			Title:      "A " + string(visibility) + " post",
+			Content:    "Body",
+			Visibility: visibility,
+		}
+		if err := app.Store.PostsStore.CreatePost(post, &[]string{"visibility"}, nil); err != nil {
+			t.Fatalf("could not create %s post: %v", visibility, err)
+		}
+		postIDs[visibility] = post.ID.String()
+
+		comment := &comments.Comment{PostID: post.ID, AuthorID: author.ID, Content: "A comment"}
+		if err := app.Store.CommentsStore.CreateComment(comment, nil); err != nil {
+			t.Fatalf("could not comment on %s post: %v", visibility, err)
+		}
+		commentIDs[visibility] = comment.ID.String()
+	}
+
+	type expectation struct {
+		get, listed bool
+	}
+
+	tests := []struct {
+		name   string
+		viewer *authentication.User
+		want   map[constants.PostVisibility]expectation
+	}{
+		{
+			name:   "anonymous",
+			viewer: nil,
+			want: map[constants.PostVisibility]expectation{
+				constants.PostVisibilityPublic:    {get: true, listed: true},
+				constants.PostVisibilityFollowers: {get: false, listed: false},
+				constants.PostVisibilityUnlisted:  {get: true, listed: false},
+				constants.PostVisibilityPrivate:   {get: false, listed: false},
+			},
+		},
+		{
+			name:   "follower",
+			viewer: follower,
+			want: map[constants.PostVisibility]expectation{
+				constants.PostVisibilityPublic:    {get: true, listed: true},
+				constants.PostVisibilityFollowers: {get: true, listed: true},
+				constants.PostVisibilityUnlisted:  {get: true, listed: false},
+				constants.PostVisibilityPrivate:   {get: false, listed: false},
+			},
+		},
+		{
+			name:   "non-follower",
+			viewer: stranger,
+			want: map[constants.PostVisibility]expectation{
+				constants.PostVisibilityPublic:    {get: true, listed: true},
+				constants.PostVisibilityFollowers: {get: false, listed: false},
+				constants.PostVisibilityUnlisted:  {get: true, listed: false},
+				constants.PostVisibilityPrivate:   {get: false, listed: false},
+			},
+		},
+		{
+			name:   "author",
+			viewer: author,
+			want: map[constants.PostVisibility]expectation{
+				constants.PostVisibilityPublic:    {get: true, listed: true},
+				constants.PostVisibilityFollowers: {get: true, listed: true},
+				constants.PostVisibilityUnlisted:  {get: true, listed: false},
+				constants.PostVisibilityPrivate:   {get: true, listed: false},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		listed := make(map[string]map[string]bool)
+		for _, path := range []string{"/api/v1/posts?limit=20", "/api/v1/tags/visibility/posts?limit=20"} {
+			w := serve(t, router, http.MethodGet, path, tt.viewer, nil)
+			if w.Code != http.StatusOK {
+				t.Fatalf("%s: GET %s: expected 200, got %d: %s", tt.name, path, w.Code, w.Body.String())
+			}
+
+			listed[path] = make(map[string]bool)
+			for _, item := range decodePage(t, w) {
+				id, _ := item["id"].(string)
+				listed[path][id] = true
+			}
+		}
+
+		for _, visibility := range visibilities {
+			want := tt.want[visibility]
+
+			t.Run(tt.name+"/"+string(visibility), func(t *testing.T) {
+				postPath := "/api/v1/posts/" + postIDs[visibility]
+				commentPath := "/api/v1/comments/" + commentIDs[visibility]
+				newComment := map[string]string{"content": "Another comment"}
+
+				routes := []struct {
+					method, path string
+					body         any
+					status       int
+				}{
+					{http.MethodGet, postPath, nil, http.StatusOK},
+					{http.MethodGet, postPath + "/comments", nil, http.StatusOK},
+					{http.MethodGet, postPath + "/reactions", nil, http.StatusOK},
+					{http.MethodGet, commentPath, nil, http.StatusOK},
+					{http.MethodGet, commentPath + "/replies", nil, http.StatusOK},
+					{http.MethodGet, commentPath + "/reactions", nil, http.StatusOK},
+					{http.MethodPost, postPath + "/comments", newComment, http.StatusCreated},
+					{http.MethodPost, commentPath + "/replies", newComment, http.StatusCreated},
+					{http.MethodPut, postPath + "/reactions/like", nil, http.StatusOK},
+					{http.MethodDelete, postPath + "/reactions/like", nil, http.StatusOK},
+					{http.MethodPut, commentPath + "/reactions/like", nil, http.StatusOK},
+					{http.MethodDelete, commentPath + "/reactions/like", nil, http.StatusOK},
+				}
+
+				for _, route := range routes {
+					wantStatus := route.status
+					switch {
+					case route.method != http.MethodGet && tt.viewer == nil:
+						wantStatus = http.StatusUnauthorized
+					case !want.get:
+						wantStatus = http.StatusNotFound
+					}
+
+					w := serve(t, router, route.method, route.path, tt.viewer, route.body)
+					if w.Code != wantStatus {
+						t.Errorf("%s %s: expected %d, got %d: %s", route.method, route.path, wantStatus, w.Code, w.Body.String())
+					}
+				}
+
+				for path, ids := range listed {
+					if got := ids[postIDs[visibility]]; got != want.listed {
+						t.Errorf("GET %s: listed = %t, want %t", path, got, want.listed)
+					}
+				}
+			})
+		}
+	}
+}
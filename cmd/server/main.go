@@ -3,8 +3,10 @@ package main
 import (
 	"fmt"
 	"gopher-social-backend-server/cmd/server/api"
+	"gopher-social-backend-server/cmd/server/api/services/search"
 	"gopher-social-backend-server/internal/database"
 	"gopher-social-backend-server/pkg/logger"
+	"gopher-social-backend-server/pkg/mailer"
 	"gopher-social-backend-server/pkg/utils"
 
 	"go.uber.org/zap"
@@ -25,14 +27,33 @@ func main() {
 		log.Error("failed to connect to the database", zap.Error(err))
 	}
 
+	if err := search.ValidateLanguage(postgresDB, search.SEARCH_LANGUAGE); err != nil {
+		log.Error("invalid search language", zap.String("language", search.SEARCH_LANGUAGE), zap.Error(err))
+		return
+	}
+
+	emailMailer, err := mailer.NewMailer()
+	if err != nil {
+		log.Error("failed to configure the mailer", zap.Error(err))
+		return
+	}
+
+	emailTemplates, err := mailer.NewRegistry()
+	if err != nil {
+		log.Error("failed to load the email templates", zap.Error(err))
+		return
+	}
+
 	store := api.NewStore(postgresDB)
-	handlers := api.NewHandlers(store)
+	handlers := api.NewHandlers(store, emailTemplates)
 
 	app := &api.Application{
-		Config:     appConfig,
-		Store:      store,
-		Handlers:   handlers,
-		PostgresDB: postgresDB,
+		Config:         appConfig,
+		Store:          store,
+		Handlers:       handlers,
+		PostgresDB:     postgresDB,
+		Mailer:         emailMailer,
+		EmailTemplates: emailTemplates,
 	}
 
 	app.Run()
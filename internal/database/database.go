@@ -17,26 +17,28 @@ var (
 	once       sync.Once
 )
 
+func ConnectionString() string {
+	POSTGRES_HOST := utils.GetEnvAsString("POSTGRES_HOST", "postgres")
+	POSTGRES_PORT := utils.GetEnvAsInt("POSTGRES_PORT", 5432)
+	POSTGRES_USER := utils.GetEnvAsString("POSTGRES_USER", "postgres")
+	POSTGRES_PASSWORD := utils.GetEnvAsString("POSTGRES_PASSWORD", "postgres")
+	POSTGRES_DB := utils.GetEnvAsString("POSTGRES_DB", "gopher_social")
+
+	return fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%d sslmode=disable TimeZone=UTC",
+		POSTGRES_HOST, POSTGRES_USER, POSTGRES_PASSWORD, POSTGRES_DB, POSTGRES_PORT,
+	)
+}
+
 func NewPostgresDB() (*gorm.DB, error) {
 	var err error
 
 	once.Do(func() {
-		POSTGRES_HOST := utils.GetEnvAsString("POSTGRES_HOST", "postgres")
-		POSTGRES_PORT := utils.GetEnvAsInt("POSTGRES_PORT", 5432)
-		POSTGRES_USER := utils.GetEnvAsString("POSTGRES_USER", "postgres")
-		POSTGRES_PASSWORD := utils.GetEnvAsString("POSTGRES_PASSWORD", "postgres")
-		POSTGRES_DB := utils.GetEnvAsString("POSTGRES_DB", "gopher_social")
-
 		MAX_OPEN_CONNS := utils.GetEnvAsInt("MAX_OPEN_CONNS", 10)
 		MAX_IDLE_CONNS := utils.GetEnvAsInt("MAX_IDLE_CONNS", 5)
 		MAX_IDLE_TIME := utils.GetEnvAsDuration("MAX_IDLE_TIME", "15m")
 
-		connectionString := fmt.Sprintf(
-			"host=%s user=%s password=%s dbname=%s port=%d sslmode=disable TimeZone=UTC",
-			POSTGRES_HOST, POSTGRES_USER, POSTGRES_PASSWORD, POSTGRES_DB, POSTGRES_PORT,
-		)
-
-		PostgresDB, err = gorm.Open(postgres.Open(connectionString), &gorm.Config{})
+		PostgresDB, err = gorm.Open(postgres.Open(ConnectionString()), &gorm.Config{})
 		if err != nil {
 			log.Error("failed to connect to the database", zap.Error(err))
 			return
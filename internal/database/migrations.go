@@ -0,0 +1,64 @@
+package database
+
+import (
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const migrationsLockID = 72613901
+
+type Migration struct {
+	ID  string
+	SQL string
+}
+
+type schemaMigration struct {
+	ID        string `gorm:"type:varchar(255);primaryKey"`
+	AppliedAt int64  `gorm:"autoCreateTime"`
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+func RunMigrations(migrations ...Migration) error {
+	if err := PostgresDB.AutoMigrate(&schemaMigration{}); err != nil {
+		log.Error("failed to migrate schema migrations table", zap.Error(err))
+		return err
+	}
+
+	for _, migration := range migrations {
+		applied := false
+
+		err := PostgresDB.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationsLockID).Error; err != nil {
+				return err
+			}
+
+			var count int64
+			if err := tx.Model(&schemaMigration{}).Where("id = ?", migration.ID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return nil
+			}
+
+			if err := tx.Exec(migration.SQL).Error; err != nil {
+				return err
+			}
+
+			applied = true
+			return tx.Create(&schemaMigration{ID: migration.ID}).Error
+		})
+		if err != nil {
+			log.Error("failed to apply migration", zap.String("migration", migration.ID), zap.Error(err))
+			return err
+		}
+
+		if applied {
+			log.Info("migration applied successfully", zap.String("migration", migration.ID))
+		}
+	}
+
+	return nil
+}
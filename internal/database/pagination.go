@@ -0,0 +1,46 @@
+package database
+
+import (
+	"errors"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrInvalidOrderBy = errors.New("invalid orderby: column is not sortable")
+
+func Paginate(table string, limit, offset int, cursor *utils.Cursor, orderby string, desc bool) func(*gorm.DB) *gorm.DB {
+	return func(query *gorm.DB) *gorm.DB {
+		if !utils.IsSortable(constants.SortColumns[table], orderby) {
+			query.AddError(ErrInvalidOrderBy)
+			return query
+		}
+
+		column := clause.Column{Table: table, Name: orderby}
+		idColumn := clause.Column{Table: table, Name: "id"}
+
+		if cursor == nil {
+			return query.
+				Order(clause.OrderByColumn{Column: column, Desc: desc}).
+				Order(clause.OrderByColumn{Column: idColumn}).
+				Limit(limit + 1).Offset(offset)
+		}
+
+		descending := desc != cursor.Backward
+
+		if cursor.ID != "" {
+			comparison := "(?, ?) > (?, ?)"
+			if descending {
+				comparison = "(?, ?) < (?, ?)"
+			}
+			query = query.Where(comparison, column, idColumn, cursor.Value, cursor.ID)
+		}
+
+		return query.
+			Order(clause.OrderByColumn{Column: column, Desc: descending}).
+			Order(clause.OrderByColumn{Column: idColumn, Desc: descending}).
+			Limit(limit + 1)
+	}
+}
@@ -0,0 +1,66 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gopher-social-backend-server/pkg/constants"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Handler func(ctx context.Context, event OutboxEvent) error
+
+type subscription struct {
+	name    string
+	handler Handler
+}
+
+type Bus struct {
+	subscriptions map[constants.DomainEventType][]subscription
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		subscriptions: make(map[constants.DomainEventType][]subscription),
+	}
+}
+
+func (b *Bus) Subscribe(eventType constants.DomainEventType, name string, handler Handler) {
+	b.subscriptions[eventType] = append(b.subscriptions[eventType], subscription{name: name, handler: handler})
+}
+
+func (b *Bus) Dispatch(ctx context.Context, event *OutboxEvent) error {
+	var errs []error
+	for _, subscription := range b.subscriptions[event.Type] {
+		if slices.Contains(event.Delivered, subscription.name) {
+			continue
+		}
+
+		if err := subscription.handler(ctx, *event); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", subscription.name, err))
+			continue
+		}
+
+		event.Delivered = append(event.Delivered, subscription.name)
+	}
+	return errors.Join(errs...)
+}
+
+func Record(tx *gorm.DB, eventType constants.DomainEventType, payload any) error {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(&OutboxEvent{
+		Type:          eventType,
+		Payload:       string(encoded),
+		Status:        constants.OutboxPending,
+		NextAttemptAt: time.Now().Unix(),
+		Delivered:     []string{},
+	}).Error
+}
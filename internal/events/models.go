@@ -0,0 +1,50 @@
+package events
+
+import (
+	"encoding/json"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OutboxEvent struct {
+	ID            uuid.UUID                 `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Type          constants.DomainEventType `json:"type" gorm:"type:varchar(64);not null"`
+	Payload       string                    `json:"payload" gorm:"type:jsonb;not null"`
+	Status        constants.OutboxStatus    `json:"status" gorm:"type:varchar(16);not null;default:'pending';index:idx_outbox_events_status_next"`
+	Attempts      int                       `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt int64                     `json:"next_attempt_at" gorm:"not null;index:idx_outbox_events_status_next"`
+	Delivered     []string                  `json:"delivered" gorm:"type:jsonb;not null;default:'[]';serializer:json"`
+	LastError     string                    `json:"last_error" gorm:"type:text;not null;default:''"`
+	ProcessedAt   *int64                    `json:"processed_at"`
+	CreatedAt     int64                     `json:"created_at" gorm:"autoCreateTime;index"`
+	UpdatedAt     int64                     `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (OutboxEvent) TableName() string {
+	return "outbox_events"
+}
+
+func (e OutboxEvent) Decode(payload any) error {
+	return json.Unmarshal([]byte(e.Payload), payload)
+}
+
+func (e *OutboxEvent) Record(err error, now time.Time, maxAttempts int, backoffBase, backoffMax time.Duration) {
+	e.Attempts++
+
+	switch {
+	case err == nil:
+		processedAt := now.Unix()
+		e.Status = constants.OutboxProcessed
+		e.ProcessedAt = &processedAt
+		e.LastError = ""
+	case e.Attempts >= maxAttempts:
+		e.Status = constants.OutboxFailed
+		e.LastError = err.Error()
+	default:
+		e.LastError = err.Error()
+		e.NextAttemptAt = now.Add(utils.Backoff(e.Attempts, backoffBase, backoffMax)).Unix()
+	}
+}
@@ -0,0 +1,67 @@
+package events
+
+import (
+	"gopher-social-backend-server/pkg/constants"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxStore interface {
+	ClaimDueEvents(now time.Time, lease time.Duration, limit int) ([]OutboxEvent, error)
+	SaveEvent(event *OutboxEvent) error
+	PurgeEvents(before time.Time) (int64, error)
+}
+
+type outboxStore struct {
+	postgresDB *gorm.DB
+}
+
+func NewOutboxStore(postgresDB *gorm.DB) OutboxStore {
+	return &outboxStore{
+		postgresDB: postgresDB,
+	}
+}
+
+func (s *outboxStore) ClaimDueEvents(now time.Time, lease time.Duration, limit int) ([]OutboxEvent, error) {
+	var events []OutboxEvent
+
+	err := s.postgresDB.Transaction(func(tx *gorm.DB) error {
+		var eventIDs []uuid.UUID
+		if err := tx.Model(&OutboxEvent{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", constants.OutboxPending, now.Unix()).
+			Order("next_attempt_at").Order("created_at").Limit(limit).
+			Pluck("id", &eventIDs).Error; err != nil {
+			return err
+		}
+
+		if len(eventIDs) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&OutboxEvent{}).Where("id IN ?", eventIDs).
+			UpdateColumn("next_attempt_at", now.Add(lease).Unix()).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("id IN ?", eventIDs).Order("created_at").Order("id").Find(&events).Error
+	})
+
+	return events, err
+}
+
+func (s *outboxStore) SaveEvent(event *OutboxEvent) error {
+	return s.postgresDB.Model(event).
+		Select("status", "attempts", "next_attempt_at", "delivered", "last_error", "processed_at", "updated_at").
+		Updates(event).Error
+}
+
+func (s *outboxStore) PurgeEvents(before time.Time) (int64, error) {
+	result := s.postgresDB.
+		Where("status = ? AND processed_at < ?", constants.OutboxProcessed, before.Unix()).
+		Delete(&OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"gopher-social-backend-server/pkg/constants"
+
+	"github.com/google/uuid"
+)
+
+type UserRegistered struct {
+	UserID uuid.UUID               `json:"user_id"`
+	Email  string                  `json:"email"`
+	OAuth  constants.OAuthProvider `json:"oauth"`
+}
+
+type UserActivated struct {
+	UserID uuid.UUID `json:"user_id"`
+	Email  string    `json:"email"`
+}
+
+type PasswordResetRequested struct {
+	UserID uuid.UUID `json:"user_id"`
+	Email  string    `json:"email"`
+}
+
+type PasswordChanged struct {
+	UserID uuid.UUID `json:"user_id"`
+	Email  string    `json:"email"`
+}
+
+type UserFollowed struct {
+	FollowerID uuid.UUID `json:"follower_id"`
+	FolloweeID uuid.UUID `json:"followee_id"`
+}
+
+type PostCreated struct {
+	PostID   uuid.UUID `json:"post_id"`
+	AuthorID uuid.UUID `json:"author_id"`
+}
+
+type CommentCreated struct {
+	CommentID uuid.UUID  `json:"comment_id"`
+	PostID    uuid.UUID  `json:"post_id"`
+	ParentID  *uuid.UUID `json:"parent_id"`
+	AuthorID  uuid.UUID  `json:"author_id"`
+}
+
+type ReactionChanged struct {
+	UserID    uuid.UUID              `json:"user_id"`
+	PostID    uuid.UUID              `json:"post_id"`
+	CommentID *uuid.UUID             `json:"comment_id"`
+	Type      constants.ReactionType `json:"type"`
+	Previous  constants.ReactionType `json:"previous"`
+}
+
+type UsersMentioned struct {
+	ActorID   uuid.UUID   `json:"actor_id"`
+	PostID    uuid.UUID   `json:"post_id"`
+	CommentID *uuid.UUID  `json:"comment_id"`
+	UserIDs   []uuid.UUID `json:"user_ids"`
+}
@@ -0,0 +1,57 @@
+package hooks
+
+import (
+	"gopher-social-backend-server/pkg/constants"
+
+	"github.com/google/uuid"
+)
+
+type Owner struct {
+	ID uuid.UUID `gorm:"type:uuid;primaryKey"`
+}
+
+func (Owner) TableName() string {
+	return "users"
+}
+
+type Webhook struct {
+	ID        uuid.UUID                    `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	OwnerID   *uuid.UUID                   `json:"owner_id" gorm:"type:uuid;index"`
+	Owner     *Owner                       `json:"-" gorm:"foreignKey:OwnerID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	URL       string                       `json:"url" gorm:"type:varchar(2048);not null"`
+	Secret    string                       `json:"-" gorm:"type:varchar(128);not null"`
+	Events    []constants.WebhookEventType `json:"events" gorm:"type:jsonb;not null;default:'[]';serializer:json"`
+	Active    bool                         `json:"active" gorm:"not null;default:true"`
+	CreatedAt int64                        `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt int64                        `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+type Delivery struct {
+	ID             uuid.UUID                       `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	WebhookID      uuid.UUID                       `json:"webhook_id" gorm:"type:uuid;not null;index:idx_webhook_deliveries_webhook_created"`
+	Webhook        Webhook                         `json:"-" gorm:"foreignKey:WebhookID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE"`
+	EventID        uuid.UUID                       `json:"event_id" gorm:"type:uuid;not null;index"`
+	Event          constants.WebhookEventType      `json:"event" gorm:"type:varchar(64);not null"`
+	Payload        string                          `json:"payload" gorm:"type:jsonb;not null"`
+	Status         constants.WebhookDeliveryStatus `json:"status" gorm:"type:varchar(16);not null;default:'pending';index:idx_webhook_deliveries_status_next"`
+	Attempts       int                             `json:"attempts" gorm:"not null;default:0"`
+	NextAttemptAt  int64                           `json:"next_attempt_at" gorm:"not null;index:idx_webhook_deliveries_status_next"`
+	LastAttemptAt  *int64                          `json:"last_attempt_at"`
+	ResponseStatus *int                            `json:"response_status"`
+	ResponseBody   string                          `json:"response_body" gorm:"type:text;not null;default:''"`
+	Error          string                          `json:"error" gorm:"type:text;not null;default:''"`
+	RedeliveryOf   *uuid.UUID                      `json:"redelivery_of" gorm:"type:uuid"`
+	CreatedAt      int64                           `json:"created_at" gorm:"autoCreateTime;index:idx_webhook_deliveries_webhook_created"`
+	UpdatedAt      int64                           `json:"updated_at" gorm:"autoUpdateTime;index"`
+}
+
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+type Payload struct {
+	ID        uuid.UUID                  `json:"id"`
+	Type      constants.WebhookEventType `json:"type"`
+	CreatedAt int64                      `json:"created_at"`
+	Data      any                        `json:"data"`
+}
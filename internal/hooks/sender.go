@@ -0,0 +1,108 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/utils"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+type Result struct {
+	StatusCode int
+	Body       string
+	Err        error
+}
+
+func (r Result) Succeeded() bool {
+	return r.Err == nil && r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+type Sender struct {
+	Client *http.Client
+}
+
+func NewSender(timeout time.Duration) *Sender {
+	dialer := &net.Dialer{Timeout: timeout, Control: controlDial}
+
+	return &Sender{
+		Client: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				TLSHandshakeTimeout: timeout,
+				ForceAttemptHTTP2:   true,
+				MaxIdleConns:        100,
+				IdleConnTimeout:     90 * time.Second,
+			},
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (s *Sender) Send(ctx context.Context, delivery Delivery) Result {
+	body := []byte(delivery.Payload)
+	timestamp := time.Now().Unix()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return Result{Err: err}
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("User-Agent", "gopher-social-webhooks")
+	request.Header.Set("X-Webhook-ID", delivery.ID.String())
+	request.Header.Set("X-Webhook-Event", string(delivery.Event))
+	request.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	request.Header.Set("X-Webhook-Signature", Sign(delivery.Webhook.Secret, timestamp, body))
+
+	response, err := s.Client.Do(request)
+	if err != nil {
+		return Result{Err: err}
+	}
+	defer response.Body.Close()
+
+	responseBody, _ := io.ReadAll(io.LimitReader(response.Body, constants.MaxWebhookResponseBody))
+	responseBody = bytes.ToValidUTF8(bytes.ReplaceAll(responseBody, []byte{0}, nil), []byte("\uFFFD"))
+	result := Result{StatusCode: response.StatusCode, Body: string(responseBody)}
+	if !result.Succeeded() {
+		result.Err = fmt.Errorf("unexpected status code: %d", response.StatusCode)
+	}
+	return result
+}
+
+func (d *Delivery) Record(result Result, now time.Time, maxAttempts int, backoffBase, backoffMax time.Duration) {
+	attemptedAt := now.Unix()
+	d.Attempts++
+	d.LastAttemptAt = &attemptedAt
+	d.Error = ""
+
+	d.ResponseBody = ""
+	if d.Webhook.OwnerID == nil {
+		d.ResponseBody = result.Body
+	}
+
+	d.ResponseStatus = nil
+	if result.StatusCode != 0 {
+		statusCode := result.StatusCode
+		d.ResponseStatus = &statusCode
+	}
+
+	switch {
+	case result.Succeeded():
+		d.Status = constants.WebhookDeliverySucceeded
+	case d.Attempts >= maxAttempts:
+		d.Status = constants.WebhookDeliveryFailed
+		d.Error = result.Err.Error()
+	default:
+		d.Error = result.Err.Error()
+		d.NextAttemptAt = now.Add(utils.Backoff(d.Attempts, backoffBase, backoffMax)).Unix()
+	}
+}
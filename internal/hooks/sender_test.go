@@ -0,0 +1,256 @@
+package hooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"gopher-social-backend-server/pkg/constants"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const testSecret = "whsec_test"
+
+func newTestDelivery(url string) Delivery {
+	return Delivery{
+		ID:      uuid.New(),
+		Event:   constants.WebhookEventPostCreated,
+		Payload: `{"id":"1","type":"post.created"}`,
+		Status:  constants.WebhookDeliveryPending,
+		Webhook: Webhook{URL: url, Secret: testSecret},
+	}
+}
+
+// newTestSender talks to httptest servers on loopback, which NewSender
+// refuses to dial.
+func newTestSender(server *httptest.Server) *Sender {
+	client := server.Client()
+	client.Timeout = time.Second
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &Sender{Client: client}
+}
+
+func TestSendSignsTimestampAndBody(t *testing.T) {
+	var (
+		headers http.Header
+		body    []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	delivery := newTestDelivery(server.URL)
+	result := newTestSender(server).Send(context.Background(), delivery)
+	if !result.Succeeded() {
+		t.Fatalf("expected success, got %+v", result)
+	}
+
+	if string(body) != delivery.Payload {
+		t.Fatalf("unexpected body: %s", body)
+	}
+	if headers.Get("X-Webhook-ID") != delivery.ID.String() || headers.Get("X-Webhook-Event") != string(delivery.Event) {
+		t.Fatalf("unexpected delivery headers: %v", headers)
+	}
+
+	timestamp, err := strconv.ParseInt(headers.Get("X-Webhook-Timestamp"), 10, 64)
+	if err != nil {
+		t.Fatalf("invalid timestamp header: %v", err)
+	}
+
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "." + string(body)))
+	signature := headers.Get("X-Webhook-Signature")
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); signature != want {
+		t.Fatalf("signature = %s, want %s", signature, want)
+	}
+
+	if !Verify(testSecret, signature, timestamp, body, time.Minute, time.Now()) {
+		t.Fatal("expected the signature to verify")
+	}
+	if Verify(testSecret, signature, timestamp, append(body, ' '), time.Minute, time.Now()) {
+		t.Fatal("expected a tampered body to fail verification")
+	}
+	if Verify(testSecret, signature, timestamp+1, body, time.Minute, time.Now()) {
+		t.Fatal("expected a different timestamp to fail verification")
+	}
+	if Verify(testSecret, signature, timestamp, body, time.Minute, time.Now().Add(time.Hour)) {
+		t.Fatal("expected an old timestamp to fail verification")
+	}
+}
+
+func TestSendCountsNon2xxAsFailure(t *testing.T) {
+	tests := []struct {
+		status  int
+		succeed bool
+	}{
+		{status: http.StatusOK, succeed: true},
+		{status: http.StatusNoContent, succeed: true},
+		{status: http.StatusFound, succeed: false},
+		{status: http.StatusBadRequest, succeed: false},
+		{status: http.StatusGone, succeed: false},
+		{status: http.StatusInternalServerError, succeed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.status), func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.status == http.StatusFound {
+					w.Header().Set("Location", "/elsewhere")
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			result := newTestSender(server).Send(context.Background(), newTestDelivery(server.URL))
+			if result.StatusCode != tt.status {
+				t.Fatalf("status = %d, want %d", result.StatusCode, tt.status)
+			}
+			if result.Succeeded() != tt.succeed {
+				t.Fatalf("succeeded = %t, want %t", result.Succeeded(), tt.succeed)
+			}
+			if !tt.succeed && result.Err == nil {
+				t.Fatal("expected an error for a failed delivery")
+			}
+		})
+	}
+}
+
+func TestRetriesFollowBackoff(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sender := newTestSender(server)
+	base, maxBackoff := 30*time.Second, 2*time.Minute
+	now := time.Unix(1_700_000_000, 0)
+
+	t.Run("retries then fails", func(t *testing.T) {
+		delivery := newTestDelivery(server.URL)
+		wantDelays := []time.Duration{30 * time.Second, time.Minute, 2 * time.Minute, 2 * time.Minute}
+
+		for attempt, wantDelay := range wantDelays {
+			delivery.Record(sender.Send(context.Background(), delivery), now, len(wantDelays)+1, base, maxBackoff)
+
+			if delivery.Attempts != attempt+1 {
+				t.Fatalf("attempts = %d, want %d", delivery.Attempts, attempt+1)
+			}
+			if delivery.Status != constants.WebhookDeliveryPending {
+				t.Fatalf("attempt %d: status = %s, want pending", attempt+1, delivery.Status)
+			}
+			if got := time.Unix(delivery.NextAttemptAt, 0).Sub(now); got != wantDelay {
+				t.Fatalf("attempt %d: next attempt in %s, want %s", attempt+1, got, wantDelay)
+			}
+			if delivery.ResponseStatus == nil || *delivery.ResponseStatus != http.StatusServiceUnavailable || delivery.Error == "" {
+				t.Fatalf("attempt %d: expected the 503 to be recorded, got %+v", attempt+1, delivery)
+			}
+		}
+
+		delivery.Record(sender.Send(context.Background(), delivery), now, len(wantDelays)+1, base, maxBackoff)
+		if delivery.Status != constants.WebhookDeliveryFailed {
+			t.Fatalf("status = %s, want failed after the last attempt", delivery.Status)
+		}
+	})
+
+	t.Run("succeeds after a retry", func(t *testing.T) {
+		failing.Store(true)
+		delivery := newTestDelivery(server.URL)
+		delivery.Record(sender.Send(context.Background(), delivery), now, 5, base, maxBackoff)
+
+		failing.Store(false)
+		delivery.Record(sender.Send(context.Background(), delivery), now, 5, base, maxBackoff)
+		if delivery.Status != constants.WebhookDeliverySucceeded || delivery.Attempts != 2 || delivery.Error != "" {
+			t.Fatalf("expected success on the second attempt, got %+v", delivery)
+		}
+	})
+}
+
+func TestRecordKeepsResponseBodiesForGlobalWebhooksOnly(t *testing.T) {
+	ownerID := uuid.New()
+	result := Result{StatusCode: http.StatusInternalServerError, Body: "internal details", Err: errors.New("unexpected status code: 500")}
+
+	global := Delivery{Webhook: Webhook{}}
+	global.Record(result, time.Now(), 3, time.Second, time.Minute)
+	if global.ResponseBody != result.Body {
+		t.Fatalf("expected the global webhook to keep the response body, got %q", global.ResponseBody)
+	}
+
+	owned := Delivery{Webhook: Webhook{OwnerID: &ownerID}}
+	owned.Record(result, time.Now(), 3, time.Second, time.Minute)
+	if owned.ResponseBody != "" {
+		t.Fatalf("expected a user webhook to drop the response body, got %q", owned.ResponseBody)
+	}
+}
+
+func TestSenderRefusesInternalAddresses(t *testing.T) {
+	var hits atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer server.Close()
+
+	result := NewSender(time.Second).Send(context.Background(), newTestDelivery(server.URL))
+	if !errors.Is(result.Err, ErrForbiddenAddress) {
+		t.Fatalf("expected ErrForbiddenAddress, got %v", result.Err)
+	}
+	if hits.Load() != 0 {
+		t.Fatal("expected the request to be refused before connecting")
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	tests := []struct {
+		url   string
+		valid bool
+	}{
+		{url: "https://example.com/hooks", valid: true},
+		{url: "http://hooks.example.org:8080/in", valid: true},
+		{url: "https://93.184.216.34/hooks", valid: true},
+		{url: "ftp://example.com/hooks", valid: false},
+		{url: "http://localhost/hooks", valid: false},
+		{url: "http://api.localhost/hooks", valid: false},
+		{url: "http://intranet/hooks", valid: false},
+		{url: "http://metadata.google.internal/computeMetadata", valid: false},
+		{url: "http://printer.local/hooks", valid: false},
+		{url: "http://127.0.0.1/hooks", valid: false},
+		{url: "http://10.0.0.5/hooks", valid: false},
+		{url: "http://172.16.0.1/hooks", valid: false},
+		{url: "http://192.168.1.1/hooks", valid: false},
+		{url: "http://169.254.169.254/latest/meta-data", valid: false},
+		{url: "http://100.64.0.1/hooks", valid: false},
+		{url: "http://0.0.0.0/hooks", valid: false},
+		{url: "http://[::1]/hooks", valid: false},
+		{url: "http://[fd00::1]/hooks", valid: false},
+		{url: "http://[fe80::1]/hooks", valid: false},
+		{url: "http://[::ffff:127.0.0.1]/hooks", valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			err := ValidateURL(tt.url)
+			if (err == nil) != tt.valid {
+				t.Fatalf("ValidateURL(%q) = %v, want valid = %t", tt.url, err, tt.valid)
+			}
+		})
+	}
+}
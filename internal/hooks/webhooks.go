@@ -0,0 +1,90 @@
+package hooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"gopher-social-backend-server/pkg/constants"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func GenerateSecret() (string, error) {
+	secret := make([]byte, constants.WebhookSecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(secret), nil
+}
+
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func Verify(secret, signature string, timestamp int64, body []byte, tolerance time.Duration, now time.Time) bool {
+	age := now.Sub(time.Unix(timestamp, 0))
+	if age > tolerance || age < -tolerance {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body)))
+}
+
+func NewDelivery(webhookID uuid.UUID, payload Payload) (Delivery, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	return Delivery{
+		WebhookID:     webhookID,
+		EventID:       payload.ID,
+		Event:         payload.Type,
+		Payload:       string(encoded),
+		Status:        constants.WebhookDeliveryPending,
+		NextAttemptAt: time.Now().Unix(),
+	}, nil
+}
+
+func Enqueue(tx *gorm.DB, eventID uuid.UUID, eventType constants.WebhookEventType, ownerIDs []uuid.UUID, data any) error {
+	query := tx.Model(&Webhook{}).Where("active AND events @> ?::jsonb", fmt.Sprintf("[%q]", eventType))
+	if len(ownerIDs) > 0 {
+		query = query.Where("owner_id IS NULL OR owner_id IN ?", ownerIDs)
+	} else {
+		query = query.Where("owner_id IS NULL")
+	}
+
+	var webhookIDs []uuid.UUID
+	if err := query.Pluck("id", &webhookIDs).Error; err != nil {
+		return err
+	}
+
+	if len(webhookIDs) == 0 {
+		return nil
+	}
+
+	payload := Payload{ID: eventID, Type: eventType, CreatedAt: time.Now().Unix(), Data: data}
+	deliveries := make([]Delivery, 0, len(webhookIDs))
+	for _, webhookID := range webhookIDs {
+		delivery, err := NewDelivery(webhookID, payload)
+		if err != nil {
+			return err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return tx.Clauses(clause.OnConflict{
+		Columns:     []clause.Column{{Name: "webhook_id"}, {Name: "event_id"}},
+		TargetWhere: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: "redelivery_of IS NULL"}}},
+		DoNothing:   true,
+	}).Create(&deliveries).Error
+}
@@ -52,3 +52,22 @@ func AuthMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+func OptionalAuthMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("AuthToken")
+		if err != nil || !utils.ValidateAccessToken(cookie.Value) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		claims, err := utils.ParseAccessToken(cookie.Value)
+		if err != nil || claims.Subject == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), constants.UserIDKey, claims.Subject)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
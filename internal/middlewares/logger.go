@@ -1,7 +1,9 @@
 package middlewares
 
 import (
+	"bufio"
 	"gopher-social-backend-server/pkg/logger"
+	"net"
 	"net/http"
 	"time"
 
@@ -37,3 +39,11 @@ type responseRecorder struct {
 	http.ResponseWriter
 	statusCode int
 }
+
+func (rec *responseRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+func (rec *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(rec.ResponseWriter).Hijack()
+}
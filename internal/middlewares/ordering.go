@@ -7,25 +7,27 @@ import (
 	"net/http"
 )
 
-func OrderingMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		orderby := utils.ParseOrderByQueryParam(r, string(constants.OrderByKey), constants.DefaultOrderBy)
-		desc := utils.ParseDescQueryParam(r, string(constants.DescKey), constants.DefaultDesc)
+func OrderingMiddleware(columns map[string]struct{}) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			orderby := utils.ParseOrderByQueryParam(r, string(constants.OrderByKey), constants.DefaultOrderBy)
+			desc := utils.ParseDescQueryParam(r, string(constants.DescKey), constants.DefaultDesc)
 
-		if utils.IsSQLInjection(orderby) {
-			utils.WriteError(w, http.StatusBadRequest, "invalid orderby: SQL injection detected")
-			return
-		}
+			if !utils.IsSortable(columns, orderby) {
+				utils.WriteError(w, http.StatusBadRequest, "invalid orderby: must be one of "+utils.SortableColumns(columns))
+				return
+			}
 
-		ctx := context.WithValue(r.Context(), constants.OrderByKey, orderby)
-		ctx = context.WithValue(ctx, constants.DescKey, desc)
-		r = r.WithContext(ctx)
+			ctx := context.WithValue(r.Context(), constants.OrderByKey, orderby)
+			ctx = context.WithValue(ctx, constants.DescKey, desc)
+			r = r.WithContext(ctx)
 
-		if desc != "true" && desc != "false" {
-			utils.WriteError(w, http.StatusBadRequest, "invalid desc: must be true or false")
-			return
-		}
+			if desc != "true" && desc != "false" {
+				utils.WriteError(w, http.StatusBadRequest, "invalid desc: must be true or false")
+				return
+			}
 
-		next.ServeHTTP(w, r)
-	})
+			next.ServeHTTP(w, r)
+		})
+	}
 }
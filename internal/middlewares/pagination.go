@@ -5,6 +5,7 @@ import (
 	"gopher-social-backend-server/pkg/constants"
 	"gopher-social-backend-server/pkg/utils"
 	"net/http"
+	"strconv"
 )
 
 func PaginationMiddleware(next http.Handler) http.Handler {
@@ -21,8 +22,18 @@ func PaginationMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
+		includeTotal := false
+		if includeTotalParam := r.URL.Query().Get(string(constants.IncludeTotalKey)); includeTotalParam != "" {
+			includeTotal, err = strconv.ParseBool(includeTotalParam)
+			if err != nil {
+				utils.WriteError(w, http.StatusBadRequest, "invalid include_total: must be true or false")
+				return
+			}
+		}
+
 		ctx := context.WithValue(r.Context(), constants.LimitKey, limit)
 		ctx = context.WithValue(ctx, constants.OffsetKey, offset)
+		ctx = context.WithValue(ctx, constants.IncludeTotalKey, includeTotal)
 		r = r.WithContext(ctx)
 
 		limitFromContext := r.Context().Value(constants.LimitKey)
@@ -46,3 +57,46 @@ func PaginationMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+func CursorPaginationMiddleware(next http.Handler) http.Handler {
+	return PaginationMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mode := r.URL.Query().Get(string(constants.ModeKey))
+		if mode == "" {
+			mode = constants.PaginationModeOffset
+		}
+
+		token := r.URL.Query().Get(string(constants.CursorKey))
+		if token != "" {
+			mode = constants.PaginationModeCursor
+		}
+
+		if mode != constants.PaginationModeOffset && mode != constants.PaginationModeCursor {
+			utils.WriteError(w, http.StatusBadRequest, "invalid pagination: must be offset or cursor")
+			return
+		}
+
+		var cursor *utils.Cursor
+		if mode == constants.PaginationModeCursor {
+			if r.URL.Query().Get(string(constants.OffsetKey)) != "" {
+				utils.WriteError(w, http.StatusBadRequest, "invalid offset: cannot be combined with cursor pagination")
+				return
+			}
+
+			cursor = &utils.Cursor{}
+			if token != "" {
+				decodedCursor, err := utils.DecodeCursor(token)
+				if err != nil {
+					utils.WriteError(w, http.StatusBadRequest, err.Error())
+					return
+				}
+				cursor = decodedCursor
+			}
+		}
+
+		ctx := context.WithValue(r.Context(), constants.ModeKey, mode)
+		ctx = context.WithValue(ctx, constants.CursorKey, cursor)
+		r = r.WithContext(ctx)
+
+		next.ServeHTTP(w, r)
+	}))
+}
@@ -0,0 +1,70 @@
+package pubsub
+
+import (
+	"encoding/json"
+	"gopher-social-backend-server/pkg/constants"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Event struct {
+	ID        int64                     `json:"id" gorm:"primaryKey;autoIncrement"`
+	Topic     string                    `json:"topic" gorm:"type:varchar(128);not null;index:idx_stream_events_topic_id"`
+	Type      constants.StreamEventType `json:"type" gorm:"type:varchar(64);not null"`
+	Data      string                    `json:"data" gorm:"type:jsonb;not null"`
+	CreatedAt int64                     `json:"created_at" gorm:"autoCreateTime;index"`
+}
+
+func (Event) TableName() string {
+	return "stream_events"
+}
+
+func UserTopic(userID uuid.UUID) string {
+	return "user:" + userID.String()
+}
+
+func PostTopic(postID uuid.UUID) string {
+	return "post:" + postID.String()
+}
+
+func TypingTopic(postID uuid.UUID) string {
+	return "typing:" + postID.String()
+}
+
+func notify(tx *gorm.DB, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if len(payload) > constants.MaxStreamNotificationPayload {
+		payload = []byte(strconv.FormatInt(event.ID, 10))
+	}
+
+	return tx.Exec("SELECT pg_notify(?, ?)", constants.StreamChannel, string(payload)).Error
+}
+
+func Announce(tx *gorm.DB, topic string, eventType constants.StreamEventType, data any) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return notify(tx, Event{Topic: topic, Type: eventType, Data: string(encoded), CreatedAt: time.Now().Unix()})
+}
+
+func Publish(tx *gorm.DB, topic string, eventType constants.StreamEventType, data any) error {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	event := Event{Topic: topic, Type: eventType, Data: string(encoded)}
+	if err := tx.Create(&event).Error; err != nil {
+		return err
+	}
+
+	return notify(tx, event)
+}
@@ -0,0 +1,116 @@
+package pubsub
+
+import "sync"
+
+type Subscriber struct {
+	topics map[string]struct{}
+	events chan Event
+	done   chan struct{}
+	once   sync.Once
+}
+
+func (s *Subscriber) Events() <-chan Event {
+	return s.events
+}
+
+func (s *Subscriber) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Subscriber) close() {
+	s.once.Do(func() {
+		close(s.done)
+	})
+}
+
+type Hub struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[*Subscriber]struct{}
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		subscribers: make(map[string]map[*Subscriber]struct{}),
+	}
+}
+
+func (h *Hub) Subscribe(topics []string, buffer int) *Subscriber {
+	subscriber := &Subscriber{
+		topics: make(map[string]struct{}, len(topics)),
+		events: make(chan Event, buffer),
+		done:   make(chan struct{}),
+	}
+
+	h.AddTopics(subscriber, topics...)
+
+	return subscriber
+}
+
+func (h *Hub) AddTopics(subscriber *Subscriber, topics ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, topic := range topics {
+		if h.subscribers[topic] == nil {
+			h.subscribers[topic] = make(map[*Subscriber]struct{})
+		}
+		h.subscribers[topic][subscriber] = struct{}{}
+		subscriber.topics[topic] = struct{}{}
+	}
+}
+
+func (h *Hub) RemoveTopics(subscriber *Subscriber, topics ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, topic := range topics {
+		delete(h.subscribers[topic], subscriber)
+		if len(h.subscribers[topic]) == 0 {
+			delete(h.subscribers, topic)
+		}
+		delete(subscriber.topics, topic)
+	}
+}
+
+func (h *Hub) TopicCount(subscriber *Subscriber) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(subscriber.topics)
+}
+
+func (h *Hub) HasTopic(subscriber *Subscriber, topic string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	_, ok := subscriber.topics[topic]
+	return ok
+}
+
+func (h *Hub) Unsubscribe(subscriber *Subscriber) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for topic := range subscriber.topics {
+		delete(h.subscribers[topic], subscriber)
+		if len(h.subscribers[topic]) == 0 {
+			delete(h.subscribers, topic)
+		}
+	}
+	subscriber.topics = make(map[string]struct{})
+
+	subscriber.close()
+}
+
+func (h *Hub) Broadcast(event Event) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for subscriber := range h.subscribers[event.Topic] {
+		select {
+		case subscriber.events <- event:
+		default:
+			subscriber.close()
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/logger"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+var log = logger.GetLogger()
+
+type EventSource interface {
+	GetEvent(eventID int64) (*Event, error)
+	GetEventsAfter(afterID int64, limit int) ([]Event, error)
+	GetLatestEventID() (int64, error)
+}
+
+func decodeNotification(payload string, source EventSource) (*Event, error) {
+	if !strings.HasPrefix(payload, "{") {
+		eventID, err := strconv.ParseInt(payload, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return source.GetEvent(eventID)
+	}
+
+	var event Event
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (h *Hub) listen(ctx context.Context, connString string, source EventSource, lastID *int64) error {
+	conn, err := pgx.Connect(ctx, connString)
+	if err != nil {
+		return err
+	}
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{constants.StreamChannel}.Sanitize()); err != nil {
+		return err
+	}
+
+	if *lastID == 0 {
+		if *lastID, err = source.GetLatestEventID(); err != nil {
+			return err
+		}
+	} else if err := h.replay(source, lastID); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		event, err := decodeNotification(notification.Payload, source)
+		if err != nil {
+			log.Warn("could not decode stream event", zap.String("payload", notification.Payload), zap.Error(err))
+			continue
+		}
+
+		h.Broadcast(*event)
+		*lastID = max(*lastID, event.ID)
+	}
+}
+
+func (h *Hub) replay(source EventSource, lastID *int64) error {
+	for {
+		missed, err := source.GetEventsAfter(*lastID, constants.StreamReplayLimit)
+		if err != nil {
+			return err
+		}
+
+		for _, event := range missed {
+			h.Broadcast(event)
+			*lastID = max(*lastID, event.ID)
+		}
+
+		if len(missed) < constants.StreamReplayLimit {
+			return nil
+		}
+	}
+}
+
+func (h *Hub) Listen(ctx context.Context, connString string, source EventSource, reconnectDelay time.Duration) {
+	var lastID int64
+
+	for {
+		err := h.listen(ctx, connString, source, &lastID)
+		if ctx.Err() != nil {
+			return
+		}
+
+		log.Warn("stream listener disconnected", zap.Duration("retry_in", reconnectDelay), zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package pubsub
+
+import (
+	"gopher-social-backend-server/pkg/constants"
+	"testing"
+)
+
+type fakeEventSource struct {
+	EventSource
+	events []Event
+}
+
+func (s *fakeEventSource) GetEventsAfter(afterID int64, limit int) ([]Event, error) {
+	var page []Event
+	for _, event := range s.events {
+		if event.ID > afterID && len(page) < limit {
+			page = append(page, event)
+		}
+	}
+	return page, nil
+}
+
+func TestReplayPagesThroughEveryMissedEvent(t *testing.T) {
+	total := 2*constants.StreamReplayLimit + 5
+
+	source := &fakeEventSource{}
+	for id := 1; id <= total; id++ {
+		source.events = append(source.events, Event{ID: int64(id), Topic: "topic"})
+	}
+
+	hub := NewHub()
+	subscriber := hub.Subscribe([]string{"topic"}, total)
+
+	lastID := int64(3)
+	if err := hub.replay(source, &lastID); err != nil {
+		t.Fatalf("could not replay: %v", err)
+	}
+
+	if lastID != int64(total) {
+		t.Fatalf("lastID = %d, want %d", lastID, total)
+	}
+
+	for want := int64(4); want <= int64(total); want++ {
+		select {
+		case event := <-subscriber.Events():
+			if event.ID != want {
+				t.Fatalf("received event %d, want %d", event.ID, want)
+			}
+		default:
+			t.Fatalf("missing event %d", want)
+		}
+	}
+}
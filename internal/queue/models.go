@@ -0,0 +1,79 @@
+package queue
+
+import (
+	"encoding/json"
+	"errors"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/utils"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Job struct {
+	ID          uuid.UUID           `json:"id" gorm:"type:uuid;primaryKey;default:uuid_generate_v4()"`
+	Kind        constants.JobKind   `json:"kind" gorm:"type:varchar(64);not null;index:idx_jobs_kind_status_run"`
+	Key         *string             `json:"key" gorm:"type:varchar(255);uniqueIndex"`
+	Payload     string              `json:"payload" gorm:"type:jsonb;not null"`
+	Status      constants.JobStatus `json:"status" gorm:"type:varchar(16);not null;default:'pending';index:idx_jobs_kind_status_run"`
+	Attempts    int                 `json:"attempts" gorm:"not null;default:0"`
+	RunAt       int64               `json:"run_at" gorm:"not null;index:idx_jobs_kind_status_run"`
+	LockedUntil *int64              `json:"locked_until"`
+	LastError   string              `json:"last_error" gorm:"type:text;not null;default:''"`
+	FinishedAt  *int64              `json:"finished_at"`
+	CreatedAt   int64               `json:"created_at" gorm:"autoCreateTime;index"`
+	UpdatedAt   int64               `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (Job) TableName() string {
+	return "jobs"
+}
+
+func (j Job) Decode(payload any) error {
+	return json.Unmarshal([]byte(j.Payload), payload)
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e permanentError) Unwrap() error {
+	return e.err
+}
+
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+func (j *Job) Record(err error, now time.Time, maxAttempts int, backoffBase, backoffMax time.Duration) {
+	j.LockedUntil = nil
+
+	if err == nil {
+		finishedAt := now.Unix()
+		j.Status = constants.JobSucceeded
+		j.FinishedAt = &finishedAt
+		j.LastError = ""
+		return
+	}
+
+	j.LastError = err.Error()
+	if len(j.LastError) > constants.MaxJobErrorLength {
+		j.LastError = strings.ToValidUTF8(j.LastError[:constants.MaxJobErrorLength], "")
+	}
+
+	var permanent permanentError
+	if errors.As(err, &permanent) || j.Attempts >= maxAttempts {
+		finishedAt := now.Unix()
+		j.Status = constants.JobDead
+		j.FinishedAt = &finishedAt
+		return
+	}
+
+	j.Status = constants.JobPending
+	j.RunAt = now.Add(utils.Backoff(j.Attempts, backoffBase, backoffMax)).Unix()
+}
@@ -0,0 +1,223 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/logger"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var log = logger.GetLogger()
+
+type Handler func(ctx context.Context, job Job) error
+
+func Handle[T any](handler func(ctx context.Context, payload T) error) Handler {
+	return func(ctx context.Context, job Job) error {
+		var payload T
+		if err := job.Decode(&payload); err != nil {
+			return Permanent(fmt.Errorf("could not decode payload: %w", err))
+		}
+		return handler(ctx, payload)
+	}
+}
+
+type Options struct {
+	Concurrency int
+	MaxAttempts int
+	Timeout     time.Duration
+}
+
+type Config struct {
+	PollInterval time.Duration
+	Lease        time.Duration
+	BackoffBase  time.Duration
+	BackoffMax   time.Duration
+	Defaults     Options
+}
+
+type worker struct {
+	kind    constants.JobKind
+	handler Handler
+	options Options
+}
+
+type schedule struct {
+	kind     constants.JobKind
+	schedule Schedule
+	next     time.Time
+}
+
+type Queue struct {
+	store     JobsStore
+	config    Config
+	workers   []*worker
+	schedules []*schedule
+}
+
+func NewQueue(store JobsStore, config Config) *Queue {
+	return &Queue{
+		store:  store,
+		config: config,
+	}
+}
+
+func (q *Queue) Register(kind constants.JobKind, handler Handler, options Options) {
+	if options.Concurrency <= 0 {
+		options.Concurrency = q.config.Defaults.Concurrency
+	}
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = q.config.Defaults.MaxAttempts
+	}
+	if options.Timeout <= 0 {
+		options.Timeout = q.config.Defaults.Timeout
+	}
+
+	q.workers = append(q.workers, &worker{kind: kind, handler: handler, options: options})
+}
+
+func (q *Queue) Schedule(kind constants.JobKind, when Schedule) {
+	q.schedules = append(q.schedules, &schedule{kind: kind, schedule: when})
+}
+
+func (q *Queue) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	for _, w := range q.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.work(ctx, w)
+		}()
+	}
+
+	if len(q.schedules) > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.runScheduler(ctx)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (q *Queue) enqueueScheduled(now time.Time) {
+	for _, s := range q.schedules {
+		next := s.schedule.Next(now)
+		if next.IsZero() || next.Equal(s.next) {
+			continue
+		}
+
+		job, err := newJob(s.kind, struct{}{}, next)
+		if err != nil {
+			log.Warn("could not create scheduled job", zap.String("kind", string(s.kind)), zap.Error(err))
+			continue
+		}
+		key := fmt.Sprintf("%s@%d", s.kind, next.Unix())
+		job.Key = &key
+
+		if err := q.store.EnqueueJob(job); err != nil {
+			log.Warn("could not enqueue scheduled job", zap.String("kind", string(s.kind)), zap.Error(err))
+			continue
+		}
+		s.next = next
+	}
+}
+
+func (q *Queue) runScheduler(ctx context.Context) {
+	ticker := time.NewTicker(q.config.PollInterval)
+	defer ticker.Stop()
+
+	q.enqueueScheduled(time.Now())
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			q.enqueueScheduled(time.Now())
+		}
+	}
+}
+
+func (q *Queue) work(ctx context.Context, w *worker) {
+	slots := make(chan struct{}, w.options.Concurrency)
+	var running sync.WaitGroup
+	defer running.Wait()
+
+	ticker := time.NewTicker(q.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		q.claim(ctx, w, slots, &running)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (q *Queue) claim(ctx context.Context, w *worker, slots chan struct{}, running *sync.WaitGroup) {
+	for ctx.Err() == nil {
+		free := cap(slots) - len(slots)
+		if free == 0 {
+			return
+		}
+
+		jobs, err := q.store.ClaimJobs(w.kind, time.Now(), q.config.Lease, free)
+		if err != nil {
+			log.Warn("could not claim jobs", zap.String("kind", string(w.kind)), zap.Error(err))
+			return
+		}
+
+		for i := range jobs {
+			slots <- struct{}{}
+			running.Add(1)
+			go func(job *Job) {
+				defer running.Done()
+				defer func() { <-slots }()
+				q.execute(ctx, w, job)
+			}(&jobs[i])
+		}
+
+		if len(jobs) < free {
+			return
+		}
+	}
+}
+
+func (q *Queue) call(ctx context.Context, w *worker, job Job) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("panic: %v", recovered)
+		}
+	}()
+
+	return w.handler(ctx, job)
+}
+
+func (q *Queue) execute(ctx context.Context, w *worker, job *Job) {
+	jobCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), w.options.Timeout)
+	defer cancel()
+
+	err := q.call(jobCtx, w, *job)
+
+	job.Record(err, time.Now(), w.options.MaxAttempts, q.config.BackoffBase, q.config.BackoffMax)
+	if err := q.store.SaveJob(job); err != nil {
+		log.Warn("could not save job", zap.String("job_id", job.ID.String()), zap.String("kind", string(job.Kind)), zap.Error(err))
+		return
+	}
+
+	switch {
+	case job.Status == constants.JobDead:
+		log.Warn("job moved to dead letter", zap.String("job_id", job.ID.String()), zap.String("kind", string(job.Kind)), zap.Int("attempts", job.Attempts), zap.String("error", job.LastError))
+	case err != nil:
+		log.Warn("job failed", zap.String("job_id", job.ID.String()), zap.String("kind", string(job.Kind)), zap.Int("attempts", job.Attempts), zap.Error(err))
+	}
+}
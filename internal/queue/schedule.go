@@ -0,0 +1,156 @@
+package queue
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Schedule interface {
+	Next(after time.Time) time.Time
+}
+
+type every time.Duration
+
+func Every(interval time.Duration) Schedule {
+	return every(interval)
+}
+
+func (e every) Next(after time.Time) time.Time {
+	interval := time.Duration(e)
+	return after.Truncate(interval).Add(interval)
+}
+
+type cron struct {
+	minutes, hours, days, months, weekdays uint64
+	anyDay, anyWeekday                     bool
+}
+
+var cronFields = []struct {
+	name     string
+	min, max int
+}{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 6},
+}
+
+func parseCronField(field string, min, max int) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			parsed, err := strconv.Atoi(stepPart)
+			if err != nil || parsed < 1 {
+				return 0, fmt.Errorf("invalid step %q", stepPart)
+			}
+			step = parsed
+		}
+
+		start, end := min, max
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+
+			parsed, err := strconv.Atoi(from)
+			if err != nil {
+				return 0, fmt.Errorf("invalid value %q", from)
+			}
+			start, end = parsed, parsed
+
+			if isRange {
+				parsed, err := strconv.Atoi(to)
+				if err != nil {
+					return 0, fmt.Errorf("invalid value %q", to)
+				}
+				end = parsed
+			} else if hasStep {
+				end = max
+			}
+		}
+
+		if start < min || end > max || start > end {
+			return 0, fmt.Errorf("value %q out of range %d-%d", rangePart, min, max)
+		}
+
+		for value := start; value <= end; value += step {
+			bits |= 1 << value
+		}
+	}
+
+	return bits, nil
+}
+
+func ParseCron(spec string) (Schedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("invalid cron spec %q: expected %d fields", spec, len(cronFields))
+	}
+
+	values := make([]uint64, len(fields))
+	for i, field := range fields {
+		bits, err := parseCronField(field, cronFields[i].min, cronFields[i].max)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron spec %q: %s: %w", spec, cronFields[i].name, err)
+		}
+		values[i] = bits
+	}
+
+	return cron{
+		minutes:    values[0],
+		hours:      values[1],
+		days:       values[2],
+		months:     values[3],
+		weekdays:   values[4],
+		anyDay:     strings.HasPrefix(fields[2], "*"),
+		anyWeekday: strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+func (c cron) matchesDay(t time.Time) bool {
+	dayMatches := c.days&(1<<t.Day()) != 0
+	weekdayMatches := c.weekdays&(1<<t.Weekday()) != 0
+
+	switch {
+	case c.anyDay && c.anyWeekday:
+		return true
+	case c.anyDay:
+		return weekdayMatches
+	case c.anyWeekday:
+		return dayMatches
+	default:
+		return dayMatches || weekdayMatches
+	}
+}
+
+func (c cron) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if c.months&(1<<t.Month()) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if !c.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.hours&(1<<t.Hour()) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+		if c.minutes&(1<<t.Minute()) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
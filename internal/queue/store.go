@@ -0,0 +1,178 @@
+package queue
+
+import (
+	"encoding/json"
+	"gopher-social-backend-server/pkg/constants"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type JobsStore interface {
+	EnqueueJob(job *Job) error
+	ClaimJobs(kind constants.JobKind, now time.Time, lease time.Duration, limit int) ([]Job, error)
+	SaveJob(job *Job) error
+	GetJobs(status constants.JobStatus, kind constants.JobKind, limit, offset int) ([]Job, error)
+	CountJobs(status constants.JobStatus, kind constants.JobKind) (int64, error)
+	GetJobByID(jobID uuid.UUID) (*Job, error)
+	RetryJob(jobID uuid.UUID, now time.Time) (*Job, error)
+	PurgeJobs(before time.Time) (int64, error)
+}
+
+type jobsStore struct {
+	postgresDB *gorm.DB
+}
+
+func NewJobsStore(postgresDB *gorm.DB) JobsStore {
+	return &jobsStore{
+		postgresDB: postgresDB,
+	}
+}
+
+func create(tx *gorm.DB, job *Job) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoNothing: true,
+	}).Create(job).Error
+}
+
+func newJob(kind constants.JobKind, payload any, runAt time.Time) (*Job, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Job{
+		Kind:    kind,
+		Payload: string(encoded),
+		Status:  constants.JobPending,
+		RunAt:   runAt.Unix(),
+	}, nil
+}
+
+func Enqueue(tx *gorm.DB, kind constants.JobKind, payload any, runAt time.Time) error {
+	job, err := newJob(kind, payload, runAt)
+	if err != nil {
+		return err
+	}
+
+	return create(tx, job)
+}
+
+func EnqueueUnique(tx *gorm.DB, key string, kind constants.JobKind, payload any, runAt time.Time) error {
+	job, err := newJob(kind, payload, runAt)
+	if err != nil {
+		return err
+	}
+	job.Key = &key
+
+	return create(tx, job)
+}
+
+func (s *jobsStore) EnqueueJob(job *Job) error {
+	return create(s.postgresDB, job)
+}
+
+func (s *jobsStore) ClaimJobs(kind constants.JobKind, now time.Time, lease time.Duration, limit int) ([]Job, error) {
+	var jobs []Job
+
+	err := s.postgresDB.Transaction(func(tx *gorm.DB) error {
+		var jobIDs []uuid.UUID
+		if err := tx.Model(&Job{}).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("kind = ?", kind).
+			Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_until <= ?)", constants.JobPending, now.Unix(), constants.JobRunning, now.Unix()).
+			Order("run_at").Order("created_at").Limit(limit).
+			Pluck("id", &jobIDs).Error; err != nil {
+			return err
+		}
+
+		if len(jobIDs) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&Job{}).Where("id IN ?", jobIDs).Updates(map[string]any{
+			"status":       constants.JobRunning,
+			"attempts":     gorm.Expr("attempts + 1"),
+			"locked_until": now.Add(lease).Unix(),
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("id IN ?", jobIDs).Order("run_at").Order("created_at").Find(&jobs).Error
+	})
+
+	return jobs, err
+}
+
+func (s *jobsStore) SaveJob(job *Job) error {
+	return s.postgresDB.Model(job).
+		Select("status", "attempts", "run_at", "locked_until", "last_error", "finished_at", "updated_at").
+		Updates(job).Error
+}
+
+func jobFilter(status constants.JobStatus, kind constants.JobKind) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if status != "" {
+			db = db.Where("status = ?", status)
+		}
+		if kind != "" {
+			db = db.Where("kind = ?", kind)
+		}
+		return db
+	}
+}
+
+func (s *jobsStore) GetJobs(status constants.JobStatus, kind constants.JobKind, limit, offset int) ([]Job, error) {
+	var jobs []Job
+	err := s.postgresDB.Scopes(jobFilter(status, kind)).
+		Order("created_at DESC").Order("id DESC").
+		Limit(limit).Offset(offset).
+		Find(&jobs).Error
+	return jobs, err
+}
+
+func (s *jobsStore) CountJobs(status constants.JobStatus, kind constants.JobKind) (int64, error) {
+	var count int64
+	err := s.postgresDB.Model(&Job{}).Scopes(jobFilter(status, kind)).Count(&count).Error
+	return count, err
+}
+
+func (s *jobsStore) GetJobByID(jobID uuid.UUID) (*Job, error) {
+	var job Job
+	if err := s.postgresDB.Where("id = ?", jobID).First(&job).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (s *jobsStore) RetryJob(jobID uuid.UUID, now time.Time) (*Job, error) {
+	var job Job
+
+	result := s.postgresDB.Model(&job).
+		Clauses(clause.Returning{}).
+		Where("id = ? AND status IN ?", jobID, []constants.JobStatus{constants.JobPending, constants.JobDead}).
+		Updates(map[string]any{
+			"status":      constants.JobPending,
+			"attempts":    0,
+			"run_at":      now.Unix(),
+			"finished_at": nil,
+		})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &job, nil
+}
+
+func (s *jobsStore) PurgeJobs(before time.Time) (int64, error) {
+	result := s.postgresDB.
+		Where("status IN ? AND finished_at < ?", []constants.JobStatus{constants.JobSucceeded, constants.JobDead}, before.Unix()).
+		Delete(&Job{})
+	return result.RowsAffected, result.Error
+}
@@ -30,8 +30,16 @@ if "%command%" == "migrate-down" (
     exit /b
 )
 
+if "%command%" == "repair-counters" (
+    echo Recomputing counters...
+    go run cmd/repair/main.go
+    echo Counters recomputed successfully!
+    exit /b
+)
+
 echo Invalid option. Use:
 echo   migrate-create [name] - to create a migration
 echo   migrate-up - to apply migrations
 echo   migrate-down - to revert migrations
+echo   repair-counters - to recompute like, dislike and comment counters
 exit /b 1
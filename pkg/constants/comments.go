@@ -0,0 +1,12 @@
+package constants
+
+const (
+	DefaultMaxCommentDepth    = 5
+	MaxCommentTreeSize        = 500
+	DeletedCommentPlaceholder = "[deleted]"
+)
+
+const (
+	CommentTreeFormatNested = "nested"
+	CommentTreeFormatFlat   = "flat"
+)
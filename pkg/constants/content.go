@@ -0,0 +1,16 @@
+package constants
+
+type ContentFormat string
+
+const (
+	ContentFormatPlain    ContentFormat = "plain"
+	ContentFormatMarkdown ContentFormat = "markdown"
+)
+
+const (
+	ContentRenderVersion = 1
+)
+
+var (
+	AllowedLinkSchemes = []string{"http", "https", "mailto"}
+)
@@ -0,0 +1,8 @@
+package constants
+
+const (
+	DefaultRestoreWindow       = "168h"
+	DefaultSoftDeleteRetention = "720h"
+	DefaultPurgeInterval       = "1h"
+	MaxDeleteReasonLength      = 255
+)
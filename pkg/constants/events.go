@@ -0,0 +1,33 @@
+package constants
+
+type DomainEventType string
+
+const (
+	DomainEventUserRegistered         DomainEventType = "user.registered"
+	DomainEventUserActivated          DomainEventType = "user.activated"
+	DomainEventPasswordResetRequested DomainEventType = "user.password_reset_requested"
+	DomainEventPasswordChanged        DomainEventType = "user.password_changed"
+	DomainEventUserFollowed           DomainEventType = "user.followed"
+	DomainEventPostCreated            DomainEventType = "post.created"
+	DomainEventCommentCreated         DomainEventType = "comment.created"
+	DomainEventReactionChanged        DomainEventType = "reaction.changed"
+	DomainEventUsersMentioned         DomainEventType = "users.mentioned"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxPending   OutboxStatus = "pending"
+	OutboxProcessed OutboxStatus = "processed"
+	OutboxFailed    OutboxStatus = "failed"
+)
+
+const (
+	DefaultOutboxInterval    = "1s"
+	DefaultOutboxBackoffBase = "5s"
+	DefaultOutboxBackoffMax  = "1h"
+	DefaultOutboxRetention   = "168h"
+	DefaultOutboxMaxAttempts = 10
+	DefaultOutboxLease       = "5m"
+	OutboxBatchSize          = 100
+)
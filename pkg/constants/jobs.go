@@ -0,0 +1,44 @@
+package constants
+
+type JobKind string
+
+const (
+	JobPurgeDeleted     JobKind = "purge_deleted"
+	JobPublishScheduled JobKind = "publish_scheduled"
+	JobSendEmail        JobKind = "send_email"
+	JobSendDigests      JobKind = "send_digests"
+	JobSendDigest       JobKind = "send_digest"
+)
+
+var JobKinds = []JobKind{JobPurgeDeleted, JobPublishScheduled, JobSendEmail, JobSendDigests, JobSendDigest}
+
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "pending"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobDead      JobStatus = "dead"
+)
+
+type EmailKind string
+
+const (
+	EmailActivation       EmailKind = "activation"
+	EmailOAuthWelcome     EmailKind = "oauth_welcome"
+	EmailAccountActivated EmailKind = "account_activated"
+	EmailPasswordReset    EmailKind = "password_reset"
+	EmailPasswordChanged  EmailKind = "password_changed"
+)
+
+const (
+	DefaultJobPollInterval = "1s"
+	DefaultJobLease        = "5m"
+	DefaultJobTimeout      = "1m"
+	DefaultJobBackoffBase  = "10s"
+	DefaultJobBackoffMax   = "1h"
+	DefaultJobRetention    = "168h"
+	DefaultJobMaxAttempts  = 5
+	DefaultJobConcurrency  = 4
+	MaxJobErrorLength      = 2048
+)
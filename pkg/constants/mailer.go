@@ -0,0 +1,31 @@
+package constants
+
+type MailTransport string
+
+const (
+	MailTransportSMTP   MailTransport = "smtp"
+	MailTransportFile   MailTransport = "file"
+	MailTransportMemory MailTransport = "memory"
+)
+
+type MailTLSMode string
+
+const (
+	MailTLSNone     MailTLSMode = "none"
+	MailTLSStartTLS MailTLSMode = "starttls"
+	MailTLSImplicit MailTLSMode = "tls"
+)
+
+const (
+	DefaultMailTransport = "smtp"
+	DefaultMailFrom      = "no-reply@gopher.com"
+	DefaultMailDir       = "mail"
+	DefaultSMTPHost      = "mailpit"
+	DefaultSMTPPort      = 1025
+	DefaultSMTPTLS       = "none"
+	DefaultSMTPTimeout   = "30s"
+	DefaultAppURL        = "http://localhost:8080"
+	DefaultLocale        = "en"
+)
+
+var SupportedLocales = []string{"en", "es"}
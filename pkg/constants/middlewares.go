@@ -3,11 +3,14 @@ package constants
 type contextKey string
 
 const (
-	UserIDKey  contextKey = "userID"
-	LimitKey   contextKey = "limit"
-	OffsetKey  contextKey = "offset"
-	OrderByKey contextKey = "orderby"
-	DescKey    contextKey = "desc"
+	UserIDKey       contextKey = "userID"
+	LimitKey        contextKey = "limit"
+	OffsetKey       contextKey = "offset"
+	OrderByKey      contextKey = "orderby"
+	DescKey         contextKey = "desc"
+	CursorKey       contextKey = "cursor"
+	ModeKey         contextKey = "pagination"
+	IncludeTotalKey contextKey = "include_total"
 )
 
 const (
@@ -20,6 +23,11 @@ const (
 	DefaultOffset = 0
 )
 
+const (
+	PaginationModeOffset = "offset"
+	PaginationModeCursor = "cursor"
+)
+
 const (
 	DefaultOrderBy = "created_at"
 )
@@ -29,16 +37,17 @@ const (
 )
 
 var (
-	SQLKeywords = map[string]struct{}{
-		"SELECT": {}, "INSERT": {}, "UPDATE": {}, "DELETE": {},
-		"FROM": {}, "WHERE": {}, "JOIN": {}, "INNER": {},
-		"LEFT": {}, "RIGHT": {}, "FULL": {}, "GROUP": {},
-		"ORDER": {}, "BY": {}, "HAVING": {}, "LIMIT": {},
-		"OFFSET": {}, "DISTINCT": {}, "CREATE": {}, "DROP": {},
-		"ALTER": {}, "TABLE": {}, "INDEX": {}, "VIEW": {},
-		"SET": {}, "VALUES": {}, "INTO": {}, "AS": {},
-		"AND": {}, "OR": {}, "NOT": {}, "LIKE": {},
-		"IS": {}, "NULL": {}, "BETWEEN": {}, "EXISTS": {},
-		"UNION": {},
+	PostSortColumns = map[string]struct{}{
+		"created_at": {}, "published_at": {}, "likes_count": {},
+		"dislikes_count": {}, "comments_count": {},
+	}
+
+	CommentSortColumns = map[string]struct{}{
+		"created_at": {}, "likes_count": {}, "dislikes_count": {},
+	}
+
+	SortColumns = map[string]map[string]struct{}{
+		"posts":    PostSortColumns,
+		"comments": CommentSortColumns,
 	}
 )
@@ -0,0 +1,21 @@
+package constants
+
+type NotificationType string
+
+const (
+	NotificationLike    NotificationType = "like"
+	NotificationComment NotificationType = "comment"
+	NotificationReply   NotificationType = "reply"
+	NotificationFollow  NotificationType = "follow"
+	NotificationMention NotificationType = "mention"
+)
+
+const MaxNotificationActors = 3
+
+var NotificationTypes = []NotificationType{
+	NotificationLike,
+	NotificationComment,
+	NotificationReply,
+	NotificationFollow,
+	NotificationMention,
+}
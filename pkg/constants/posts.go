@@ -0,0 +1,40 @@
+package constants
+
+type PostStatus string
+
+const (
+	PostStatusDraft     PostStatus = "draft"
+	PostStatusScheduled PostStatus = "scheduled"
+	PostStatusPublished PostStatus = "published"
+	PostStatusArchived  PostStatus = "archived"
+)
+
+type PostVisibility string
+
+const (
+	PostVisibilityPublic    PostVisibility = "public"
+	PostVisibilityFollowers PostVisibility = "followers"
+	PostVisibilityUnlisted  PostVisibility = "unlisted"
+	PostVisibilityPrivate   PostVisibility = "private"
+)
+
+const (
+	DefaultPublishInterval = "30s"
+	PublishBatchSize       = 100
+)
+
+var (
+	PostStatusTransitions = map[PostStatus]map[PostStatus]struct{}{
+		PostStatusDraft:     {PostStatusDraft: {}, PostStatusScheduled: {}, PostStatusPublished: {}},
+		PostStatusScheduled: {PostStatusDraft: {}, PostStatusScheduled: {}, PostStatusPublished: {}},
+		PostStatusPublished: {PostStatusPublished: {}, PostStatusArchived: {}},
+		PostStatusArchived:  {PostStatusArchived: {}, PostStatusPublished: {}},
+	}
+
+	AuthorPostStatuses = map[string][]PostStatus{
+		"":                          {PostStatusDraft, PostStatusScheduled},
+		string(PostStatusDraft):     {PostStatusDraft},
+		string(PostStatusScheduled): {PostStatusScheduled},
+		string(PostStatusArchived):  {PostStatusArchived},
+	}
+)
@@ -0,0 +1,25 @@
+package constants
+
+type ReactionType string
+
+const (
+	ReactionLike    ReactionType = "like"
+	ReactionDislike ReactionType = "dislike"
+)
+
+const (
+	DefaultReactionTypes  = "like:👍,dislike:👎,love:❤️,laugh:😂,wow:😮"
+	MaxReactionTypeLength = 32
+)
+
+var (
+	ReactionCounterColumns = map[ReactionType]string{
+		ReactionLike:    "likes_count",
+		ReactionDislike: "dislikes_count",
+	}
+
+	RequiredReactionEmojis = map[ReactionType]string{
+		ReactionLike:    "👍",
+		ReactionDislike: "👎",
+	}
+)
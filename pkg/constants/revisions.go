@@ -0,0 +1,14 @@
+package constants
+
+const (
+	DefaultPostEditWindow    = "0s"
+	DefaultCommentEditWindow = "0s"
+	DefaultDiffRateLimit     = "2s"
+	MaxDiffCells             = 250_000
+)
+
+const (
+	DiffOpEqual  = "equal"
+	DiffOpInsert = "insert"
+	DiffOpDelete = "delete"
+)
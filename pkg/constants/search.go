@@ -0,0 +1,23 @@
+package constants
+
+type SearchType string
+
+const (
+	SearchTypePost    SearchType = "post"
+	SearchTypeComment SearchType = "comment"
+	SearchTypeUser    SearchType = "user"
+)
+
+const (
+	DefaultSearchLanguage = "english"
+	UserSearchLanguage    = "simple"
+	MaxSearchQueryLength  = 256
+)
+
+var (
+	SearchTypes = map[string]SearchType{
+		"post": SearchTypePost, "posts": SearchTypePost,
+		"comment": SearchTypeComment, "comments": SearchTypeComment,
+		"user": SearchTypeUser, "users": SearchTypeUser,
+	}
+)
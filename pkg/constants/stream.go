@@ -0,0 +1,47 @@
+package constants
+
+type StreamEventType string
+
+const (
+	StreamEventNotification     StreamEventType = "notification"
+	StreamEventCommentCreated   StreamEventType = "comment.created"
+	StreamEventCommentUpdated   StreamEventType = "comment.updated"
+	StreamEventCommentDeleted   StreamEventType = "comment.deleted"
+	StreamEventCommentRestored  StreamEventType = "comment.restored"
+	StreamEventTyping           StreamEventType = "typing"
+	StreamEventReactionsUpdated StreamEventType = "reactions.updated"
+)
+
+type WebSocketMessageType string
+
+const (
+	WebSocketSubscribe    WebSocketMessageType = "subscribe"
+	WebSocketUnsubscribe  WebSocketMessageType = "unsubscribe"
+	WebSocketTyping       WebSocketMessageType = "typing"
+	WebSocketPing         WebSocketMessageType = "ping"
+	WebSocketSubscribed   WebSocketMessageType = "subscribed"
+	WebSocketUnsubscribed WebSocketMessageType = "unsubscribed"
+	WebSocketPong         WebSocketMessageType = "pong"
+	WebSocketError        WebSocketMessageType = "error"
+)
+
+const (
+	DefaultWebSocketPingInterval   = "30s"
+	DefaultWebSocketFrameInterval  = "100ms"
+	DefaultWebSocketTypingInterval = "2s"
+	MaxWebSocketMessageSize        = 4096
+	WebSocketReplyBuffer           = 16
+)
+
+const (
+	StreamChannel                = "stream_events"
+	DefaultStreamHeartbeat       = "15s"
+	DefaultStreamWriteTimeout    = "10s"
+	DefaultStreamRetry           = "3s"
+	DefaultStreamEventRetention  = "24h"
+	DefaultStreamBufferSize      = 64
+	DefaultStreamReconnectDelay  = "5s"
+	StreamReplayLimit            = 1000
+	MaxStreamPosts               = 50
+	MaxStreamNotificationPayload = 7900
+)
@@ -0,0 +1,7 @@
+package constants
+
+const (
+	MaxTagsPerPost        = 20
+	DefaultTrendingWindow = "24h"
+	MaxTrendingWindow     = "720h"
+)
@@ -0,0 +1,31 @@
+package constants
+
+type MentionPolicy string
+
+const (
+	MentionPolicyEveryone  MentionPolicy = "everyone"
+	MentionPolicyFollowing MentionPolicy = "following"
+	MentionPolicyNobody    MentionPolicy = "nobody"
+)
+
+type DigestFrequency string
+
+const (
+	DigestOff    DigestFrequency = "off"
+	DigestDaily  DigestFrequency = "daily"
+	DigestWeekly DigestFrequency = "weekly"
+)
+
+const (
+	DefaultDigestSchedule = "0 8 * * *"
+	DigestRecipientBatch  = 500
+	DigestItemLimit       = 5
+)
+
+const (
+	MinHandleLength       = 3
+	MaxHandleLength       = 30
+	MaxHandleBaseLength   = 20
+	MaxHandleAttempts     = 5
+	MaxMentionsPerContent = 10
+)
@@ -0,0 +1,41 @@
+package constants
+
+type WebhookEventType string
+
+const (
+	WebhookEventPostCreated    WebhookEventType = "post.created"
+	WebhookEventCommentCreated WebhookEventType = "comment.created"
+	WebhookEventUserRegistered WebhookEventType = "user.registered"
+	WebhookEventPing           WebhookEventType = "ping"
+)
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+const (
+	DefaultWebhookInterval          = "5s"
+	DefaultWebhookTimeout           = "10s"
+	DefaultWebhookBackoffBase       = "30s"
+	DefaultWebhookBackoffMax        = "6h"
+	DefaultWebhookMaxAttempts       = 8
+	DefaultWebhookDeliveryRetention = "720h"
+	WebhookBatchSize                = 50
+	WebhookSecretBytes              = 32
+	MaxWebhooksPerUser              = 10
+	MaxWebhookResponseBody          = 2048
+)
+
+var WebhookEventTypes = []WebhookEventType{
+	WebhookEventPostCreated,
+	WebhookEventCommentCreated,
+	WebhookEventUserRegistered,
+}
+
+var GlobalWebhookEventTypes = map[WebhookEventType]struct{}{
+	WebhookEventUserRegistered: {},
+}
@@ -0,0 +1,42 @@
+package mailer
+
+import "gopher-social-backend-server/pkg/constants"
+
+const digestTemplate = "digest"
+
+type DigestActivity struct {
+	Actor  string
+	Others int64
+}
+
+type DigestPost struct {
+	Title    string
+	Author   string
+	URL      string
+	Likes    int64
+	Comments int64
+}
+
+type DigestEmailData struct {
+	Email          string
+	Frequency      constants.DigestFrequency
+	Followers      []DigestActivity
+	Replies        []DigestActivity
+	Mentions       []DigestActivity
+	Posts          []DigestPost
+	UnsubscribeURL string
+}
+
+func (d DigestEmailData) Empty() bool {
+	return len(d.Followers) == 0 && len(d.Replies) == 0 && len(d.Mentions) == 0 && len(d.Posts) == 0
+}
+
+func (r *Registry) NewDigestEmail(locale string, data DigestEmailData) (Message, error) {
+	message, err := r.Render(digestTemplate, locale, data)
+	message.To = data.Email
+	message.Headers = map[string]string{
+		"List-Unsubscribe":      "<" + data.UnsubscribeURL + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}
+	return message, err
+}
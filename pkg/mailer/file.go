@@ -0,0 +1,42 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type fileMailer struct {
+	dir  string
+	from string
+}
+
+func NewFileMailer(dir, from string) (Mailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("error creating mail directory: %w", err)
+	}
+
+	return &fileMailer{
+		dir:  dir,
+		from: from,
+	}, nil
+}
+
+func (m *fileMailer) Send(ctx context.Context, message Message) error {
+	var body bytes.Buffer
+	if err := message.writeTo(&body, m.from); err != nil {
+		return fmt.Errorf("error composing email: %w", err)
+	}
+
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), uuid.New())
+	if err := os.WriteFile(filepath.Join(m.dir, name), body.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("error writing email: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"gopher-social-backend-server/pkg/constants"
+	"gopher-social-backend-server/pkg/utils"
+	"io"
+
+	"gopkg.in/gomail.v2"
+)
+
+var APP_URL = utils.GetEnvAsString("APP_URL", constants.DefaultAppURL)
+
+type Message struct {
+	To      string
+	Subject string
+	HTML    string
+	Text    string
+	Headers map[string]string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
+
+func (m Message) writeTo(w io.Writer, from string) error {
+	mail := gomail.NewMessage()
+	mail.SetHeader("From", from)
+	mail.SetHeader("To", m.To)
+	mail.SetHeader("Subject", m.Subject)
+	for key, value := range m.Headers {
+		mail.SetHeader(key, value)
+	}
+
+	if m.Text != "" {
+		mail.SetBody("text/plain", m.Text)
+		mail.AddAlternative("text/html", m.HTML)
+	} else {
+		mail.SetBody("text/html", m.HTML)
+	}
+
+	_, err := mail.WriteTo(w)
+	return err
+}
+
+func NewMailer() (Mailer, error) {
+	MAIL_TRANSPORT := constants.MailTransport(utils.GetEnvAsString("MAIL_TRANSPORT", constants.DefaultMailTransport))
+	MAIL_FROM := utils.GetEnvAsString("MAIL_FROM", constants.DefaultMailFrom)
+
+	switch MAIL_TRANSPORT {
+	case constants.MailTransportSMTP:
+		return NewSMTPMailer(SMTPConfig{
+			Host:     utils.GetEnvAsString("SMTP_HOST", constants.DefaultSMTPHost),
+			Port:     utils.GetEnvAsInt("SMTP_PORT", constants.DefaultSMTPPort),
+			Username: utils.GetEnvAsString("SMTP_USERNAME", ""),
+			Password: utils.GetEnvAsString("SMTP_PASSWORD", ""),
+			TLS:      constants.MailTLSMode(utils.GetEnvAsString("SMTP_TLS", constants.DefaultSMTPTLS)),
+			Timeout:  utils.GetEnvAsDuration("SMTP_TIMEOUT", constants.DefaultSMTPTimeout),
+			From:     MAIL_FROM,
+		})
+	case constants.MailTransportFile:
+		return NewFileMailer(utils.GetEnvAsString("MAIL_DIR", constants.DefaultMailDir), MAIL_FROM)
+	case constants.MailTransportMemory:
+		return NewMemoryMailer(), nil
+	default:
+		return nil, fmt.Errorf("invalid mail transport: %s", MAIL_TRANSPORT)
+	}
+}
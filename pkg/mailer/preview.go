@@ -0,0 +1,39 @@
+package mailer
+
+import "gopher-social-backend-server/pkg/constants"
+
+const sampleEmail = "jane.doe@example.com"
+
+var samples = map[string]func() any{
+	accountActivationTemplate: func() any { return newActivationEmailData(sampleEmail, "sample-activation-token") },
+	accountActivatedTemplate:  func() any { return AccountActivatedEmailData{Email: sampleEmail} },
+	oauthWelcomeTemplate: func() any {
+		return OAuthWelcomeEmailData{Email: sampleEmail, Provider: constants.ProviderGoogle}
+	},
+	passwordChangedTemplate: func() any { return PasswordChangedEmailData{Email: sampleEmail} },
+	passwordResetTemplate:   func() any { return newPasswordResetEmailData(sampleEmail, "sample-reset-token") },
+	digestTemplate: func() any {
+		return DigestEmailData{
+			Email:     sampleEmail,
+			Frequency: constants.DigestWeekly,
+			Followers: []DigestActivity{{Actor: "@gopher", Others: 2}},
+			Replies:   []DigestActivity{{Actor: "@rustacean"}},
+			Mentions:  []DigestActivity{{Actor: "@pythonista", Others: 1}},
+			Posts: []DigestPost{
+				{Title: "Understanding Go channels", Author: "@gopher", URL: APP_URL + "/api/v1/posts/sample", Likes: 42, Comments: 7},
+			},
+			UnsubscribeURL: APP_URL + "/api/v1/users/unsubscribe?token=sample-unsubscribe-token",
+		}
+	},
+}
+
+func (r *Registry) Preview(name, locale string) (Message, error) {
+	sample, ok := samples[name]
+	if !ok {
+		return Message{}, ErrTemplateNotFound
+	}
+
+	message, err := r.Render(name, locale, sample())
+	message.To = sampleEmail
+	return message, err
+}
@@ -0,0 +1,187 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gopher-social-backend-server/pkg/constants"
+	"html"
+	"html/template"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+//go:embed templates/*.gtpl
+var templateFS embed.FS
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+var ErrTemplateNotFound = errors.New("template not found")
+
+type catalog map[string]string
+
+type Registry struct {
+	templates map[string]*template.Template
+	catalogs  map[string]catalog
+}
+
+func NewRegistry() (*Registry, error) {
+	registry := &Registry{
+		templates: make(map[string]*template.Template),
+		catalogs:  make(map[string]catalog),
+	}
+
+	localeFiles, err := fs.Glob(localeFS, "locales/*.json")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range localeFiles {
+		content, err := localeFS.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		var messages catalog
+		if err := json.Unmarshal(content, &messages); err != nil {
+			return nil, fmt.Errorf("error parsing locale %s: %w", file, err)
+		}
+		registry.catalogs[strings.TrimSuffix(path.Base(file), ".json")] = messages
+	}
+
+	for _, locale := range append([]string{constants.DefaultLocale}, constants.SupportedLocales...) {
+		if _, ok := registry.catalogs[locale]; !ok {
+			return nil, fmt.Errorf("missing catalog for locale %s", locale)
+		}
+	}
+
+	templateFiles, err := fs.Glob(templateFS, "templates/*.gtpl")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range templateFiles {
+		name := strings.TrimSuffix(path.Base(file), ".gtpl")
+		if name == "layout" {
+			continue
+		}
+
+		tmpl, err := template.New(name).Funcs(registry.funcs(constants.DefaultLocale)).
+			ParseFS(templateFS, "templates/layout.gtpl", file)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing template %s: %w", name, err)
+		}
+		registry.templates[name] = tmpl
+	}
+
+	return registry, nil
+}
+
+func (r *Registry) Templates() []string {
+	names := make([]string, 0, len(r.templates))
+	for name := range r.templates {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (r *Registry) Locales() []string {
+	locales := make([]string, 0, len(r.catalogs))
+	for locale := range r.catalogs {
+		locales = append(locales, locale)
+	}
+	slices.Sort(locales)
+	return locales
+}
+
+func (r *Registry) HasLocale(locale string) bool {
+	_, ok := r.catalogs[locale]
+	return ok
+}
+
+func (r *Registry) translate(locale, key string, args ...any) string {
+	message, ok := r.catalogs[locale][key]
+	if !ok {
+		message, ok = r.catalogs[constants.DefaultLocale][key]
+	}
+	if !ok {
+		return key
+	}
+
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+func (r *Registry) funcs(locale string) template.FuncMap {
+	return template.FuncMap{
+		"t": func(key string, args ...any) string {
+			return r.translate(locale, key, args...)
+		},
+		"locale": func() string {
+			return locale
+		},
+		"dict": dict,
+	}
+}
+
+func dict(pairs ...any) (map[string]any, error) {
+	if len(pairs)%2 != 0 {
+		return nil, errors.New("dict expects key and value pairs")
+	}
+
+	values := make(map[string]any, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict key %v is not a string", pairs[i])
+		}
+		values[key] = pairs[i+1]
+	}
+	return values, nil
+}
+
+func (r *Registry) Render(name, locale string, data any) (Message, error) {
+	base, ok := r.templates[name]
+	if !ok {
+		return Message{}, ErrTemplateNotFound
+	}
+
+	if !r.HasLocale(locale) {
+		locale = constants.DefaultLocale
+	}
+
+	tmpl, err := base.Clone()
+	if err != nil {
+		return Message{}, err
+	}
+	tmpl.Funcs(r.funcs(locale))
+
+	var subject bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, fmt.Errorf("error rendering %s email subject: %w", name, err)
+	}
+
+	var body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&body, "layout", data); err != nil {
+		return Message{}, fmt.Errorf("error rendering %s email template: %w", name, err)
+	}
+
+	text, err := htmlToText(body.String())
+	if err != nil {
+		return Message{}, fmt.Errorf("error rendering %s email text: %w", name, err)
+	}
+
+	return Message{
+		Subject: strings.TrimSpace(html.UnescapeString(subject.String())),
+		HTML:    body.String(),
+		Text:    text,
+	}, nil
+}
@@ -0,0 +1,124 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"gopher-social-backend-server/pkg/constants"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	TLS      constants.MailTLSMode
+	Timeout  time.Duration
+	From     string
+}
+
+type smtpMailer struct {
+	config SMTPConfig
+}
+
+func NewSMTPMailer(config SMTPConfig) (Mailer, error) {
+	switch config.TLS {
+	case constants.MailTLSNone, constants.MailTLSStartTLS, constants.MailTLSImplicit:
+	default:
+		return nil, fmt.Errorf("invalid smtp tls mode: %s", config.TLS)
+	}
+
+	return &smtpMailer{
+		config: config,
+	}, nil
+}
+
+func (m *smtpMailer) dial(ctx context.Context) (*smtp.Client, error) {
+	address := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	tlsConfig := &tls.Config{ServerName: m.config.Host}
+
+	ctx, cancel := context.WithTimeout(ctx, m.config.Timeout)
+	defer cancel()
+
+	var conn net.Conn
+	var err error
+	if m.config.TLS == constants.MailTLSImplicit {
+		dialer := &tls.Dialer{Config: tlsConfig}
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, m.config.Host)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	if m.config.TLS == constants.MailTLSStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			client.Close()
+			return nil, fmt.Errorf("smtp server %s does not support STARTTLS", address)
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+
+	if m.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+
+	return client, nil
+}
+
+func (m *smtpMailer) Send(ctx context.Context, message Message) error {
+	var body bytes.Buffer
+	if err := message.writeTo(&body, m.config.From); err != nil {
+		return fmt.Errorf("error composing email: %w", err)
+	}
+
+	client, err := m.dial(ctx)
+	if err != nil {
+		return fmt.Errorf("error connecting to smtp server: %w", err)
+	}
+	defer client.Close()
+
+	if err := client.Mail(m.config.From); err != nil {
+		return fmt.Errorf("error sending email: %w", err)
+	}
+	if err := client.Rcpt(message.To); err != nil {
+		return fmt.Errorf("error sending email: %w", err)
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("error sending email: %w", err)
+	}
+	if _, err := writer.Write(body.Bytes()); err != nil {
+		writer.Close()
+		return fmt.Errorf("error sending email: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("error sending email: %w", err)
+	}
+
+	return client.Quit()
+}
@@ -0,0 +1,105 @@
+package mailer
+
+import (
+	"io"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+var blockTags = map[string]bool{
+	"p": true, "div": true, "h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"ul": true, "ol": true, "li": true, "table": true, "tr": true, "blockquote": true, "hr": true,
+}
+
+var skippedTags = map[string]bool{
+	"head": true, "style": true, "script": true, "title": true,
+}
+
+func htmlToText(source string) (string, error) {
+	tokenizer := html.NewTokenizer(strings.NewReader(source))
+
+	var text strings.Builder
+	var links []string
+	var linkText strings.Builder
+	skipped := 0
+
+	write := func(s string) {
+		text.WriteString(s)
+		if len(links) > 0 {
+			linkText.WriteString(s)
+		}
+	}
+
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			if err := tokenizer.Err(); err != io.EOF {
+				return "", err
+			}
+			lines := strings.Split(text.String(), "\n")
+			for i, line := range lines {
+				lines[i] = strings.TrimSpace(line)
+			}
+			return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")), nil
+		case html.TextToken:
+			if skipped > 0 {
+				continue
+			}
+			content := strings.Join(strings.Fields(string(tokenizer.Text())), " ")
+			if content == "" {
+				continue
+			}
+			if current := text.String(); current != "" && !strings.HasSuffix(current, "\n") && !strings.HasSuffix(current, " ") {
+				write(" ")
+			}
+			write(content)
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := tokenizer.TagName()
+			tag := string(name)
+			switch {
+			case skippedTags[tag]:
+				skipped++
+			case tag == "br":
+				write("\n")
+			case tag == "hr":
+				write("\n\n---\n\n")
+			case tag == "li":
+				write("\n- ")
+			case tag == "a":
+				href := ""
+				for hasAttr {
+					var key, value []byte
+					key, value, hasAttr = tokenizer.TagAttr()
+					if string(key) == "href" {
+						href = string(value)
+					}
+				}
+				links = append(links, href)
+				linkText.Reset()
+			case blockTags[tag]:
+				write("\n\n")
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			tag := string(name)
+			switch {
+			case skippedTags[tag]:
+				if skipped > 0 {
+					skipped--
+				}
+			case tag == "a" && len(links) > 0:
+				href := links[len(links)-1]
+				links = links[:len(links)-1]
+				if href != "" && strings.TrimSpace(linkText.String()) != href {
+					write(" (" + href + ")")
+				}
+			case blockTags[tag]:
+				write("\n\n")
+			}
+		}
+	}
+}
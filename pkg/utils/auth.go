@@ -2,6 +2,10 @@ package utils
 
 import (
 	"errors"
+	"gopher-social-backend-server/pkg/constants"
+	"net/http"
+
+	"github.com/google/uuid"
 )
 
 func VerifyOwnership(userID, authUserID string) error {
@@ -10,3 +14,14 @@ func VerifyOwnership(userID, authUserID string) error {
 	}
 	return nil
 }
+
+func ViewerID(r *http.Request) uuid.UUID {
+	userID, _ := r.Context().Value(constants.UserIDKey).(string)
+
+	viewerID, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil
+	}
+
+	return viewerID
+}
@@ -0,0 +1,11 @@
+package utils
+
+import "time"
+
+func Backoff(attempts int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < max; i++ {
+		delay *= 2
+	}
+	return min(delay, max)
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"bytes"
+	"gopher-social-backend-server/pkg/constants"
+	"html"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+)
+
+var markdown = goldmark.New()
+
+var htmlPolicy = newHTMLPolicy()
+
+func newHTMLPolicy() *bluemonday.Policy {
+	policy := bluemonday.UGCPolicy()
+	policy.AllowURLSchemes(constants.AllowedLinkSchemes...)
+	policy.RequireNoFollowOnLinks(true)
+	policy.RequireNoReferrerOnLinks(true)
+	policy.AddTargetBlankToFullyQualifiedLinks(true)
+	return policy
+}
+
+func renderPlain(content string) string {
+	paragraphs := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n\n")
+
+	var builder strings.Builder
+	for _, paragraph := range paragraphs {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		builder.WriteString("<p>")
+		builder.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>\n"))
+		builder.WriteString("</p>\n")
+	}
+
+	return builder.String()
+}
+
+func RenderContent(format constants.ContentFormat, content string) (string, error) {
+	if format != constants.ContentFormatMarkdown {
+		return renderPlain(content), nil
+	}
+
+	var buf bytes.Buffer
+	if err := markdown.Convert([]byte(content), &buf); err != nil {
+		return "", err
+	}
+
+	return htmlPolicy.Sanitize(buf.String()), nil
+}
+
+func RenderedContent(format constants.ContentFormat, content, cached string, version int) string {
+	if version == constants.ContentRenderVersion {
+		return cached
+	}
+
+	rendered, err := RenderContent(format, content)
+	if err != nil {
+		return renderPlain(content)
+	}
+
+	return rendered
+}
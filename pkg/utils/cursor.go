@@ -0,0 +1,172 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"gorm.io/gorm/schema"
+)
+
+var CURSOR_SECRET = GetEnvAsByteArr("CURSOR_SECRET", string(deriveKey(JWT_SECRET, "cursor")))
+
+var schemaCache = &sync.Map{}
+
+type Cursor struct {
+	OrderBy  string `json:"o"`
+	Desc     bool   `json:"d"`
+	Value    any    `json:"v"`
+	ID       string `json:"i"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+func signCursor(payload []byte) []byte {
+	mac := hmac.New(sha256.New, CURSOR_SECRET)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func EncodeCursor(cursor Cursor) (string, error) {
+	payload, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %v", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(signCursor(payload)), nil
+}
+
+func DecodeCursor(token string) (*Cursor, error) {
+	encodedPayload, encodedSignature, found := strings.Cut(token, ".")
+	if !found {
+		return nil, errors.New("invalid cursor: malformed token")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return nil, errors.New("invalid cursor: malformed token")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, signCursor(payload)) {
+		return nil, errors.New("invalid cursor: signature mismatch")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+
+	var cursor Cursor
+	if err := decoder.Decode(&cursor); err != nil {
+		return nil, errors.New("invalid cursor: malformed token")
+	}
+
+	if number, ok := cursor.Value.(json.Number); ok {
+		if intValue, err := number.Int64(); err == nil {
+			cursor.Value = intValue
+		} else if floatValue, err := number.Float64(); err == nil {
+			cursor.Value = floatValue
+		}
+	}
+
+	return &cursor, nil
+}
+
+func ValidateCursor(cursor *Cursor, orderby string, desc bool) error {
+	if cursor == nil || cursor.ID == "" {
+		return nil
+	}
+
+	if cursor.OrderBy != orderby || cursor.Desc != desc {
+		return errors.New("invalid cursor: ordering does not match the cursor")
+	}
+
+	return nil
+}
+
+func CursorValue(item any, column string) (any, error) {
+	itemSchema, err := schema.Parse(item, schemaCache, schema.NamingStrategy{})
+	if err != nil {
+		return nil, err
+	}
+
+	field := itemSchema.LookUpField(column)
+	if field == nil {
+		return nil, fmt.Errorf("invalid orderby: unknown column %s", column)
+	}
+
+	value := reflect.ValueOf(item)
+	for value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+
+	fieldValue, _ := field.ValueOf(context.Background(), value)
+	if stringer, ok := fieldValue.(fmt.Stringer); ok {
+		return stringer.String(), nil
+	}
+
+	return fieldValue, nil
+}
+
+func newCursor(item any, orderby string, desc, backward bool) (string, error) {
+	value, err := CursorValue(item, orderby)
+	if err != nil {
+		return "", err
+	}
+
+	id, err := CursorValue(item, "id")
+	if err != nil {
+		return "", err
+	}
+
+	return EncodeCursor(Cursor{
+		OrderBy:  orderby,
+		Desc:     desc,
+		Value:    value,
+		ID:       fmt.Sprint(id),
+		Backward: backward,
+	})
+}
+
+func PaginateByCursor[T any](items []T, limit int, cursor *Cursor, orderby string, desc bool) ([]T, string, string, error) {
+	started := cursor != nil && cursor.ID != ""
+	backward := started && cursor.Backward
+
+	hasMore := len(items) > limit
+	if hasMore {
+		items = items[:limit]
+	}
+
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+
+	if len(items) == 0 {
+		return items, "", "", nil
+	}
+
+	var next, prev string
+	var err error
+
+	if (!backward && hasMore) || (backward && started) {
+		if next, err = newCursor(&items[len(items)-1], orderby, desc, false); err != nil {
+			return nil, "", "", err
+		}
+	}
+
+	if (backward && hasMore) || (!backward && started) {
+		if prev, err = newCursor(&items[0], orderby, desc, true); err != nil {
+			return nil, "", "", err
+		}
+	}
+
+	return items, next, prev, nil
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"gopher-social-backend-server/pkg/constants"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var RESTORE_WINDOW = GetEnvAsDuration("RESTORE_WINDOW", constants.DefaultRestoreWindow)
+
+func IsStaff(role constants.UserRole) bool {
+	return role == constants.RoleStaff || role == constants.RoleAdmin
+}
+
+func ParseDeleteReason(r *http.Request) (string, error) {
+	reason := r.URL.Query().Get("reason")
+	if len(reason) > constants.MaxDeleteReasonLength {
+		return "", fmt.Errorf("invalid reason: must be at most %d characters", constants.MaxDeleteReasonLength)
+	}
+	return reason, nil
+}
+
+func CanRestore(userID uuid.UUID, role constants.UserRole, authorID uuid.UUID, deletedByID *uuid.UUID, deletedAt time.Time) error {
+	if IsStaff(role) {
+		return nil
+	}
+
+	if userID != authorID || deletedByID == nil || *deletedByID != userID {
+		return errors.New("only the author can restore content they deleted")
+	}
+
+	if time.Since(deletedAt) > RESTORE_WINDOW {
+		return errors.New("the restore window has expired")
+	}
+
+	return nil
+}
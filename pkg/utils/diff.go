@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"fmt"
+	"gopher-social-backend-server/pkg/constants"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+type DiffLine struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+func DiffLines(from, to string) []DiffLine {
+	a := strings.Split(from, "\n")
+	b := strings.Split(to, "\n")
+	diff := make([]DiffLine, 0, len(a)+len(b))
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	for _, line := range a[:prefix] {
+		diff = append(diff, DiffLine{Op: constants.DiffOpEqual, Text: line})
+	}
+	diff = append(diff, diffRegion(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		diff = append(diff, DiffLine{Op: constants.DiffOpEqual, Text: line})
+	}
+
+	return diff
+}
+
+func diffRegion(a, b []string) []DiffLine {
+	diff := make([]DiffLine, 0, len(a)+len(b))
+
+	if (len(a)+1)*(len(b)+1) > constants.MaxDiffCells {
+		for _, line := range a {
+			diff = append(diff, DiffLine{Op: constants.DiffOpDelete, Text: line})
+		}
+		for _, line := range b {
+			diff = append(diff, DiffLine{Op: constants.DiffOpInsert, Text: line})
+		}
+		return diff
+	}
+
+	width := len(b) + 1
+	lcs := make([]int32, (len(a)+1)*width)
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i*width+j] = lcs[(i+1)*width+j+1] + 1
+			} else {
+				lcs[i*width+j] = max(lcs[(i+1)*width+j], lcs[i*width+j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			diff = append(diff, DiffLine{Op: constants.DiffOpEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[(i+1)*width+j] >= lcs[i*width+j+1]:
+			diff = append(diff, DiffLine{Op: constants.DiffOpDelete, Text: a[i]})
+			i++
+		default:
+			diff = append(diff, DiffLine{Op: constants.DiffOpInsert, Text: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		diff = append(diff, DiffLine{Op: constants.DiffOpDelete, Text: a[i]})
+	}
+	for ; j < len(b); j++ {
+		diff = append(diff, DiffLine{Op: constants.DiffOpInsert, Text: b[j]})
+	}
+
+	return diff
+}
+
+func ParseRevisionVersion(r *http.Request, key string, fallback int64) (int64, error) {
+	param := r.URL.Query().Get(key)
+	if param == "" {
+		return fallback, nil
+	}
+
+	version, err := strconv.ParseInt(param, 10, 64)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("invalid %s: must be a positive revision number", key)
+	}
+
+	return version, nil
+}
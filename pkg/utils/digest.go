@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"gopher-social-backend-server/pkg/constants"
+	"time"
+)
+
+func DigestPeriod(frequency constants.DigestFrequency) time.Duration {
+	switch frequency {
+	case constants.DigestDaily:
+		return 24 * time.Hour
+	case constants.DigestWeekly:
+		return 7 * 24 * time.Hour
+	default:
+		return 0
+	}
+}
+
+func DigestCutoff(frequency constants.DigestFrequency, now time.Time) int64 {
+	return now.Add(-DigestPeriod(frequency) + time.Hour).Unix()
+}
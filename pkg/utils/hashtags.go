@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+var hashtagRegex = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_&#/])#([\p{L}\p{N}_]{1,64})`)
+var tagNameRegex = regexp.MustCompile(`^[\p{L}\p{N}_]{1,64}$`)
+
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
+
+func IsValidTag(tag string) bool {
+	return tagNameRegex.MatchString(tag)
+}
+
+func ExtractHashtags(content string) []string {
+	var tags []string
+	for _, match := range hashtagRegex.FindAllStringSubmatch(content, -1) {
+		tags = append(tags, match[1])
+	}
+	return tags
+}
+
+func MergeTags(tagLists ...[]string) []string {
+	seen := make(map[string]struct{})
+	tags := make([]string, 0)
+
+	for _, tagList := range tagLists {
+		for _, tag := range tagList {
+			tag = NormalizeTag(tag)
+			if !IsValidTag(tag) {
+				continue
+			}
+			if _, exists := seen[tag]; exists {
+				continue
+			}
+			seen[tag] = struct{}{}
+			tags = append(tags, tag)
+		}
+	}
+
+	return tags
+}
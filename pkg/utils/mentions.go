@@ -0,0 +1,86 @@
+package utils
+
+import (
+	"fmt"
+	"gopher-social-backend-server/pkg/constants"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+type Mention struct {
+	Handle string
+	Start  int
+	End    int
+}
+
+var mentionRegex = regexp.MustCompile(fmt.Sprintf(`(?:^|[^A-Za-z0-9_@])@([A-Za-z0-9_]{%d,%d})`, constants.MinHandleLength, constants.MaxHandleLength))
+var handleRegex = regexp.MustCompile(fmt.Sprintf(`^[a-z0-9_]{%d,%d}$`, constants.MinHandleLength, constants.MaxHandleLength))
+var handleInvalidChars = regexp.MustCompile(`[^a-z0-9_]+`)
+
+func NormalizeHandle(handle string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(handle), "@"))
+}
+
+func IsValidHandle(handle string) bool {
+	return handleRegex.MatchString(handle)
+}
+
+func HandleFromEmail(email string) string {
+	local, _, _ := strings.Cut(strings.ToLower(email), "@")
+	handle := strings.Trim(handleInvalidChars.ReplaceAllString(local, "_"), "_")
+	if len(handle) > constants.MaxHandleBaseLength {
+		handle = handle[:constants.MaxHandleBaseLength]
+	}
+	if len(handle) < constants.MinHandleLength {
+		handle = "user_" + strings.ReplaceAll(uuid.NewString(), "-", "")[:8]
+	}
+	return handle
+}
+
+func ExtractMentions(content string) []Mention {
+	var mentions []Mention
+	seen := make(map[string]struct{})
+
+	for _, match := range mentionRegex.FindAllStringSubmatchIndex(content, -1) {
+		start, end := match[2]-1, match[3]
+		if end < len(content) {
+			if next, _ := utf8.DecodeRuneInString(content[end:]); next == '_' || ('0' <= next && next <= '9') || ('a' <= next && next <= 'z') || ('A' <= next && next <= 'Z') {
+				continue
+			}
+		}
+
+		handle := NormalizeHandle(content[match[2]:match[3]])
+		if _, exists := seen[handle]; !exists {
+			if len(seen) == constants.MaxMentionsPerContent {
+				continue
+			}
+			seen[handle] = struct{}{}
+		}
+
+		mentions = append(mentions, Mention{
+			Handle: handle,
+			Start:  utf8.RuneCountInString(content[:start]),
+			End:    utf8.RuneCountInString(content[:end]),
+		})
+	}
+
+	return mentions
+}
+
+func MentionHandles(mentions []Mention) []string {
+	seen := make(map[string]struct{})
+	handles := make([]string, 0, len(mentions))
+
+	for _, mention := range mentions {
+		if _, exists := seen[mention.Handle]; exists {
+			continue
+		}
+		seen[mention.Handle] = struct{}{}
+		handles = append(handles, mention.Handle)
+	}
+
+	return handles
+}
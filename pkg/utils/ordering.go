@@ -1,10 +1,10 @@
 package utils
 
 import (
+	"maps"
 	"net/http"
+	"slices"
 	"strings"
-
-	"gopher-social-backend-server/pkg/constants"
 )
 
 func ParseOrderByQueryParam(r *http.Request, key, defaultValue string) string {
@@ -26,7 +26,11 @@ func ParseDescQueryParam(r *http.Request, key, defaultValue string) string {
 	return paramStr
 }
 
-func IsSQLInjection(queryPart string) bool {
-	_, exists := constants.SQLKeywords[strings.ToUpper(queryPart)]
+func IsSortable(columns map[string]struct{}, column string) bool {
+	_, exists := columns[column]
 	return exists
 }
+
+func SortableColumns(columns map[string]struct{}) string {
+	return strings.Join(slices.Sorted(maps.Keys(columns)), ", ")
+}
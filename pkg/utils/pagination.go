@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"gopher-social-backend-server/pkg/constants"
 	"net/http"
 	"strconv"
 )
@@ -19,3 +20,139 @@ func ParseLimitOffsetQueryParam(r *http.Request, key string, defaultValue, min,
 
 	return value, nil
 }
+
+type PageInfo struct {
+	Total      *int64 `json:"total,omitempty"`
+	Limit      int    `json:"limit"`
+	Offset     *int   `json:"offset,omitempty"`
+	Cursor     string `json:"cursor,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+type Page[T any] struct {
+	Data []T `json:"data"`
+	PageInfo
+}
+
+func IncludeTotal(r *http.Request) bool {
+	includeTotal, _ := r.Context().Value(constants.IncludeTotalKey).(bool)
+	return includeTotal
+}
+
+func PaginateResults[T any](r *http.Request, items []T) ([]T, PageInfo, error) {
+	limit, _ := r.Context().Value(constants.LimitKey).(int)
+	cursor, _ := r.Context().Value(constants.CursorKey).(*Cursor)
+
+	if cursor != nil {
+		orderby, _ := r.Context().Value(constants.OrderByKey).(string)
+		desc, _ := r.Context().Value(constants.DescKey).(string)
+
+		items, next, prev, err := PaginateByCursor(items, limit, cursor, orderby, desc == "true")
+		if err != nil {
+			return nil, PageInfo{}, err
+		}
+
+		return items, PageInfo{
+			Limit:      limit,
+			Cursor:     r.URL.Query().Get(string(constants.CursorKey)),
+			NextCursor: next,
+			PrevCursor: prev,
+			HasMore:    next != "",
+		}, nil
+	}
+
+	offset, _ := r.Context().Value(constants.OffsetKey).(int)
+
+	hasMore := len(items) > limit
+	if hasMore {
+		items = items[:limit]
+	}
+
+	return items, PageInfo{
+		Limit:   limit,
+		Offset:  &offset,
+		HasMore: hasMore,
+	}, nil
+}
+
+func SetLinkHeader(w http.ResponseWriter, r *http.Request, links map[string]map[string]string) {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if forwardedProto := r.Header.Get("X-Forwarded-Proto"); forwardedProto != "" {
+		scheme = forwardedProto
+	}
+
+	for _, rel := range []string{"first", "prev", "next", "last"} {
+		params, ok := links[rel]
+		if !ok {
+			continue
+		}
+
+		query := r.URL.Query()
+		for key, value := range params {
+			if value == "" {
+				query.Del(key)
+			} else {
+				query.Set(key, value)
+			}
+		}
+
+		link := fmt.Sprintf("%s://%s%s", scheme, r.Host, r.URL.Path)
+		if encoded := query.Encode(); encoded != "" {
+			link += "?" + encoded
+		}
+
+		w.Header().Add("Link", fmt.Sprintf(`<%s>; rel="%s"`, link, rel))
+	}
+}
+
+func pageLinks(pageInfo PageInfo) map[string]map[string]string {
+	links := make(map[string]map[string]string)
+
+	if pageInfo.Offset == nil {
+		links["first"] = map[string]string{string(constants.CursorKey): "", string(constants.ModeKey): constants.PaginationModeCursor}
+		if pageInfo.NextCursor != "" {
+			links["next"] = map[string]string{string(constants.CursorKey): pageInfo.NextCursor, string(constants.ModeKey): ""}
+		}
+		if pageInfo.PrevCursor != "" {
+			links["prev"] = map[string]string{string(constants.CursorKey): pageInfo.PrevCursor, string(constants.ModeKey): ""}
+		}
+		return links
+	}
+
+	limit, offset := pageInfo.Limit, *pageInfo.Offset
+
+	links["first"] = map[string]string{string(constants.OffsetKey): strconv.Itoa(0)}
+	if offset > 0 {
+		links["prev"] = map[string]string{string(constants.OffsetKey): strconv.Itoa(max(offset-limit, 0))}
+	}
+	if pageInfo.HasMore {
+		links["next"] = map[string]string{string(constants.OffsetKey): strconv.Itoa(offset + limit)}
+	}
+	if pageInfo.Total != nil && limit > 0 {
+		lastOffset := 0
+		if *pageInfo.Total > 0 {
+			lastOffset = int((*pageInfo.Total-1)/int64(limit)) * limit
+		}
+		links["last"] = map[string]string{string(constants.OffsetKey): strconv.Itoa(lastOffset)}
+	}
+
+	return links
+}
+
+func WritePage[T any](w http.ResponseWriter, r *http.Request, status int, items []T, pageInfo PageInfo) error {
+	if items == nil {
+		items = make([]T, 0)
+	}
+
+	SetLinkHeader(w, r, pageLinks(pageInfo))
+
+	return WriteJSON(w, status, Page[T]{
+		Data:     items,
+		PageInfo: pageInfo,
+	})
+}
@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"fmt"
+	"gopher-social-backend-server/pkg/constants"
+	"time"
+)
+
+func ValidatePostStatus(from, to constants.PostStatus, publishAt *int64) error {
+	if _, ok := constants.PostStatusTransitions[from][to]; !ok {
+		return fmt.Errorf("invalid status: cannot change a %s post to %s", from, to)
+	}
+
+	if to == constants.PostStatusScheduled {
+		if publishAt == nil {
+			return fmt.Errorf("invalid publish_at: required when scheduling a post")
+		}
+		if *publishAt <= time.Now().Unix() {
+			return fmt.Errorf("invalid publish_at: must be in the future")
+		}
+	}
+
+	return nil
+}
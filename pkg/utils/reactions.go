@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"fmt"
+	"gopher-social-backend-server/pkg/constants"
+	"regexp"
+	"strings"
+)
+
+var reactionTypePattern = regexp.MustCompile(fmt.Sprintf(`^[a-z0-9_]{1,%d}$`, constants.MaxReactionTypeLength))
+
+var REACTION_TYPES = ParseReactionTypes(GetEnvAsString("REACTION_TYPES", constants.DefaultReactionTypes))
+
+type Reaction struct {
+	Type  constants.ReactionType `json:"type"`
+	Emoji string                 `json:"emoji"`
+}
+
+type ReactionCount struct {
+	Reaction
+	Count int64 `json:"count"`
+}
+
+func ParseReactionTypes(spec string) []Reaction {
+	reactions := make([]Reaction, 0)
+	seen := make(map[constants.ReactionType]struct{})
+
+	for _, entry := range strings.Split(spec, ",") {
+		name, emoji, _ := strings.Cut(strings.TrimSpace(entry), ":")
+		reactionType := constants.ReactionType(strings.ToLower(strings.TrimSpace(name)))
+		if !reactionTypePattern.MatchString(string(reactionType)) {
+			continue
+		}
+		if _, ok := seen[reactionType]; ok {
+			continue
+		}
+
+		seen[reactionType] = struct{}{}
+		reactions = append(reactions, Reaction{Type: reactionType, Emoji: strings.TrimSpace(emoji)})
+	}
+
+	for _, reactionType := range []constants.ReactionType{constants.ReactionLike, constants.ReactionDislike} {
+		if _, ok := seen[reactionType]; !ok {
+			reactions = append(reactions, Reaction{Type: reactionType, Emoji: constants.RequiredReactionEmojis[reactionType]})
+		}
+	}
+
+	return reactions
+}
+
+func LookupReaction(reactionType constants.ReactionType) (Reaction, bool) {
+	for _, reaction := range REACTION_TYPES {
+		if reaction.Type == reactionType {
+			return reaction, true
+		}
+	}
+	return Reaction{}, false
+}
+
+func IsValidReactionType(reactionType constants.ReactionType) bool {
+	_, ok := LookupReaction(reactionType)
+	return ok
+}
+
+func ReactionCounts(counts map[constants.ReactionType]int64) []ReactionCount {
+	reactionCounts := make([]ReactionCount, 0, len(REACTION_TYPES))
+	for _, reaction := range REACTION_TYPES {
+		reactionCounts = append(reactionCounts, ReactionCount{Reaction: reaction, Count: counts[reaction.Type]})
+	}
+	return reactionCounts
+}
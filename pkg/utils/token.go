@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -10,6 +13,16 @@ var ACTIVATION_MAIL_EXPIRATION = GetEnvAsDuration("ACTIVATION_MAIL_EXPIRATION",
 var PASSWORD_RESET_EXPIRATION = GetEnvAsDuration("PASSWORD_RESET_EXPIRATION", "30m")
 var JWT_SECRET = GetEnvAsByteArr("JWT_SECRET", "b82d4b46c665de2f8d506caf26f889c4d1b4d279a94fb99ef1f2d46992b034e5")
 var JWT_EXPIRATION = GetEnvAsDuration("JWT_EXPIRATION", "6h")
+var UNSUBSCRIBE_SECRET = GetEnvAsByteArr("UNSUBSCRIBE_SECRET", string(deriveKey(JWT_SECRET, "unsubscribe")))
+var UNSUBSCRIBE_TOKEN_MAX_AGE = GetEnvAsDuration("UNSUBSCRIBE_TOKEN_MAX_AGE", "1440h")
+
+const unsubscribeAudience = "unsubscribe"
+
+func deriveKey(secret []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
 
 func GenerateActivationToken(userID string) string {
 	expirationTime := time.Now().Add(ACTIVATION_MAIL_EXPIRATION)
@@ -91,3 +104,30 @@ func ParseAccessToken(tokenStr string) (*jwt.RegisteredClaims, error) {
 	}
 	return claims, nil
 }
+
+var ErrInvalidUnsubscribeToken = errors.New("invalid unsubscribe token")
+
+func GenerateUnsubscribeToken(userID string) string {
+	now := time.Now()
+	claims := &jwt.RegisteredClaims{
+		Audience:  jwt.ClaimStrings{unsubscribeAudience},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(UNSUBSCRIBE_TOKEN_MAX_AGE)),
+		Subject:   userID,
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	tokenString, _ := token.SignedString(UNSUBSCRIBE_SECRET)
+	return tokenString
+}
+
+func VerifyUnsubscribeToken(tokenStr string) (string, error) {
+	claims := &jwt.RegisteredClaims{}
+	token, err := jwt.ParseWithClaims(tokenStr, claims, func(token *jwt.Token) (interface{}, error) {
+		return UNSUBSCRIBE_SECRET, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(unsubscribeAudience), jwt.WithIssuedAt(), jwt.WithExpirationRequired())
+	if err != nil || !token.Valid || claims.Subject == "" {
+		return "", ErrInvalidUnsubscribeToken
+	}
+	return claims.Subject, nil
+}